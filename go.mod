@@ -1,7 +1,7 @@
 module github.com/c2devel/aws-ebs-csi-driver
 
 require (
-	github.com/aws/aws-sdk-go v1.23.21
+	github.com/aws/aws-sdk-go v1.55.8
 	github.com/container-storage-interface/spec v1.2.0
 	github.com/golang/mock v1.4.3
 	github.com/golang/protobuf v1.3.3
@@ -10,6 +10,7 @@ require (
 	github.com/kubernetes-sigs/aws-ebs-csi-driver v0.5.0
 	github.com/onsi/ginkgo v1.10.2
 	github.com/onsi/gomega v1.7.0
+	github.com/prometheus/client_golang v1.0.0
 	google.golang.org/grpc v1.26.0
 	k8s.io/api v0.17.3
 	k8s.io/apimachinery v0.17.3
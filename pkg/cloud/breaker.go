@@ -0,0 +1,175 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"k8s.io/klog"
+)
+
+// DefaultCircuitBreakerCooldown is how long an open circuit breaker fails
+// the requests fast when no cooldown is set.
+const DefaultCircuitBreakerCooldown = 30 * time.Second
+
+// circuitBreaker fails fast the requests of an AWS API operation after
+// consecutive failures of that operation. Once the cooldown is over, a single
+// request is let through: the breaker closes if it succeeds, and opens for
+// another cooldown otherwise.
+type circuitBreaker struct {
+	mux        sync.Mutex
+	threshold  int
+	cooldown   time.Duration
+	operations map[string]*breakerState
+
+	// now is overridden in unit tests
+	now func() time.Time
+}
+
+type breakerState struct {
+	failures int
+	openedAt time.Time
+	// probing is set while the request let through after the cooldown is in
+	// flight
+	probing bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold:  threshold,
+		cooldown:   cooldown,
+		operations: make(map[string]*breakerState),
+		now:        time.Now,
+	}
+}
+
+// validateHandler returns a request handler failing the request with
+// ErrCircuitOpen, before it is sent, while the breaker of its operation is
+// open. It is meant for the Validate handler list of the session.
+func (b *circuitBreaker) validateHandler() request.NamedHandler {
+	return request.NamedHandler{
+		Name: "ebscsi.circuitbreaker.validate",
+		Fn: func(r *request.Request) {
+			if !b.allow(r.Operation.Name) {
+				r.Error = ErrCircuitOpen
+			}
+		},
+	}
+}
+
+// completeHandler returns a request handler recording the outcome of the
+// request, once all its attempts are done. It is meant for the Complete
+// handler list of the session.
+func (b *circuitBreaker) completeHandler() request.NamedHandler {
+	return request.NamedHandler{
+		Name: "ebscsi.circuitbreaker.complete",
+		Fn: func(r *request.Request) {
+			if r.Error == ErrCircuitOpen {
+				return
+			}
+			b.record(r.Operation.Name, isBreakerFailure(r.Error))
+		},
+	}
+}
+
+// allow reports whether a request of the operation may be sent.
+func (b *circuitBreaker) allow(operation string) bool {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	state, ok := b.operations[operation]
+	if !ok || state.failures < b.threshold {
+		return true
+	}
+	if state.probing || b.now().Sub(state.openedAt) < b.cooldown {
+		return false
+	}
+
+	klog.V(4).Infof("Circuit breaker of %s half-open, letting a request through", operation)
+	state.probing = true
+	return true
+}
+
+func (b *circuitBreaker) record(operation string, failed bool) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	state, ok := b.operations[operation]
+	if !ok {
+		state = &breakerState{}
+		b.operations[operation] = state
+	}
+	state.probing = false
+
+	if !failed {
+		state.failures = 0
+		return
+	}
+
+	state.failures++
+	if state.failures >= b.threshold {
+		if state.failures == b.threshold {
+			klog.Warningf("Circuit breaker of %s open after %d consecutive failures", operation, state.failures)
+		}
+		state.openedAt = b.now()
+	}
+}
+
+// isBreakerFailure returns whether the error of a request counts as a failure
+// of the operation. Client errors about the request itself, e.g. a volume not
+// found or invalid parameters, don't, unlike the throttling, authentication,
+// server and connection errors. Neither do the requests canceled or timed out
+// by their caller's context, which say nothing about the operation.
+func isBreakerFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	if isContextError(err) {
+		return false
+	}
+	if aerr, ok := err.(awserr.Error); ok {
+		if aerr.Code() == request.InvalidParameterErrCode || aerr.Code() == request.CanceledErrorCode {
+			return false
+		}
+		if isContextError(aerr.OrigErr()) {
+			return false
+		}
+	}
+	if request.IsErrorThrottle(err) {
+		return true
+	}
+	if reqErr, ok := err.(awserr.RequestFailure); ok {
+		status := reqErr.StatusCode()
+		if status == http.StatusUnauthorized || status == http.StatusForbidden {
+			return true
+		}
+		return status < http.StatusBadRequest || status >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// isContextError returns whether the error is, or wraps, the error of a
+// canceled or expired context.
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	breaker := newCircuitBreaker(3, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	validate := breaker.validateHandler()
+	complete := breaker.completeHandler()
+	send := func(operation string, err error) error {
+		r := newTestRequest(operation, nil)
+		validate.Fn(r)
+		if r.Error == nil {
+			r.Error = err
+		}
+		complete.Fn(r)
+		return r.Error
+	}
+
+	authErr := awserr.NewRequestFailure(awserr.New("AuthFailure", "", nil), 401, "")
+	notFoundErr := awserr.NewRequestFailure(awserr.New("InvalidVolume.NotFound", "", nil), 400, "")
+
+	// Client errors and interrupted failure streaks don't open the breaker
+	for _, err := range []error{authErr, authErr, nil, authErr, authErr, notFoundErr, notFoundErr, notFoundErr} {
+		send("DescribeVolumes", err)
+	}
+	if err := send("DescribeVolumes", nil); err != nil {
+		t.Fatalf("expected closed breaker, got: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := send("DescribeVolumes", authErr); err != authErr {
+			t.Fatalf("expected %v, got: %v", authErr, err)
+		}
+	}
+	if err := send("DescribeVolumes", nil); err != ErrCircuitOpen {
+		t.Fatalf("expected %v, got: %v", ErrCircuitOpen, err)
+	}
+	// The breakers are per operation
+	if err := send("DescribeInstances", nil); err != nil {
+		t.Fatalf("expected closed breaker of another operation, got: %v", err)
+	}
+
+	// After the cooldown, a single request is let through, and fails again
+	now = now.Add(time.Minute)
+	r := newTestRequest("DescribeVolumes", nil)
+	validate.Fn(r)
+	if r.Error != nil {
+		t.Fatalf("expected half-open breaker to let a request through, got: %v", r.Error)
+	}
+	if err := send("DescribeVolumes", nil); err != ErrCircuitOpen {
+		t.Fatalf("expected %v while probing, got: %v", ErrCircuitOpen, err)
+	}
+	r.Error = fmt.Errorf("connection reset")
+	complete.Fn(r)
+	if err := send("DescribeVolumes", nil); err != ErrCircuitOpen {
+		t.Fatalf("expected %v after the probe failed, got: %v", ErrCircuitOpen, err)
+	}
+
+	// After another cooldown, a successful request closes the breaker
+	now = now.Add(time.Minute)
+	if err := send("DescribeVolumes", nil); err != nil {
+		t.Fatalf("expected half-open breaker to let a request through, got: %v", err)
+	}
+	if err := send("DescribeVolumes", nil); err != nil {
+		t.Fatalf("expected closed breaker, got: %v", err)
+	}
+}
+
+func TestIsBreakerFailure(t *testing.T) {
+	testCases := []struct {
+		name       string
+		err        error
+		expFailure bool
+	}{
+		{
+			name: "success",
+		},
+		{
+			name: "not found",
+			err:  awserr.NewRequestFailure(awserr.New("InvalidVolume.NotFound", "", nil), 400, ""),
+		},
+		{
+			name: "invalid parameters",
+			err:  request.ErrInvalidParams{Context: "CreateVolumeInput"},
+		},
+		{
+			name:       "throttled",
+			err:        awserr.NewRequestFailure(awserr.New("RequestLimitExceeded", "", nil), 400, ""),
+			expFailure: true,
+		},
+		{
+			name:       "unauthorized",
+			err:        awserr.NewRequestFailure(awserr.New("UnauthorizedOperation", "", nil), 403, ""),
+			expFailure: true,
+		},
+		{
+			name:       "server error",
+			err:        awserr.NewRequestFailure(awserr.New("InternalError", "", nil), 500, ""),
+			expFailure: true,
+		},
+		{
+			name:       "connection error",
+			err:        fmt.Errorf("connection refused"),
+			expFailure: true,
+		},
+		{
+			name: "request canceled",
+			err:  awserr.New(request.CanceledErrorCode, "request context canceled", context.Canceled),
+		},
+		{
+			name: "context canceled",
+			err:  context.Canceled,
+		},
+		{
+			name: "context deadline exceeded",
+			err:  fmt.Errorf("could not describe volumes: %w", context.DeadlineExceeded),
+		},
+		{
+			name: "send error of an expired context",
+			err:  awserr.New(request.ErrCodeRequestError, "send request failed", context.DeadlineExceeded),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if failure := isBreakerFailure(tc.err); failure != tc.expFailure {
+				t.Fatalf("expected failure %t, got %t", tc.expFailure, failure)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// DefaultVolumeCacheTTL is how long a described volume is kept in the
+// volume cache when no TTL is configured
+const DefaultVolumeCacheTTL = 5 * time.Second
+
+// volumeCache keeps recently described volumes, keyed by volume ID, to save
+// DescribeVolumes calls during attach/detach/resize flows.
+// A nil *volumeCache is valid and caches nothing, which is the default.
+type volumeCache struct {
+	mux     sync.Mutex
+	ttl     time.Duration
+	entries map[string]volumeCacheEntry
+
+	// nextSweep is when set drops the expired entries next, at most once per
+	// TTL, so that the volumes never read again don't pile up
+	nextSweep time.Time
+
+	// now is overridden in unit tests
+	now func() time.Time
+}
+
+type volumeCacheEntry struct {
+	volume  *ec2.Volume
+	expires time.Time
+}
+
+func newVolumeCache(ttl time.Duration) *volumeCache {
+	return &volumeCache{
+		ttl:     ttl,
+		entries: make(map[string]volumeCacheEntry),
+		now:     time.Now,
+	}
+}
+
+// get returns the cached volume, if present and not expired.
+func (vc *volumeCache) get(volumeID string) (*ec2.Volume, bool) {
+	if vc == nil {
+		return nil, false
+	}
+
+	vc.mux.Lock()
+	defer vc.mux.Unlock()
+
+	entry, ok := vc.entries[volumeID]
+	if !ok {
+		return nil, false
+	}
+	if !vc.now().Before(entry.expires) {
+		delete(vc.entries, volumeID)
+		return nil, false
+	}
+	return entry.volume, true
+}
+
+func (vc *volumeCache) set(volume *ec2.Volume) {
+	if vc == nil || volume == nil {
+		return
+	}
+
+	vc.mux.Lock()
+	defer vc.mux.Unlock()
+
+	now := vc.now()
+	if !now.Before(vc.nextSweep) {
+		for volumeID, entry := range vc.entries {
+			if !now.Before(entry.expires) {
+				delete(vc.entries, volumeID)
+			}
+		}
+		vc.nextSweep = now.Add(vc.ttl)
+	}
+
+	vc.entries[aws.StringValue(volume.VolumeId)] = volumeCacheEntry{
+		volume:  volume,
+		expires: now.Add(vc.ttl),
+	}
+}
+
+// invalidate drops the cached volume. It must be called whenever the volume
+// is modified, so that the next read goes to EC2.
+func (vc *volumeCache) invalidate(volumeID string) {
+	if vc == nil {
+		return
+	}
+
+	vc.mux.Lock()
+	defer vc.mux.Unlock()
+
+	delete(vc.entries, volumeID)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	dm "github.com/c2devel/aws-ebs-csi-driver/pkg/cloud/devicemanager"
+	"github.com/c2devel/aws-ebs-csi-driver/pkg/cloud/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestVolumeCache(t *testing.T) {
+	now := time.Now()
+	vc := newVolumeCache(5 * time.Second)
+	vc.now = func() time.Time { return now }
+
+	vol := &ec2.Volume{VolumeId: aws.String("vol-000000a8")}
+	vc.set(vol)
+
+	if cached, ok := vc.get("vol-000000a8"); !ok || cached != vol {
+		t.Fatalf("expected cached volume, got %v", cached)
+	}
+
+	now = now.Add(5 * time.Second)
+	if _, ok := vc.get("vol-000000a8"); ok {
+		t.Fatal("expected expired volume not to be returned")
+	}
+
+	vc.set(vol)
+	vc.invalidate("vol-000000a8")
+	if _, ok := vc.get("vol-000000a8"); ok {
+		t.Fatal("expected invalidated volume not to be returned")
+	}
+
+	// A nil cache is disabled and caches nothing
+	var disabled *volumeCache
+	disabled.set(vol)
+	disabled.invalidate("vol-000000a8")
+	if _, ok := disabled.get("vol-000000a8"); ok {
+		t.Fatal("expected disabled cache not to return anything")
+	}
+}
+
+func TestVolumeCacheSweep(t *testing.T) {
+	now := time.Now()
+	vc := newVolumeCache(5 * time.Second)
+	vc.now = func() time.Time { return now }
+
+	vc.set(&ec2.Volume{VolumeId: aws.String("vol-000000a8")})
+	vc.set(&ec2.Volume{VolumeId: aws.String("vol-000000a9")})
+
+	// The volumes never read again are dropped by a later set once expired
+	now = now.Add(5 * time.Second)
+	vc.set(&ec2.Volume{VolumeId: aws.String("vol-000000aa")})
+	if len(vc.entries) != 1 {
+		t.Fatalf("expected 1 cached volume, got %d", len(vc.entries))
+	}
+	if _, ok := vc.entries["vol-000000aa"]; !ok {
+		t.Fatal("expected the volume just set to be cached")
+	}
+}
+
+func TestGetDiskByIDVolumeCache(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockEC2 := mocks.NewMockEC2(mockCtrl)
+	c := &cloud{
+		region:      "test-region",
+		dm:          dm.NewDeviceManager(),
+		ec2:         mockEC2,
+		volumeCache: newVolumeCache(time.Minute),
+	}
+
+	vol := &ec2.Volume{
+		VolumeId:         aws.String("vol-000000a8"),
+		Size:             aws.Int64(1),
+		AvailabilityZone: aws.String(expZone),
+	}
+
+	ctx := context.Background()
+	gomock.InOrder(
+		// The second lookup is served from the cache
+		mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{vol}}, nil).Times(1),
+		mockEC2.EXPECT().DeleteVolumeWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DeleteVolumeOutput{}, nil),
+		// Deleting the volume invalidates it
+		mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{}, nil),
+	)
+
+	for i := 0; i < 2; i++ {
+		disk, err := c.GetDiskByID(ctx, "vol-000000a8")
+		if err != nil {
+			t.Fatalf("GetDiskByID() failed: expected no error, got: %v", err)
+		}
+		if disk.VolumeID != "vol-000000a8" {
+			t.Fatalf("GetDiskByID() failed: expected volume %q, got %q", "vol-000000a8", disk.VolumeID)
+		}
+	}
+
+	if _, err := c.DeleteDisk(ctx, "vol-000000a8"); err != nil {
+		t.Fatalf("DeleteDisk() failed: expected no error, got: %v", err)
+	}
+
+	if _, err := c.GetDiskByID(ctx, "vol-000000a8"); err != ErrNotFound {
+		t.Fatalf("GetDiskByID() failed: expected %v, got: %v", ErrNotFound, err)
+	}
+
+	mockCtrl.Finish()
+}
@@ -18,20 +18,33 @@ package cloud
 
 import (
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"net/url"
 	"os"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/corehandlers"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ebs"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/sts"
 	dm "github.com/c2devel/aws-ebs-csi-driver/pkg/cloud/devicemanager"
 	"github.com/c2devel/aws-ebs-csi-driver/pkg/util"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -50,6 +63,9 @@ const (
 	VolumeTypeST2 = "st2"
 	// VolumeTypeStandard represents a previous type of  volume.
 	VolumeTypeStandard = "standard"
+	// VolumeTypeAuto makes CreateDisk pick the volume type from the volume
+	// size: gp2, or st2 from the auto volume type threshold on.
+	VolumeTypeAuto = "auto"
 )
 
 var (
@@ -61,6 +77,14 @@ var (
 		VolumeTypeST2,
 		VolumeTypeStandard,
 	}
+
+	// DefaultVolumeTypeAliases maps the volume type names commonly used in
+	// storage classes to volume types. They can be overridden with the
+	// WithVolumeTypeAliases option.
+	DefaultVolumeTypeAliases = map[string]string{
+		"ssd": VolumeTypeGP2,
+		"hdd": VolumeTypeST2,
+	}
 )
 
 // AWS provisioning limits.
@@ -72,6 +96,18 @@ const (
 	MinTotalIOPS = 100
 	// MaxTotalIOPS represents the maximum Input Output per second.
 	MaxTotalIOPS = 20000
+	// MaxIO2BlockExpressIOPS represents the maximum Input Output per second of an io2 Block Express volume.
+	MaxIO2BlockExpressIOPS = 256000
+	// MaxIO1IOPSPerGB represents the maximum ratio of Input Output per second to GiB of an io1 volume.
+	MaxIO1IOPSPerGB = 50
+	// MaxIO2IOPSPerGB represents the maximum ratio of Input Output per second to GiB of an io2 volume.
+	MaxIO2IOPSPerGB = 500
+	// MaxIO2BlockExpressIOPSPerGB represents the maximum ratio of Input Output per second to GiB of an io2 Block Express volume.
+	MaxIO2BlockExpressIOPSPerGB = 1000
+	// MinVolumeInitializationRate represents the minimum initialization rate in MiB/s of a volume restored from a snapshot.
+	MinVolumeInitializationRate = 100
+	// MaxVolumeInitializationRate represents the maximum initialization rate in MiB/s of a volume restored from a snapshot.
+	MaxVolumeInitializationRate = 300
 	// MaxNumTagsPerResource represents the maximum number of tags per AWS resource.
 	MaxNumTagsPerResource = 50
 	// MaxTagKeyLength represents the maximum key length for a tag.
@@ -81,6 +117,16 @@ const (
 )
 
 // Defaults
+const (
+	// DefaultAutoVolumeTypeThreshold is the size from which the volumes of
+	// type auto are created as st2 volumes, when no threshold is configured.
+	DefaultAutoVolumeTypeThreshold int64 = 1024 * util.GiB
+)
+const (
+	// DefaultDeleteSnapshotsConcurrency is the number of snapshots deleted at
+	// once by DeleteSnapshots when no concurrency is given.
+	DefaultDeleteSnapshotsConcurrency = 5
+)
 const (
 	// DefaultVolumeSize represents the default volume size.
 	DefaultVolumeSize int64 = 100 * util.GiB
@@ -94,6 +140,9 @@ const (
 	VolumeNameTagKey = "CSIVolumeName"
 	// SnapshotNameTagKey is the key value that refers to the snapshot's name.
 	SnapshotNameTagKey = "CSIVolumeSnapshotName"
+	// SnapshotSourceRegionTagKey is the key value that refers to the region
+	// the data of the snapshot originates from.
+	SnapshotSourceRegionTagKey = "CSISnapshotSourceRegion"
 	// KubernetesTagKeyPrefix is the prefix of the key value that is reserved for Kubernetes.
 	KubernetesTagKeyPrefix = "kubernetes.io"
 	// AWSTagKeyPrefix is the prefix of the key value that is reserved for AWS.
@@ -115,12 +164,47 @@ var (
 	// ErrAlreadyExists is returned when a resource is already existent.
 	ErrAlreadyExists = errors.New("Resource already exists")
 
+	// ErrSnapshotInUse is returned when a snapshot can't be deleted because it
+	// is in use, e.g. by a volume being created from it or by an AMI.
+	ErrSnapshotInUse = errors.New("Snapshot is in use")
+
+	// ErrModificationRateLimited is returned when a volume can't be modified
+	// because it was already modified recently. AWS allows one modification
+	// per volume every 6 hours.
+	ErrModificationRateLimited = errors.New("Volume modification rate exceeded")
+
+	// ErrCircuitOpen is returned when an AWS API operation failed too many
+	// times in a row, and isn't called again until its circuit breaker
+	// cooldown is over.
+	ErrCircuitOpen = errors.New("Circuit breaker is open")
+
+	// ErrIncorrectVolumeState is returned when a volume can't be modified
+	// because of its state, e.g. while it is being created or deleted.
+	ErrIncorrectVolumeState = errors.New("Volume is in an incorrect state")
+
+	// ErrIdempotentParameterMismatch is returned when a volume was already
+	// created with the same client token but different parameters.
+	ErrIdempotentParameterMismatch = errors.New("Parameters on this idempotent request are inconsistent with parameters used in previous request(s)")
+
+	// ErrSnapshotFailed is returned when a snapshot waited for ends up in the
+	// error state instead of completed.
+	ErrSnapshotFailed = errors.New("Snapshot is in error state")
+
 	// ErrMultiSnapshots is returned when multiple snapshots are found
 	// with the same ID
 	ErrMultiSnapshots = errors.New("Multiple snapshots with the same name found")
 
 	// ErrInvalidMaxResults is returned when a MaxResults pagination parameter is between 1 and 4
 	ErrInvalidMaxResults = errors.New("MaxResults parameter must be 0 or greater than or equal to 5")
+
+	// ErrDiskSmallerThanSnapshot is returned when a disk is requested from a
+	// snapshot with a size smaller than the snapshot's source volume.
+	ErrDiskSmallerThanSnapshot = errors.New("Requested disk size is smaller than the snapshot size")
+
+	// ErrCannotShrinkVolume is returned when a volume is requested to be
+	// resized to a size smaller than its current one, as EBS volumes can
+	// only grow.
+	ErrCannotShrinkVolume = errors.New("Volume can't be shrunk")
 )
 
 // Disk represents a EBS volume
@@ -129,6 +213,19 @@ type Disk struct {
 	CapacityGiB      int64
 	AvailabilityZone string
 	SnapshotID       string
+	// KmsKeyID is the ARN of the key the volume is encrypted with, empty if
+	// the volume isn't encrypted.
+	KmsKeyID string
+	// IOPS is the performance EC2 provisioned the volume with, which may
+	// differ from the requested one, 0 if unknown.
+	IOPS int64
+}
+
+// VolumeAttachment represents an attachment of an EBS volume to an instance
+type VolumeAttachment struct {
+	InstanceID string
+	Device     string
+	State      string
 }
 
 // DiskOptions represents parameters to create an EBS volume
@@ -143,6 +240,35 @@ type DiskOptions struct {
 	// example: arn:aws:kms:us-east-1:012345678910:key/abcd1234-a123-456a-a12b-a123b4cd56ef
 	KmsKeyID   string
 	SnapshotID string
+	// MultiAttachEnabled enables the volume to be attached to several
+	// instances at once. Only supported by the io1 and io2 volume types.
+	MultiAttachEnabled bool
+	// OutpostArn is the ARN of the AWS Outpost to create the volume on.
+	// AvailabilityZone must be set to the Outpost's zone when it is used.
+	// example: arn:aws:outposts:us-west-2:012345678910:outpost/op-0123456789abcdef0
+	OutpostArn string
+	// BlockExpress raises the IOPS ceiling of io2 volumes to the one of
+	// io2 Block Express. It is ignored for the other volume types.
+	BlockExpress bool
+	// VolumeInitializationRate is the rate in MiB/s at which the volume
+	// restored from SnapshotID is initialized, zero for the default rate. It
+	// requires SnapshotID.
+	VolumeInitializationRate int64
+	// ClientToken makes the creation idempotent: EC2 returns the volume
+	// created earlier with the same token instead of creating another one.
+	// Default to a token derived from the volume name.
+	ClientToken string
+}
+
+// ModifyOptions represents the changes of a volume modification. The zero
+// fields are left unchanged.
+type ModifyOptions struct {
+	// SizeBytes is the new size of the volume, rounded up to whole GiB. It
+	// can't be smaller than the current size.
+	SizeBytes int64
+	// IOPS is the new provisioned IOPS of the volume. Only supported by the
+	// io1 and io2 volume types.
+	IOPS int64
 }
 
 // Snapshot represents an EBS volume snapshot
@@ -152,6 +278,23 @@ type Snapshot struct {
 	Size           int64
 	CreationTime   time.Time
 	ReadyToUse     bool
+	// Progress is the completion percentage of the snapshot, 0 if unknown.
+	Progress int
+	// SourceRegion is the region the data of the snapshot originates from,
+	// empty if unknown. It is read from the SnapshotSourceRegionTagKey tag,
+	// or else from the description EC2 gives to the copied snapshots.
+	SourceRegion string
+	// State is the raw EC2 state of the snapshot, like "pending" or "completed".
+	State string
+}
+
+// IsError returns true if the snapshot failed and will never be ready to use.
+func (s *Snapshot) IsError() bool {
+	switch s.State {
+	case ec2.SnapshotStateError, "recoverable-error":
+		return true
+	}
+	return false
 }
 
 // ListSnapshotsResponse is the container for our snapshots along with a pagination token to pass back to the caller
@@ -160,9 +303,96 @@ type ListSnapshotsResponse struct {
 	NextToken string
 }
 
+// ListVolumesResponse is the container for our volumes along with a pagination token to pass back to the caller
+type ListVolumesResponse struct {
+	Disks     []*Disk
+	NextToken string
+}
+
+// InstanceTypeInfo represents the EBS related capabilities of an instance type,
+// as reported by DescribeInstanceTypes
+type InstanceTypeInfo struct {
+	InstanceType string
+	// Hypervisor is "nitro" or "xen". On Nitro instances, EBS volumes share
+	// the attachment limit with the network interfaces.
+	Hypervisor string
+	// MaxNetworkInterfaces is the maximum number of network interfaces
+	MaxNetworkInterfaces int64
+	// EBSOptimizedSupport is "unsupported", "supported" or "default"
+	EBSOptimizedSupport string
+	// NVMeSupport is "unsupported", "supported" or "required"
+	NVMeSupport string
+	// Maximum EBS performance when EBS-optimized, zero if not reported
+	MaxIOPS             int64
+	MaxBandwidthInMbps  int64
+	MaxThroughputInMBps float64
+}
+
+// ConfigDrift reports which settings of a volume differ from the desired
+// ones, along with their actual and desired values.
+type ConfigDrift struct {
+	VolumeTypeDrifted bool
+	ActualVolumeType  string
+	DesiredVolumeType string
+
+	// IOPS are only compared for the io1 and io2 volume types, the IOPS of
+	// the other types being derived from their size.
+	IOPSDrifted bool
+	ActualIOPS  int64
+	DesiredIOPS int64
+
+	SizeDrifted    bool
+	ActualSizeGiB  int64
+	DesiredSizeGiB int64
+}
+
+// Drifted reports whether any setting of the volume drifted.
+func (d *ConfigDrift) Drifted() bool {
+	return d.VolumeTypeDrifted || d.IOPSDrifted || d.SizeDrifted
+}
+
+// VolumeHealth represents the status checks of an EBS volume, as reported by
+// DescribeVolumeStatus
+type VolumeHealth struct {
+	VolumeID string
+	// Status is "ok", "impaired", "warning" or "insufficient-data"
+	Status string
+	Events []VolumeHealthEvent
+}
+
+// VolumeHealthEvent represents an event affecting the health of a volume,
+// e.g. "potential-data-inconsistency" when its IO is disabled
+type VolumeHealthEvent struct {
+	EventID     string
+	EventType   string
+	Description string
+	NotBefore   time.Time
+	NotAfter    time.Time
+}
+
+// Impaired reports whether EC2 found the volume degraded.
+func (h *VolumeHealth) Impaired() bool {
+	return h.Status == ec2.VolumeStatusInfoStatusImpaired
+}
+
 // SnapshotOptions represents parameters to create an EBS volume
 type SnapshotOptions struct {
 	Tags map[string]string
+	// SourceRegion records the region the data of the volume originates
+	// from, e.g. when it was restored from a snapshot copied from another
+	// region, in the SnapshotSourceRegionTagKey tag of the snapshot.
+	SourceRegion string
+}
+
+// ListSnapshotsOptions represents parameters to list snapshots. The filters
+// are combined, a snapshot must match all of them to be listed.
+type ListSnapshotsOptions struct {
+	// VolumeID lists only the snapshots of the volume, if set
+	VolumeID string
+	// Tags lists only the snapshots carrying all these tags
+	Tags       map[string]string
+	MaxResults int64
+	NextToken  string
 }
 
 // ec2ListSnapshotsResponse is a helper struct returned from the AWS API calling function to the main ListSnapshots function
@@ -181,218 +411,151 @@ type EC2 interface {
 	AttachVolumeWithContext(ctx aws.Context, input *ec2.AttachVolumeInput, opts ...request.Option) (*ec2.VolumeAttachment, error)
 	DescribeInstancesWithContext(ctx aws.Context, input *ec2.DescribeInstancesInput, opts ...request.Option) (*ec2.DescribeInstancesOutput, error)
 	CreateSnapshotWithContext(ctx aws.Context, input *ec2.CreateSnapshotInput, opts ...request.Option) (*ec2.Snapshot, error)
+	CopySnapshotWithContext(ctx aws.Context, input *ec2.CopySnapshotInput, opts ...request.Option) (*ec2.CopySnapshotOutput, error)
 	DeleteSnapshotWithContext(ctx aws.Context, input *ec2.DeleteSnapshotInput, opts ...request.Option) (*ec2.DeleteSnapshotOutput, error)
 	DescribeSnapshotsWithContext(ctx aws.Context, input *ec2.DescribeSnapshotsInput, opts ...request.Option) (*ec2.DescribeSnapshotsOutput, error)
 	ModifyVolumeWithContext(ctx aws.Context, input *ec2.ModifyVolumeInput, opts ...request.Option) (*ec2.ModifyVolumeOutput, error)
 	DescribeVolumesModificationsWithContext(ctx aws.Context, input *ec2.DescribeVolumesModificationsInput, opts ...request.Option) (*ec2.DescribeVolumesModificationsOutput, error)
+	DescribeVolumeStatusWithContext(ctx aws.Context, input *ec2.DescribeVolumeStatusInput, opts ...request.Option) (*ec2.DescribeVolumeStatusOutput, error)
 	DescribeAvailabilityZonesWithContext(ctx aws.Context, input *ec2.DescribeAvailabilityZonesInput, opts ...request.Option) (*ec2.DescribeAvailabilityZonesOutput, error)
+	DescribeInstanceTypesWithContext(ctx aws.Context, input *ec2.DescribeInstanceTypesInput, opts ...request.Option) (*ec2.DescribeInstanceTypesOutput, error)
+	GetEbsEncryptionByDefaultWithContext(ctx aws.Context, input *ec2.GetEbsEncryptionByDefaultInput, opts ...request.Option) (*ec2.GetEbsEncryptionByDefaultOutput, error)
+	GetEbsDefaultKmsKeyIdWithContext(ctx aws.Context, input *ec2.GetEbsDefaultKmsKeyIdInput, opts ...request.Option) (*ec2.GetEbsDefaultKmsKeyIdOutput, error)
+	CreateTagsWithContext(ctx aws.Context, input *ec2.CreateTagsInput, opts ...request.Option) (*ec2.CreateTagsOutput, error)
+	DeleteTagsWithContext(ctx aws.Context, input *ec2.DeleteTagsInput, opts ...request.Option) (*ec2.DeleteTagsOutput, error)
+}
+
+// EBS abstracts the EBS direct APIs client to facilitate its mocking.
+// See https://docs.aws.amazon.com/sdk-for-go/api/service/ebs/ for details
+type EBS interface {
+	ListSnapshotBlocksWithContext(ctx aws.Context, input *ebs.ListSnapshotBlocksInput, opts ...request.Option) (*ebs.ListSnapshotBlocksOutput, error)
 }
 
 type Cloud interface {
 	CreateDisk(ctx context.Context, volumeName string, diskOptions *DiskOptions) (disk *Disk, err error)
 	DeleteDisk(ctx context.Context, volumeID string) (success bool, err error)
 	AttachDisk(ctx context.Context, volumeID string, nodeID string) (devicePath string, err error)
+	WillBeEncrypted(ctx context.Context, diskOptions *DiskOptions) (encrypted bool, kmsKeyID string, err error)
+	CreateAndAttachDisk(ctx context.Context, volumeName string, nodeID string, diskOptions *DiskOptions) (disk *Disk, devicePath string, err error)
+	GetVolumeAttachments(ctx context.Context, volumeID string) (attachments []VolumeAttachment, err error)
+	GetExpectedDevicePath(ctx context.Context, volumeID string, nodeID string) (devicePath string, err error)
+	GetAvailabilityZonesForVolumeType(ctx context.Context, volumeType string) (zones []string, err error)
 	DetachDisk(ctx context.Context, volumeID string, nodeID string) (err error)
 	ResizeDisk(ctx context.Context, volumeID string, reqSize int64) (newSize int64, err error)
+	ModifyDisk(ctx context.Context, volumeID string, options *ModifyOptions) (newSize int64, err error)
+	ModifyDiskTags(ctx context.Context, volumeID string, addOrUpdate map[string]string, remove []string) (err error)
+	BatchReconcileVolumeTags(ctx context.Context, volumeTags map[string]map[string]string) (errs map[string]error, err error)
 	WaitForAttachmentState(ctx context.Context, volumeID, state string) error
 	GetDiskByName(ctx context.Context, name string, capacityBytes int64) (disk *Disk, err error)
 	GetDiskByID(ctx context.Context, volumeID string) (disk *Disk, err error)
+	VolumeExists(ctx context.Context, volumeID string) (exists bool, err error)
+	GetVolumeHealth(ctx context.Context, volumeID string) (health VolumeHealth, err error)
+	DetectStuckVolumes(ctx context.Context, stuckAfter time.Duration) (disks []*Disk, err error)
+	CheckVolumeConfigDrift(ctx context.Context, volumeID string, desired *DiskOptions) (drift *ConfigDrift, err error)
+	FindVolumesAttachedToDeadNodes(ctx context.Context, liveNodeIDs map[string]bool) (disks []*Disk, err error)
+	ListDisksByTag(ctx context.Context, tagKey, tagValue string) (disks []*Disk, err error)
+	ListOrphanedDisks(ctx context.Context, clusterTagKey, clusterTagValue string) (disks []*Disk, err error)
+	ListVolumes(ctx context.Context, maxResults int64, nextToken string) (listVolumesResponse *ListVolumesResponse, err error)
+	GetInstanceTypeInfo(ctx context.Context, instanceType string) (info *InstanceTypeInfo, err error)
+	IsThrottled() bool
+	ThrottleRate() float64
 	IsExistInstance(ctx context.Context, nodeID string) (success bool)
+	GetInstanceType(ctx context.Context, nodeID string) (instanceType string, err error)
 	CreateSnapshot(ctx context.Context, volumeID string, snapshotOptions *SnapshotOptions) (snapshot *Snapshot, err error)
+	CreateEncryptedSnapshot(ctx context.Context, volumeID, kmsKeyID string, snapshotOptions *SnapshotOptions) (snapshot *Snapshot, err error)
 	DeleteSnapshot(ctx context.Context, snapshotID string) (success bool, err error)
+	DeleteSnapshots(ctx context.Context, snapshotIDs []string, concurrency int) (errs map[string]error, err error)
+	ModifySnapshotTags(ctx context.Context, snapshotID string, addOrUpdate map[string]string, remove []string) (err error)
 	GetSnapshotByName(ctx context.Context, name string) (snapshot *Snapshot, err error)
+	GetNewestSnapshotByName(ctx context.Context, name string) (snapshot *Snapshot, err error)
 	GetSnapshotByID(ctx context.Context, snapshotID string) (snapshot *Snapshot, err error)
+	WaitForSnapshotCompleted(ctx context.Context, snapshotID string) (snapshot *Snapshot, err error)
+	GetSnapshotActualSize(ctx context.Context, snapshotID string) (sizeBytes int64, err error)
 	ListSnapshots(ctx context.Context, volumeID string, maxResults int64, nextToken string) (listSnapshotsResponse *ListSnapshotsResponse, err error)
+	ListSnapshotsWithOptions(ctx context.Context, options *ListSnapshotsOptions) (listSnapshotsResponse *ListSnapshotsResponse, err error)
+	ListSnapshotsOlderThan(ctx context.Context, cutoff time.Time, tagFilters map[string]string) (snapshots []*Snapshot, err error)
+	Close()
 }
 
+// cloud is safe for concurrent use by the controller and node goroutines: its
+// state shared between calls, i.e. the caches, the throttle tracker and the
+// device manager, guards itself.
 type cloud struct {
-	region string
-	ec2    EC2
-	dm     dm.DeviceManager
+	region   string
+	ec2      EC2
+	ebs      EBS
+	dm       dm.DeviceManager
+	options  CloudOptions
+	throttle *throttleTracker
+
+	// instanceTypes caches the *InstanceTypeInfo by instance type, which don't change
+	instanceTypes sync.Map
+
+	// attachLocks holds a *sync.Mutex by instance ID, serializing the
+	// attachments to each instance. Entries are never removed, there is one
+	// per node the driver ever attached a volume to.
+	attachLocks sync.Map
+
+	// volumeCache is nil unless enabled in the options
+	volumeCache *volumeCache
+
+	// zoneCounts is nil unless the zone balancing is enabled in the options
+	zoneCounts *zoneVolumeCounts
+
+	// rootCtx is canceled by Close, aborting the in-flight wait loops
+	rootCtx context.Context
+	cancel  context.CancelFunc
 }
 
 var _ Cloud = &cloud{}
 
-// AttachVolumeRequest generates a "aws/request.Request" representing the
-// client's request for the AttachVolume operation. The "output" return
-// value will be populated with the request's response once the request completes
-// successfully.
-//
-// Use "Send" method on the returned Request to send the API call to the service.
-// the "output" return value is not valid until after Send returns without error.
-//
-// See AttachVolume for more information on using the AttachVolume
-// API call, and error handling.
-//
-// This method is useful when you want to inject custom logic or configuration
-// into the SDK's request lifecycle. Such as custom headers, or retry logic.
-//
-//
-//    // Example sending a request using the AttachVolumeRequest method.
-//    req, resp := client.AttachVolumeRequest(params)
-//
-//    err := req.Send()
-//    if err == nil { // resp is now filled
-//        fmt.Println(resp)
-//    }
-//
-// See also, https://docs.aws.amazon.com/goto/WebAPI/ec2-2016-11-15/AttachVolume
-func AttachVolumeRequest(c *ec2.EC2, input *AttachVolumeInput) (req *request.Request, output *ec2.VolumeAttachment) {
-	op := &request.Operation{
-		Name:       "AttachVolume",
-		HTTPMethod: "POST",
-		HTTPPath:   "/",
-	}
-
-	if input == nil {
-		input = &AttachVolumeInput{}
-	}
-
-	output = &ec2.VolumeAttachment{}
-	req = c.NewRequest(op, input, output)
-	return
-}
-
-// AttachVolume API operation for Amazon Elastic Compute Cloud.
-//
-// Attaches an EBS volume to a running or stopped instance and exposes it to
-// the instance with the specified device name.
-//
-// Encrypted EBS volumes must be attached to instances that support Amazon EBS
-// encryption. For more information, see Amazon EBS Encryption (https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/EBSEncryption.html)
-// in the Amazon Elastic Compute Cloud User Guide.
-//
-// After you attach an EBS volume, you must make it available. For more information,
-// see Making an EBS Volume Available For Use (https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/ebs-using-volumes.html).
-//
-// If a volume has an AWS Marketplace product code:
-//
-//    * The volume can be attached only to a stopped instance.
-//
-//    * AWS Marketplace product codes are copied from the volume to the instance.
-//
-//    * You must be subscribed to the product.
-//
-//    * The instance type and operating system of the instance must support
-//    the product. For example, you can't detach a volume from a Windows instance
-//    and attach it to a Linux instance.
-//
-// For more information, see Attaching Amazon EBS Volumes (https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/ebs-attaching-volume.html)
-// in the Amazon Elastic Compute Cloud User Guide.
-//
-// Returns awserr.Error for service API and SDK errors. Use runtime type assertions
-// with awserr.Error's Code and Message methods to get detailed information about
-// the error.
-//
-// See the AWS API reference guide for Amazon Elastic Compute Cloud's
-// API operation AttachVolume for usage and error information.
-// See also, https://docs.aws.amazon.com/goto/WebAPI/ec2-2016-11-15/AttachVolume
-func AttachVolume(c *ec2.EC2, input *AttachVolumeInput) (*ec2.VolumeAttachment, error) {
-	req, out := AttachVolumeRequest(c, input)
-	return out, req.Send()
-}
-
-// AttachVolumeWithContext is the same as AttachVolume with the addition of
-// the ability to pass a context and additional request options.
-//
-// See AttachVolume for details on how to use this API operation.
-//
-// The context must be non-nil and will be used for request cancellation. If
-// the context is nil a panic will occur. In the future the SDK may create
-// sub-contexts for http.Requests. See https://golang.org/pkg/context/
-// for more information on using Contexts.
-func AttachVolumeWithContext(c *ec2.EC2, ctx aws.Context, input *AttachVolumeInput, opts ...request.Option) (*ec2.VolumeAttachment, error) {
-	req, out := AttachVolumeRequest(c, input)
-	req.SetContext(ctx)
-	req.ApplyOptions(opts...)
-	return out, req.Send()
-}
-
-// AttachVolumeInput is a type that Contains the parameters for AttachVolume.
-type AttachVolumeInput struct {
-	_ struct{} `type:"structure"`
-
-	// The device name (for example, /dev/sdh or xvdh).
-	//
-	// Device is a required field
-	Device *string `type:"string"`
-
-	// Checks whether you have the required permissions for the action, without
-	// actually making the request, and provides an error response. If you have
-	// the required permissions, the error response is DryRunOperation. Otherwise,
-	// it is UnauthorizedOperation.
-	DryRun *bool `locationName:"dryRun" type:"boolean"`
-
-	// The ID of the instance.
-	//
-	// InstanceId is a required field
-	InstanceId *string `type:"string" required:"true"`
-
-	// The ID of the EBS volume. The volume and instance must be within the same
-	// Availability Zone.
-	//
-	// VolumeId is a required field
-	VolumeId *string `type:"string" required:"true"`
-}
-
-// String returns the string representation
-func (s AttachVolumeInput) String() string {
-	return awsutil.Prettify(s)
-}
-
-// GoString returns the string representation
-func (s AttachVolumeInput) GoString() string {
-	return s.String()
-}
-
-// Validate inspects the fields of the type to determine if they are valid.
-func (s *AttachVolumeInput) Validate() error {
-	invalidParams := request.ErrInvalidParams{Context: "AttachVolumeInput"}
-
-	if s.InstanceId == nil {
-		invalidParams.Add(request.NewErrParamRequired("InstanceId"))
-	}
-	if s.VolumeId == nil {
-		invalidParams.Add(request.NewErrParamRequired("VolumeId"))
+// NewCloud returns a new instance of AWS cloud
+// It panics if session is invalid
+func NewCloud(region string, options ...func(*CloudOptions)) (Cloud, error) {
+	envUseFIPSEndpoint := os.Getenv("AWS_USE_FIPS_ENDPOINT")
+	useFIPSEndpoint := false
+	if envUseFIPSEndpoint != "" {
+		var err error
+		useFIPSEndpoint, err = strconv.ParseBool(envUseFIPSEndpoint)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to parse environment variable AWS_USE_FIPS_ENDPOINT: %w", err)
+		}
 	}
 
-	if invalidParams.Len() > 0 {
-		return invalidParams
+	cloudOptions := defaultCloudOptions()
+	cloudOptions.assumeRoleARN = os.Getenv("AWS_ASSUME_ROLE_ARN")
+	cloudOptions.assumeRoleExternalID = os.Getenv("AWS_ASSUME_ROLE_EXTERNAL_ID")
+	cloudOptions.useFIPSEndpoint = useFIPSEndpoint
+	for _, option := range options {
+		option(&cloudOptions)
 	}
-	return nil
-}
-
-// SetDevice sets the Device field's value.
-func (s *AttachVolumeInput) SetDevice(v string) *AttachVolumeInput {
-	s.Device = &v
-	return s
-}
 
-// SetDryRun sets the DryRun field's value.
-func (s *AttachVolumeInput) SetDryRun(v bool) *AttachVolumeInput {
-	s.DryRun = &v
-	return s
-}
-
-// SetInstanceId sets the InstanceId field's value.
-func (s *AttachVolumeInput) SetInstanceId(v string) *AttachVolumeInput {
-	s.InstanceId = &v
-	return s
-}
+	if err := ValidateCloudOptions(&cloudOptions); err != nil {
+		return nil, fmt.Errorf("Invalid cloud options: %w", err)
+	}
 
-// SetVolumeId sets the VolumeId field's value.
-func (s *AttachVolumeInput) SetVolumeId(v string) *AttachVolumeInput {
-	s.VolumeId = &v
-	return s
+	return newEC2Cloud(region, cloudOptions)
 }
 
-// NewCloud returns a new instance of AWS cloud
-// It panics if session is invalid
-func NewCloud(region string) (Cloud, error) {
-	return newEC2Cloud(region)
+// NewCloudWithEC2 returns a new instance of AWS cloud calling the given EC2
+// client, e.g. wrapped for instrumentation, and assigning the devices with the
+// given device manager. It has the default options of NewCloud, but the ones
+// read from the environment, and no EBS client, so GetSnapshotActualSize isn't
+// available.
+func NewCloudWithEC2(region string, ec2Client EC2, dm dm.DeviceManager) Cloud {
+	rootCtx, cancel := context.WithCancel(context.Background())
+	return &cloud{
+		region:   region,
+		dm:       dm,
+		ec2:      ec2Client,
+		options:  defaultCloudOptions(),
+		throttle: newThrottleTracker(throttleWindow),
+		rootCtx:  rootCtx,
+		cancel:   cancel,
+	}
 }
 
-func newEC2Cloud(region string) (Cloud, error) {
+func newEC2Cloud(region string, cloudOptions CloudOptions) (Cloud, error) {
 
 	var awsConfig *aws.Config
 
@@ -402,7 +565,7 @@ func newEC2Cloud(region string) (Cloud, error) {
 		var err error
 		isEndpointInsecure, err = strconv.ParseBool(envEndpointInsecure)
 		if err != nil {
-			return nil, fmt.Errorf("Unable to parse environment variable AWS_EC2_ENDPOINT_UNSECURE: %v", err)
+			return nil, fmt.Errorf("Unable to parse environment variable AWS_EC2_ENDPOINT_UNSECURE: %w", err)
 		}
 	}
 
@@ -424,36 +587,144 @@ func newEC2Cloud(region string) (Cloud, error) {
 		}
 	}
 
-	endpoint := os.Getenv("AWS_EC2_ENDPOINT")
-	if endpoint != "" {
-		awsConfig.Endpoint = aws.String(endpoint)
+	awsConfig.EndpointResolver = newEndpointResolver(customEndpointsFromEnv(), cloudOptions.endpointResolver)
+	// Always set, as the SDK would otherwise read AWS_USE_FIPS_ENDPOINT itself
+	awsConfig.UseFIPSEndpoint = endpoints.FIPSEndpointStateDisabled
+	if cloudOptions.useFIPSEndpoint {
+		awsConfig.UseFIPSEndpoint = endpoints.FIPSEndpointStateEnabled
 	}
 
-	return &cloud{
-		region: region,
-		dm:     dm.NewDeviceManager(),
-		ec2:    ec2.New(session.Must(session.NewSession(awsConfig))),
-	}, nil
+	request.WithRetryer(awsConfig, newRetryAfterRetryer())
+
+	throttle := newThrottleTracker(throttleWindow)
+
+	sess := session.Must(session.NewSession(awsConfig))
+	sess.Handlers.Complete.PushFrontNamed(metrics.handler())
+	sess.Handlers.CompleteAttempt.PushFrontNamed(throttle.handler())
+	if threshold := cloudOptions.circuitBreakerThreshold; threshold > 0 {
+		cooldown := cloudOptions.circuitBreakerCooldown
+		if cooldown == 0 {
+			cooldown = DefaultCircuitBreakerCooldown
+		}
+		breaker := newCircuitBreaker(threshold, cooldown)
+		sess.Handlers.Validate.PushFrontNamed(breaker.validateHandler())
+		sess.Handlers.Complete.PushFrontNamed(breaker.completeHandler())
+	}
+
+	clientConfig := aws.NewConfig()
+	if roleARN := cloudOptions.assumeRoleARN; roleARN != "" {
+		klog.V(2).Infof("Assuming role %q for EC2 API calls", roleARN)
+		clientConfig.Credentials = stscreds.NewCredentials(sess, roleARN, func(p *stscreds.AssumeRoleProvider) {
+			if cloudOptions.assumeRoleExternalID != "" {
+				p.ExternalID = aws.String(cloudOptions.assumeRoleExternalID)
+			}
+		})
+	}
+
+	rootCtx, cancel := context.WithCancel(context.Background())
+	c := &cloud{
+		region:   region,
+		dm:       dm.NewDeviceManager(),
+		ec2:      ec2.New(sess, clientConfig),
+		ebs:      ebs.New(sess, clientConfig),
+		options:  cloudOptions,
+		throttle: throttle,
+		rootCtx:  rootCtx,
+		cancel:   cancel,
+	}
+
+	if cloudOptions.volumeCacheEnabled {
+		ttl := cloudOptions.volumeCacheTTL
+		if ttl == 0 {
+			ttl = DefaultVolumeCacheTTL
+		}
+		c.volumeCache = newVolumeCache(ttl)
+	}
+
+	if cloudOptions.zoneBalancingEnabled {
+		ttl := cloudOptions.zoneBalancingTTL
+		if ttl == 0 {
+			ttl = DefaultZoneVolumeCountsTTL
+		}
+		c.zoneCounts = newZoneVolumeCounts(ttl)
+	}
+
+	return c, nil
+}
+
+// customEndpointsFromEnv returns the custom endpoints set in the environment,
+// keyed by service ID
+func customEndpointsFromEnv() map[string]string {
+	envs := map[string]string{
+		ec2.EndpointsID: "AWS_EC2_ENDPOINT",
+		sts.EndpointsID: "AWS_STS_ENDPOINT",
+	}
+
+	custom := map[string]string{}
+	for service, env := range envs {
+		if endpoint := os.Getenv(env); endpoint != "" {
+			custom[service] = endpoint
+		}
+	}
+	return custom
+}
+
+// newEndpointResolver returns a resolver using the custom endpoints for the
+// services they are set for, and the fallback resolver for the others. The
+// default AWS resolver is used if fallback is nil.
+func newEndpointResolver(custom map[string]string, fallback endpoints.Resolver) endpoints.Resolver {
+	if fallback == nil {
+		fallback = endpoints.DefaultResolver()
+	}
+
+	return endpoints.ResolverFunc(func(service, region string, opts ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
+		if endpoint, ok := custom[service]; ok {
+			return endpoints.ResolvedEndpoint{
+				URL:           endpoint,
+				SigningRegion: region,
+			}, nil
+		}
+		return fallback.EndpointFor(service, region, opts...)
+	})
+}
+
+// IsThrottled returns true when enough of the recent AWS API requests were
+// throttled that callers should slow down.
+func (c *cloud) IsThrottled() bool {
+	return c.ThrottleRate() > throttledRateThreshold
+}
+
+// ThrottleRate returns the share, between 0 and 1, of the AWS API request
+// attempts throttled over the last throttleWindow.
+func (c *cloud) ThrottleRate() float64 {
+	return c.throttle.rate()
 }
 
 func (c *cloud) CreateDisk(ctx context.Context, volumeName string, diskOptions *DiskOptions) (*Disk, error) {
-	var (
-		createType string
-		iops       int64
-	)
-	capacityGiB := util.BytesToGiB(diskOptions.CapacityBytes)
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	if volumeType, ok := c.volumeTypeAlias(diskOptions.VolumeType); ok {
+		options := *diskOptions
+		options.VolumeType = volumeType
+		diskOptions = &options
+	}
+	if diskOptions.VolumeType == VolumeTypeAuto {
+		options := *diskOptions
+		options.VolumeType = c.autoVolumeType(diskOptions.CapacityBytes)
+		diskOptions = &options
+	}
+
+	var createType string
+	capacityGiB := util.RoundUpGiB(diskOptions.CapacityBytes)
 
 	switch diskOptions.VolumeType {
 	case VolumeTypeGP2, VolumeTypeST2, VolumeTypeStandard:
 		createType = diskOptions.VolumeType
 	case VolumeTypeIO1, VolumeTypeIO2:
 		createType = diskOptions.VolumeType
-		iops = capacityGiB * int64(diskOptions.IOPSPerGB)
-		if iops < MinTotalIOPS {
-			iops = MinTotalIOPS
-		}
-		if iops > MaxTotalIOPS {
-			iops = MaxTotalIOPS
+		if limit := maxIOPSPerGB(diskOptions); diskOptions.IOPSPerGB > limit {
+			return nil, fmt.Errorf("IOPS per GiB %d of %s volume exceeds the limit of %d", diskOptions.IOPSPerGB, diskOptions.VolumeType, limit)
 		}
 	case "":
 		createType = DefaultVolumeType
@@ -461,196 +732,782 @@ func (c *cloud) CreateDisk(ctx context.Context, volumeName string, diskOptions *
 		return nil, fmt.Errorf("invalid AWS VolumeType %q", diskOptions.VolumeType)
 	}
 
-	var tags []*ec2.Tag
-	for key, value := range diskOptions.Tags {
-		copiedKey := key
-		copiedValue := value
-		tags = append(tags, &ec2.Tag{Key: &copiedKey, Value: &copiedValue})
+	iops := resolveIOPS(createType, capacityGiB, diskOptions)
+
+	if diskOptions.MultiAttachEnabled && createType != VolumeTypeIO1 && createType != VolumeTypeIO2 {
+		return nil, fmt.Errorf("multi-attach is only supported by %s and %s volumes, not %s", VolumeTypeIO1, VolumeTypeIO2, createType)
+	}
+
+	snapshotID := diskOptions.SnapshotID
+	if rate := diskOptions.VolumeInitializationRate; rate != 0 {
+		if len(snapshotID) == 0 {
+			return nil, fmt.Errorf("volume initialization rate is only supported for volumes restored from a snapshot")
+		}
+		if rate < MinVolumeInitializationRate || rate > MaxVolumeInitializationRate {
+			return nil, fmt.Errorf("volume initialization rate %d MiB/s is out of range [%d, %d]", rate, MinVolumeInitializationRate, MaxVolumeInitializationRate)
+		}
 	}
-	tagSpec := ec2.TagSpecification{
-		ResourceType: aws.String("volume"),
-		Tags:         tags,
+	if len(snapshotID) > 0 && !c.options.skipSnapshotPreChecks {
+		if err := c.checkSnapshotSize(ctx, snapshotID, capacityGiB); err != nil {
+			return nil, err
+		}
 	}
 
 	zone := diskOptions.AvailabilityZone
 	if zone == "" {
+		if len(diskOptions.OutpostArn) > 0 {
+			return nil, fmt.Errorf("availability zone must be provided to create a volume on outpost %q", diskOptions.OutpostArn)
+		}
+		if c.options.requireExplicitAZ {
+			return nil, fmt.Errorf("availability zone must be provided to create volume %q", volumeName)
+		}
 		klog.V(5).Infof("AZ is not provided. Using node AZ [%s]", zone)
 		var err error
-		zone, err = c.randomAvailabilityZone(ctx, c.region)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get availability zone %s", err)
+		if c.zoneCounts != nil {
+			zone, err = c.leastUsedAvailabilityZone(ctx)
+			if err != nil {
+				klog.Warningf("Could not count the volumes per availability zone, picking one at random: %v", err)
+			}
+		}
+		if zone == "" {
+			zone, err = c.randomAvailabilityZone(ctx, c.region)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get availability zone %w", err)
+			}
 		}
 	}
 
+	tags := c.withExtraTags(diskOptions.Tags)
+	if err := validateTags(tags); err != nil {
+		return nil, fmt.Errorf("invalid tags for volume %q: %w", volumeName, err)
+	}
+
 	request := &ec2.CreateVolumeInput{
 		AvailabilityZone:  aws.String(zone),
 		Size:              aws.Int64(capacityGiB),
 		VolumeType:        aws.String(createType),
-		TagSpecifications: []*ec2.TagSpecification{&tagSpec},
+		TagSpecifications: buildTagSpecifications(ec2.ResourceTypeVolume, tags),
 		Encrypted:         aws.Bool(diskOptions.Encrypted),
 	}
 	if len(diskOptions.KmsKeyID) > 0 {
 		request.KmsKeyId = aws.String(diskOptions.KmsKeyID)
 		request.Encrypted = aws.Bool(true)
 	}
+	if diskOptions.MultiAttachEnabled {
+		request.MultiAttachEnabled = aws.Bool(true)
+	}
 	if iops > 0 {
 		request.Iops = aws.Int64(iops)
 	}
-	snapshotID := diskOptions.SnapshotID
 	if len(snapshotID) > 0 {
 		request.SnapshotId = aws.String(snapshotID)
 	}
+	if len(diskOptions.OutpostArn) > 0 {
+		request.OutpostArn = aws.String(diskOptions.OutpostArn)
+	}
+	clientToken := diskOptions.ClientToken
+	if len(clientToken) == 0 {
+		clientToken = volumeClientToken(volumeName)
+	}
+	request.ClientToken = aws.String(clientToken)
+
+	var opts []request.Option
+	if rate := diskOptions.VolumeInitializationRate; rate != 0 {
+		opts = append(opts, withVolumeInitializationRate(rate))
+	}
 
-	response, err := c.ec2.CreateVolumeWithContext(ctx, request)
+	response, err := c.ec2.CreateVolumeWithContext(ctx, request, opts...)
 	if err != nil {
 		if isAWSErrorSnapshotNotFound(err) {
 			return nil, ErrNotFound
 		}
-		return nil, fmt.Errorf("could not create volume in EC2: %v", err)
+		if isAWSErrorIdempotentParameterMismatch(err) {
+			return nil, ErrIdempotentParameterMismatch
+		}
+		return nil, newCloudError(err, "could not create volume in EC2")
 	}
 
 	volumeID := aws.StringValue(response.VolumeId)
 	if len(volumeID) == 0 {
 		return nil, fmt.Errorf("volume ID was not returned by CreateVolume")
 	}
+	c.zoneCounts.increment(zone)
 
 	size := aws.Int64Value(response.Size)
 	if size == 0 {
 		return nil, fmt.Errorf("disk size was not returned by CreateVolume")
 	}
 
-	if err := c.waitForVolume(ctx, volumeID); err != nil {
-		return nil, fmt.Errorf("failed to get an available volume in EC2: %v", err)
+	volume, err := c.waitForVolume(ctx, volumeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get an available volume in EC2: %w", err)
+	}
+
+	// Some EC2 compatible clouds report the size of the snapshot rather than
+	// the requested one in the CreateVolume response of a restore, although
+	// the volume is grown to the requested size.
+	if describedSize := aws.Int64Value(volume.Size); len(snapshotID) > 0 && describedSize > 0 && describedSize != size {
+		klog.V(4).Infof("CreateVolume reported size %d GiB for volume %q restored from snapshot %q, which is %d GiB", size, volumeID, snapshotID, describedSize)
+		size = describedSize
+	}
+
+	provisionedIOPS := aws.Int64Value(response.Iops)
+	if provisionedIOPS == 0 {
+		provisionedIOPS = aws.Int64Value(volume.Iops)
+	}
+
+	return &Disk{CapacityGiB: size, VolumeID: volumeID, AvailabilityZone: zone, SnapshotID: snapshotID, IOPS: provisionedIOPS}, nil
+}
+
+// WillBeEncrypted returns whether a volume created with the options would be
+// encrypted, either as requested or because of the encryption by default of
+// the account, and the ID of the KMS key it would be encrypted with.
+func (c *cloud) WillBeEncrypted(ctx context.Context, diskOptions *DiskOptions) (bool, string, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	if len(diskOptions.KmsKeyID) > 0 {
+		return true, diskOptions.KmsKeyID, nil
+	}
+
+	if !diskOptions.Encrypted {
+		response, err := c.ec2.GetEbsEncryptionByDefaultWithContext(ctx, &ec2.GetEbsEncryptionByDefaultInput{})
+		if err != nil {
+			return false, "", fmt.Errorf("could not get EBS encryption by default: %w", err)
+		}
+		if !aws.BoolValue(response.EbsEncryptionByDefault) {
+			return false, "", nil
+		}
 	}
 
-	return &Disk{CapacityGiB: size, VolumeID: volumeID, AvailabilityZone: zone, SnapshotID: snapshotID}, nil
+	response, err := c.ec2.GetEbsDefaultKmsKeyIdWithContext(ctx, &ec2.GetEbsDefaultKmsKeyIdInput{})
+	if err != nil {
+		return false, "", fmt.Errorf("could not get EBS default KMS key: %w", err)
+	}
+	return true, aws.StringValue(response.KmsKeyId), nil
 }
 
+// DeleteDisk deletes the volume. Deleting a volume that no longer exists is
+// considered a success, as required for an idempotent CSI DeleteVolume.
 func (c *cloud) DeleteDisk(ctx context.Context, volumeID string) (bool, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	if err := validateVolumeID(volumeID); err != nil {
+		return false, err
+	}
+
 	request := &ec2.DeleteVolumeInput{VolumeId: &volumeID}
-	if _, err := c.ec2.DeleteVolumeWithContext(ctx, request); err != nil {
+	_, err := c.ec2.DeleteVolumeWithContext(ctx, request)
+	c.volumeCache.invalidate(volumeID)
+	if err != nil {
 		if isAWSErrorVolumeNotFound(err) {
-			return false, ErrNotFound
+			klog.V(5).Infof("DeleteDisk: volume %q not found, assuming it is already deleted", volumeID)
+			return true, nil
 		}
-		return false, fmt.Errorf("DeleteDisk could not delete volume: %v", err)
+		return false, newCloudError(err, "DeleteDisk could not delete volume")
 	}
 	return true, nil
 }
 
 func (c *cloud) AttachDisk(ctx context.Context, volumeID, nodeID string) (string, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	if err := validateVolumeID(volumeID); err != nil {
+		return "", err
+	}
+
 	instance, err := c.getInstance(ctx, nodeID)
 	if err != nil {
 		return "", err
 	}
 
+	// Nodes may register before their instance is fully running
+	if c.options.instanceRunningTimeout > 0 && instanceStateName(instance) == ec2.InstanceStateNamePending {
+		klog.V(4).Infof("Waiting for instance %q to be running before attaching volume %q", nodeID, volumeID)
+		instance, err = c.waitForInstanceRunning(ctx, nodeID)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// Attaching to an instance in any other state is doomed, don't wait for
+	// it to time out. An instance without a state is let through.
+	switch state := instanceStateName(instance); state {
+	case "", ec2.InstanceStateNameRunning, ec2.InstanceStateNameStopped:
+	default:
+		return "", fmt.Errorf("could not attach volume %q to node %q: instance is %s, not %s or %s", volumeID, nodeID, state, ec2.InstanceStateNameRunning, ec2.InstanceStateNameStopped)
+	}
+
+	// Concurrent attachments to the instance would otherwise race on the
+	// device bookkeeping, e.g. one releasing the in-flight device of the
+	// other before it is attached.
+	lock := c.attachLock(nodeID)
+	lock.Lock()
+	defer lock.Unlock()
+
 	device, err := c.dm.NewDevice(instance, volumeID)
 	if err != nil {
 		return "", err
 	}
 	defer device.Release(false)
 
+	// multiAttach is whether the volume can be attached to several instances
+	var multiAttach bool
+	// attachment is the one returned by AttachVolume, if sent
+	var attachment *ec2.VolumeAttachment
 	if !device.IsAlreadyAssigned {
-		request := &AttachVolumeInput{
+		request := &ec2.DescribeVolumesInput{
+			VolumeIds: []*string{
+				aws.String(volumeID),
+			},
+		}
+		volume, err := c.getVolume(ctx, request)
+		if err != nil {
+			return "", newCloudError(err, fmt.Sprintf("could not describe volume %q", volumeID))
+		}
+
+		// The volume may have been attached by a previous call that didn't
+		// get to report back (e.g. the controller restarted), so don't
+		// re-issue the attach in that case.
+		if isAttachedToNode(volume, nodeID) {
+			klog.V(5).Infof("Volume %q is already attached to instance %q", volumeID, nodeID)
+			return device.Path, nil
+		}
+		multiAttach = aws.BoolValue(volume.MultiAttachEnabled)
+
+		attachRequest := &ec2.AttachVolumeInput{
 			InstanceId: aws.String(nodeID),
 			VolumeId:   aws.String(volumeID),
 		}
 
-		resp, err := AttachVolumeWithContext(c.ec2.(*ec2.EC2), ctx, request)
-		if err != nil {
-			if awsErr, ok := err.(awserr.Error); ok {
-				if awsErr.Code() == "VolumeInUse" {
-					return "", ErrAlreadyExists
-				}
+		resp, err := c.attachVolume(ctx, attachRequest)
+		c.volumeCache.invalidate(volumeID)
+		switch {
+		case err == nil:
+			klog.V(5).Infof("AttachVolume volume=%q instance=%q request returned %v", volumeID, nodeID, resp)
+			attachment = resp
+		// A multi-attach volume being in use by other instances doesn't
+		// prevent attaching it to this one
+		case isAWSError(err, "VolumeInUse") && !multiAttach:
+			// The volume may be in use by this very instance, attached by a
+			// concurrent call since it was described
+			attachedToNode, describeErr := c.isAttachingOrAttachedToNode(ctx, volumeID, nodeID)
+			if describeErr != nil {
+				return "", newCloudError(describeErr, fmt.Sprintf("could not describe volume %q", volumeID))
 			}
-			return "", fmt.Errorf("could not attach volume %q to node %q: %v", volumeID, nodeID, err)
+			if !attachedToNode {
+				return "", ErrAlreadyExists
+			}
+			klog.V(5).Infof("Volume %q in use by instance %q already, waiting for it to be attached", volumeID, nodeID)
+		default:
+			return "", newCloudError(err, fmt.Sprintf("could not attach volume %q to node %q", volumeID, nodeID))
 		}
-		klog.V(5).Infof("AttachVolume volume=%q instance=%q request returned %v", volumeID, nodeID, resp)
-
 	}
 
-	// This is the only situation where we taint the device
-	if err := c.WaitForAttachmentState(ctx, volumeID, "attached"); err != nil {
+	// The device is tainted once the attachment is sent, here and below if the
+	// attachment isn't the expected one, as it may be in use on the instance
+	if err := c.waitForAttachmentState(ctx, volumeID, nodeID, "attached", attachment); err != nil {
 		device.Taint()
 		return "", err
 	}
 
-	// TODO: Double check the attachment to be 100% sure we attached the correct volume at the correct mountpoint
+	// Double check the attachment to be sure we attached the correct volume at the correct mountpoint.
 	// It could happen otherwise that we see the volume attached from a previous/separate AttachVolume call,
-	// which could theoretically be against a different device (or even instance).
+	// which could be against a different device (or even instance).
+	if err := c.verifyAttachment(ctx, volumeID, nodeID, device.Path); err != nil {
+		device.Taint()
+		return "", err
+	}
 
 	return device.Path, nil
 }
 
-func (c *cloud) DetachDisk(ctx context.Context, volumeID, nodeID string) error {
-	instance, err := c.getInstance(ctx, nodeID)
-	if err != nil {
-		return err
+// attachLock returns the mutex serializing the attachments to the instance.
+func (c *cloud) attachLock(nodeID string) *sync.Mutex {
+	lock, _ := c.attachLocks.LoadOrStore(nodeID, &sync.Mutex{})
+	return lock.(*sync.Mutex)
+}
+
+// attachVolume sends the attach request, sending it again up to the configured
+// number of retries while EC2 reports the volume as not found, as a volume
+// just created may not be visible to AttachVolume yet.
+func (c *cloud) attachVolume(ctx context.Context, request *ec2.AttachVolumeInput) (*ec2.VolumeAttachment, error) {
+	interval := c.options.attachNotFoundRetryInterval
+	if interval == 0 {
+		interval = DefaultAttachNotFoundRetryInterval
 	}
 
-	// TODO: check if attached
-	device, err := c.dm.GetDevice(instance, volumeID)
+	for attempt := 0; ; attempt++ {
+		resp, err := c.ec2.AttachVolumeWithContext(ctx, request, withoutDeviceName)
+		if err == nil || !isAWSErrorVolumeNotFound(err) || attempt >= c.options.attachNotFoundRetries {
+			return resp, err
+		}
+
+		klog.V(4).Infof("Volume %q not found by AttachVolume, retrying in %v: %v", aws.StringValue(request.VolumeId), interval, err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-c.rootCtx.Done():
+			return nil, c.rootCtx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// instanceRunningCheckInterval is the polling interval of waitForInstanceRunning
+const instanceRunningCheckInterval = 1 * time.Second
+
+// waitForInstanceRunning polls until the instance leaves the "pending" state,
+// for at most the configured instance running timeout. It fails if the
+// instance ends up in any other state than "running".
+func (c *cloud) waitForInstanceRunning(ctx context.Context, nodeID string) (*ec2.Instance, error) {
+	waitCtx, cancel := context.WithTimeout(ctx, c.options.instanceRunningTimeout)
+	defer cancel()
+
+	var instance *ec2.Instance
+	err := wait.PollUntil(instanceRunningCheckInterval, func() (bool, error) {
+		var err error
+		instance, err = c.getInstance(ctx, nodeID)
+		if err != nil {
+			return false, err
+		}
+		return instanceStateName(instance) != ec2.InstanceStateNamePending, nil
+	}, waitCtx.Done())
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("could not wait for instance %q to be running: %w", nodeID, err)
 	}
-	defer device.Release(true)
 
-	if !device.IsAlreadyAssigned {
-		klog.Warningf("DetachDisk called on non-attached volume: %s", volumeID)
+	if state := instanceStateName(instance); state != ec2.InstanceStateNameRunning {
+		return nil, fmt.Errorf("instance %q is %s instead of running", nodeID, state)
 	}
+	return instance, nil
+}
 
-	request := &ec2.DetachVolumeInput{
-		InstanceId: aws.String(nodeID),
-		VolumeId:   aws.String(volumeID),
+// instanceStateName returns the state name of the instance, empty if unknown.
+func instanceStateName(instance *ec2.Instance) string {
+	if instance.State == nil {
+		return ""
 	}
+	return aws.StringValue(instance.State.Name)
+}
 
-	_, err = c.ec2.DetachVolumeWithContext(ctx, request)
+// verifyAttachment checks that the volume has a single attachment, to the
+// given instance at the given device. Multi-attach volumes may have other
+// attachments, to other instances.
+func (c *cloud) verifyAttachment(ctx context.Context, volumeID, nodeID, devicePath string) error {
+	request := &ec2.DescribeVolumesInput{
+		VolumeIds: []*string{
+			aws.String(volumeID),
+		},
+	}
+
+	volume, err := c.getVolume(ctx, request)
 	if err != nil {
-		if isAWSErrorIncorrectState(err) ||
-			isAWSErrorInvalidAttachmentNotFound(err) ||
-			isAWSErrorVolumeNotFound(err) {
-			return ErrNotFound
-		}
-		return fmt.Errorf("could not detach volume %q from node %q: %v", volumeID, nodeID, err)
+		return fmt.Errorf("could not describe volume %q: %w", volumeID, err)
 	}
 
-	if err := c.WaitForAttachmentState(ctx, volumeID, "detached"); err != nil {
-		return err
+	attachments := volume.Attachments
+	if aws.BoolValue(volume.MultiAttachEnabled) {
+		attachments = nodeAttachments(volume, nodeID)
+	}
+	if len(attachments) != 1 {
+		return fmt.Errorf("expected volume %q to have 1 attachment, got %d", volumeID, len(attachments))
+	}
+
+	attachment := attachments[0]
+	if instanceID := aws.StringValue(attachment.InstanceId); instanceID != nodeID {
+		return fmt.Errorf("volume %q is attached to instance %q instead of %q", volumeID, instanceID, nodeID)
+	}
+	if device := aws.StringValue(attachment.Device); device != devicePath {
+		return fmt.Errorf("volume %q is attached at device %q instead of %q", volumeID, device, devicePath)
 	}
 
 	return nil
 }
 
-// WaitForAttachmentState polls until the attachment status is the expected value.
-func (c *cloud) WaitForAttachmentState(ctx context.Context, volumeID, state string) error {
-	// Most attach/detach operations on AWS finish within 1-4 seconds.
-	// By using 1 second starting interval with a backoff of 1.8,
-	// we get [1, 1.8, 3.24, 5.832000000000001, 10.4976].
-	// In total we wait for 2601 seconds.
-	backoff := wait.Backoff{
-		Duration: 1 * time.Second,
-		Factor:   1.8,
-		Steps:    13,
-	}
+// rollbackDeleteTimeout bounds the deletion of the volume created by
+// CreateAndAttachDisk when attaching it fails.
+const rollbackDeleteTimeout = 1 * time.Minute
 
-	verifyVolumeFunc := func() (bool, error) {
-		request := &ec2.DescribeVolumesInput{
-			VolumeIds: []*string{
-				aws.String(volumeID),
-			},
-		}
+// CreateAndAttachDisk creates a volume in the availability zone of the node
+// and attaches it to the node. The volume is deleted again if the attach fails.
+func (c *cloud) CreateAndAttachDisk(ctx context.Context, volumeName, nodeID string, diskOptions *DiskOptions) (*Disk, string, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
 
-		volume, err := c.getVolume(ctx, request)
-		if err != nil {
-			return false, err
-		}
+	instance, err := c.getInstance(ctx, nodeID)
+	if err != nil {
+		return nil, "", err
+	}
 
-		if len(volume.Attachments) == 0 {
-			if state == "detached" {
-				return true, nil
-			}
-		}
+	var zone string
+	if instance.Placement != nil {
+		zone = aws.StringValue(instance.Placement.AvailabilityZone)
+	}
+	if zone == "" {
+		return nil, "", fmt.Errorf("could not get availability zone of node %q", nodeID)
+	}
+	if diskOptions.AvailabilityZone != "" && diskOptions.AvailabilityZone != zone {
+		return nil, "", fmt.Errorf("availability zone %q doesn't match zone %q of node %q", diskOptions.AvailabilityZone, zone, nodeID)
+	}
 
-		for _, a := range volume.Attachments {
+	options := *diskOptions
+	options.AvailabilityZone = zone
+	disk, err := c.CreateDisk(ctx, volumeName, &options)
+	if err != nil {
+		return nil, "", err
+	}
+
+	devicePath, err := c.AttachDisk(ctx, disk.VolumeID, nodeID)
+	if err != nil {
+		// The attach may have failed because ctx is done, so the volume is
+		// deleted with a context of its own not to leak it.
+		deleteCtx, deleteCancel := context.WithTimeout(context.Background(), rollbackDeleteTimeout)
+		defer deleteCancel()
+		if _, deleteErr := c.DeleteDisk(deleteCtx, disk.VolumeID); deleteErr != nil {
+			klog.Warningf("Could not delete volume %q after failing to attach it to node %q: %v", disk.VolumeID, nodeID, deleteErr)
+		}
+		return nil, "", err
+	}
+
+	return disk, devicePath, nil
+}
+
+// volumeTypeAlias returns the volume type the alias stands for, looked up in
+// the configured aliases first, then in DefaultVolumeTypeAliases.
+func (c *cloud) volumeTypeAlias(alias string) (string, bool) {
+	if volumeType, ok := c.options.volumeTypeAliases[alias]; ok {
+		return volumeType, true
+	}
+	volumeType, ok := DefaultVolumeTypeAliases[alias]
+	return volumeType, ok
+}
+
+// autoVolumeType returns the volume type picked for a volume of type auto:
+// the large volumes, typically used for sequential workloads such as logs or
+// data processing, are created as throughput-optimized st2 volumes, the
+// others as general purpose gp2 volumes.
+func (c *cloud) autoVolumeType(capacityBytes int64) string {
+	threshold := c.options.autoVolumeTypeThreshold
+	if threshold == 0 {
+		threshold = DefaultAutoVolumeTypeThreshold
+	}
+	if capacityBytes >= threshold {
+		return VolumeTypeST2
+	}
+	return VolumeTypeGP2
+}
+
+// isValidVolumeType reports whether the volume type is one of ValidVolumeTypes.
+func isValidVolumeType(volumeType string) bool {
+	for _, t := range ValidVolumeTypes {
+		if t == volumeType {
+			return true
+		}
+	}
+	return false
+}
+
+// maxIOPSPerGB returns the maximum ratio of IOPS to size accepted by EC2 for
+// the provisioned IOPS volume type of the options.
+func maxIOPSPerGB(diskOptions *DiskOptions) int {
+	if diskOptions.VolumeType == VolumeTypeIO1 {
+		return MaxIO1IOPSPerGB
+	}
+	if diskOptions.BlockExpress {
+		return MaxIO2BlockExpressIOPSPerGB
+	}
+	return MaxIO2IOPSPerGB
+}
+
+// resolveIOPS returns the IOPS to provision for a volume of the type and of
+// capacityGiB. The io1 and io2 volumes get the IOPS per GiB of the options,
+// within the limits of the volume type. The other volume types get none,
+// their performance following from their size.
+func resolveIOPS(volumeType string, capacityGiB int64, diskOptions *DiskOptions) int64 {
+	if volumeType != VolumeTypeIO1 && volumeType != VolumeTypeIO2 {
+		return 0
+	}
+
+	iops := capacityGiB * int64(diskOptions.IOPSPerGB)
+	if iops < MinTotalIOPS {
+		iops = MinTotalIOPS
+	}
+	maxIOPS := int64(MaxTotalIOPS)
+	if volumeType == VolumeTypeIO2 && diskOptions.BlockExpress {
+		maxIOPS = MaxIO2BlockExpressIOPS
+	}
+	if iops > maxIOPS {
+		iops = maxIOPS
+	}
+	return iops
+}
+
+// withoutDeviceName lets AttachVolume be sent without a device name, which
+// the SDK requires but the cloud picks itself. The SDK parameter validation is
+// swapped for validateAttachVolumeInput, so that the other required fields
+// are still checked before the request is sent.
+func withoutDeviceName(r *request.Request) {
+	r.Handlers.Validate.Swap(corehandlers.ValidateParametersHandler.Name, request.NamedHandler{
+		Name: "ebscsi.ValidateAttachVolumeInput",
+		Fn:   validateAttachVolumeInput,
+	})
+}
+
+// validateAttachVolumeInput validates the parameters of AttachVolume like the
+// SDK does, except for the device name which is optional.
+func validateAttachVolumeInput(r *request.Request) {
+	input, ok := r.Params.(*ec2.AttachVolumeInput)
+	if !ok {
+		corehandlers.ValidateParametersHandler.Fn(r)
+		return
+	}
+
+	invalidParams := request.ErrInvalidParams{Context: "AttachVolumeInput"}
+	if input.InstanceId == nil {
+		invalidParams.Add(request.NewErrParamRequired("InstanceId"))
+	}
+	if input.VolumeId == nil {
+		invalidParams.Add(request.NewErrParamRequired("VolumeId"))
+	}
+	if invalidParams.Len() > 0 {
+		r.Error = invalidParams
+	}
+}
+
+// withVolumeInitializationRate returns a request option sending the volume
+// initialization rate with a CreateVolume request. The SDK doesn't model the
+// parameter, so it is added to the serialized query.
+func withVolumeInitializationRate(rate int64) request.Option {
+	return func(r *request.Request) {
+		r.Handlers.Build.PushBackNamed(request.NamedHandler{
+			Name: "ebscsi.VolumeInitializationRate",
+			Fn: func(r *request.Request) {
+				if r.Error != nil {
+					return
+				}
+				if _, err := r.Body.Seek(0, io.SeekStart); err != nil {
+					r.Error = err
+					return
+				}
+				body, err := ioutil.ReadAll(r.Body)
+				if err != nil {
+					r.Error = err
+					return
+				}
+				query, err := url.ParseQuery(string(body))
+				if err != nil {
+					r.Error = err
+					return
+				}
+				query.Set("VolumeInitializationRate", strconv.FormatInt(rate, 10))
+				r.SetBufferBody([]byte(query.Encode()))
+			},
+		})
+	}
+}
+
+// GetVolumeAttachments returns the attachments of the volume, of which
+// multi-attach volumes can have several. It returns an empty slice if the
+// volume is detached.
+func (c *cloud) GetVolumeAttachments(ctx context.Context, volumeID string) ([]VolumeAttachment, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	request := &ec2.DescribeVolumesInput{
+		VolumeIds: []*string{
+			aws.String(volumeID),
+		},
+	}
+
+	volume, err := c.getVolume(ctx, request)
+	if err != nil {
+		if isAWSErrorVolumeNotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	attachments := make([]VolumeAttachment, 0, len(volume.Attachments))
+	for _, a := range volume.Attachments {
+		attachments = append(attachments, VolumeAttachment{
+			InstanceID: aws.StringValue(a.InstanceId),
+			Device:     aws.StringValue(a.Device),
+			State:      aws.StringValue(a.State),
+		})
+	}
+	return attachments, nil
+}
+
+// GetExpectedDevicePath returns the device path the volume is, or will be,
+// attached under on the node. Nitro instances ignore it and present the volume
+// as an NVMe device instead, which the node can find by NVMeSerial.
+func (c *cloud) GetExpectedDevicePath(ctx context.Context, volumeID, nodeID string) (string, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	instance, err := c.getInstance(ctx, nodeID)
+	if err != nil {
+		return "", err
+	}
+
+	device, err := c.dm.GetDevice(instance, volumeID)
+	if err != nil {
+		return "", err
+	}
+	if device.Path != "" {
+		return device.Path, nil
+	}
+	return dm.DevicePath(volumeID), nil
+}
+
+// NVMeSerial returns the serial number of the NVMe device the volume is
+// presented as, which is the volume ID without the dash.
+func NVMeSerial(volumeID string) string {
+	return strings.Replace(volumeID, "-", "", -1)
+}
+
+// isAttachedToNode reports whether the volume has an attachment to the
+// given instance in the "attached" state.
+func isAttachedToNode(volume *ec2.Volume, nodeID string) bool {
+	for _, a := range nodeAttachments(volume, nodeID) {
+		if aws.StringValue(a.State) == "attached" {
+			return true
+		}
+	}
+	return false
+}
+
+// isAttachingOrAttachedToNode describes the volume to report whether it has
+// an attachment to the instance, whatever its state.
+func (c *cloud) isAttachingOrAttachedToNode(ctx context.Context, volumeID, nodeID string) (bool, error) {
+	request := &ec2.DescribeVolumesInput{
+		VolumeIds: []*string{
+			aws.String(volumeID),
+		},
+	}
+	volume, err := c.getVolume(ctx, request)
+	if err != nil {
+		return false, err
+	}
+	return len(nodeAttachments(volume, nodeID)) > 0, nil
+}
+
+// nodeAttachments returns the attachments of the volume to the given instance.
+func nodeAttachments(volume *ec2.Volume, nodeID string) []*ec2.VolumeAttachment {
+	var attachments []*ec2.VolumeAttachment
+	for _, a := range volume.Attachments {
+		if aws.StringValue(a.InstanceId) == nodeID {
+			attachments = append(attachments, a)
+		}
+	}
+	return attachments
+}
+
+func (c *cloud) DetachDisk(ctx context.Context, volumeID, nodeID string) error {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	if err := validateVolumeID(volumeID); err != nil {
+		return err
+	}
+
+	instance, err := c.getInstance(ctx, nodeID)
+	if err != nil {
+		return err
+	}
+
+	// TODO: check if attached
+	device, err := c.dm.GetDevice(instance, volumeID)
+	if err != nil {
+		return err
+	}
+	defer device.Release(true)
+
+	if !device.IsAlreadyAssigned {
+		klog.Warningf("DetachDisk called on non-attached volume: %s", volumeID)
+	}
+
+	request := &ec2.DetachVolumeInput{
+		InstanceId: aws.String(nodeID),
+		VolumeId:   aws.String(volumeID),
+	}
+
+	_, err = c.ec2.DetachVolumeWithContext(ctx, request)
+	c.volumeCache.invalidate(volumeID)
+	if err != nil {
+		if isAWSErrorIncorrectState(err) ||
+			isAWSErrorInvalidAttachmentNotFound(err) ||
+			isAWSErrorVolumeNotFound(err) {
+			return ErrNotFound
+		}
+		return newCloudError(err, fmt.Sprintf("could not detach volume %q from node %q", volumeID, nodeID))
+	}
+
+	if err := c.waitForAttachmentState(ctx, volumeID, nodeID, "detached", nil); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// WaitForAttachmentState polls until the attachment status is the expected value.
+func (c *cloud) WaitForAttachmentState(ctx context.Context, volumeID, state string) error {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	return c.waitForAttachmentState(ctx, volumeID, "", state, nil)
+}
+
+// waitForAttachmentState polls until the status of the attachment to the
+// given instance is the expected value. An empty nodeID matches the
+// attachments to any instance, which is only accurate for volumes that
+// aren't multi-attach. The initial attachment, e.g. the one returned by
+// AttachVolume, saves the polling if it already has the expected status.
+func (c *cloud) waitForAttachmentState(ctx context.Context, volumeID, nodeID, state string, initial *ec2.VolumeAttachment) error {
+	if initial != nil && aws.StringValue(initial.State) == state && (nodeID == "" || aws.StringValue(initial.InstanceId) == nodeID) {
+		return nil
+	}
+
+	// Most attach/detach operations on AWS finish within 1-4 seconds.
+	// By using 1 second starting interval with a backoff of 1.8,
+	// we get [1, 1.8, 3.24, 5.832000000000001, 10.4976].
+	// In total we wait for 2601 seconds.
+	backoff := wait.Backoff{
+		Duration: 1 * time.Second,
+		Factor:   1.8,
+		Steps:    13,
+	}
+
+	verifyVolumeFunc := func() (bool, error) {
+		request := &ec2.DescribeVolumesInput{
+			VolumeIds: []*string{
+				aws.String(volumeID),
+			},
+		}
+
+		// Always read through to EC2 while polling, but keep the result
+		// for the reads that follow
+		volume, err := c.getVolume(ctx, request)
+		if err != nil {
+			return false, err
+		}
+		c.volumeCache.set(volume)
+
+		attachments := volume.Attachments
+		if nodeID != "" {
+			attachments = nodeAttachments(volume, nodeID)
+		}
+
+		if len(attachments) == 0 {
+			if state == "detached" {
+				return true, nil
+			}
+		}
+
+		for _, a := range attachments {
 			if a.State == nil {
 				klog.Warningf("Ignoring nil attachment state for volume %q: %v", volumeID, a)
 				continue
@@ -662,10 +1519,84 @@ func (c *cloud) WaitForAttachmentState(ctx context.Context, volumeID, state stri
 		return false, nil
 	}
 
-	return wait.ExponentialBackoff(backoff, verifyVolumeFunc)
+	return exponentialBackoff(c.rootCtx, backoff, verifyVolumeFunc)
+}
+
+// withOperationTimeout bounds the context of a Cloud method call by the
+// configured operation timeout, unless it already has a deadline.
+func (c *cloud) withOperationTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.options.operationTimeout <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.options.operationTimeout)
+}
+
+// Close aborts the in-flight waits for volumes to be attached, detached,
+// created or resized, which return an error. It is meant to be called on
+// shutdown, the cloud mustn't be used afterwards.
+func (c *cloud) Close() {
+	c.cancel()
+}
+
+// exponentialBackoff is wait.ExponentialBackoff, returning the context error
+// as soon as the context is done instead of sleeping through the backoff.
+func exponentialBackoff(ctx context.Context, backoff wait.Backoff, condition wait.ConditionFunc) error {
+	for backoff.Steps > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if ok, err := condition(); err != nil || ok {
+			return err
+		}
+		if backoff.Steps == 1 {
+			break
+		}
+
+		timer := time.NewTimer(backoff.Step())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return wait.ErrWaitTimeout
+}
+
+// pollWithCappedBackoff is exponentialBackoff without a number of steps: the
+// interval grows by half after each check up to maxInterval, and the polling
+// goes on until the condition is done or fails, or ctx is done.
+func pollWithCappedBackoff(ctx context.Context, interval, maxInterval time.Duration, condition wait.ConditionFunc) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if ok, err := condition(); err != nil || ok {
+			return err
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		interval = interval * 3 / 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
 }
 
 func (c *cloud) GetDiskByName(ctx context.Context, name string, capacityBytes int64) (*Disk, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
 	request := &ec2.DescribeVolumesInput{
 		Filters: []*ec2.Filter{
 			{
@@ -675,13 +1606,14 @@ func (c *cloud) GetDiskByName(ctx context.Context, name string, capacityBytes in
 		},
 	}
 
-	volume, err := c.getVolume(ctx, request)
+	// Volumes are created rounded up to whole GiB
+	volume, err := c.getVolumeOfSize(ctx, request, util.RoundUpGiB(capacityBytes))
 	if err != nil {
 		return nil, err
 	}
 
 	volSizeBytes := aws.Int64Value(volume.Size)
-	if volSizeBytes != util.BytesToGiB(capacityBytes) {
+	if volSizeBytes != util.RoundUpGiB(capacityBytes) {
 		return nil, ErrDiskExistsDiffSize
 	}
 
@@ -694,6 +1626,17 @@ func (c *cloud) GetDiskByName(ctx context.Context, name string, capacityBytes in
 }
 
 func (c *cloud) GetDiskByID(ctx context.Context, volumeID string) (*Disk, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	if err := validateVolumeID(volumeID); err != nil {
+		return nil, err
+	}
+
+	if volume, ok := c.volumeCache.get(volumeID); ok {
+		return c.ec2VolumeResponseToStruct(volume), nil
+	}
+
 	request := &ec2.DescribeVolumesInput{
 		VolumeIds: []*string{
 			aws.String(volumeID),
@@ -704,65 +1647,656 @@ func (c *cloud) GetDiskByID(ctx context.Context, volumeID string) (*Disk, error)
 	if err != nil {
 		return nil, err
 	}
+	c.volumeCache.set(volume)
 
-	return &Disk{
-		VolumeID:         aws.StringValue(volume.VolumeId),
-		CapacityGiB:      aws.Int64Value(volume.Size),
-		AvailabilityZone: aws.StringValue(volume.AvailabilityZone),
-	}, nil
-}
-
-func (c *cloud) IsExistInstance(ctx context.Context, nodeID string) bool {
-	instance, err := c.getInstance(ctx, nodeID)
-	if err != nil || instance == nil {
-		return false
-	}
-	return true
+	return c.ec2VolumeResponseToStruct(volume), nil
 }
 
-func (c *cloud) CreateSnapshot(ctx context.Context, volumeID string, snapshotOptions *SnapshotOptions) (snapshot *Snapshot, err error) {
-	descriptions := "Created by AWS EBS CSI driver for volume " + volumeID
+// DetectStuckVolumes returns the volumes created by the driver that have been
+// in the "creating" state for longer than stuckAfter, so that an operator can
+// investigate or delete them.
+func (c *cloud) DetectStuckVolumes(ctx context.Context, stuckAfter time.Duration) ([]*Disk, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
 
-	var tags []*ec2.Tag
-	for key, value := range snapshotOptions.Tags {
-		tags = append(tags, &ec2.Tag{Key: &key, Value: &value})
-	}
-	tagSpec := ec2.TagSpecification{
-		ResourceType: aws.String("snapshot"),
-		Tags:         tags,
-	}
-	request := &ec2.CreateSnapshotInput{
-		VolumeId:          aws.String(volumeID),
-		DryRun:            aws.Bool(false),
-		TagSpecifications: []*ec2.TagSpecification{&tagSpec},
-		Description:       aws.String(descriptions),
+	request := &ec2.DescribeVolumesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("tag-key"),
+				Values: []*string{aws.String(VolumeNameTagKey)},
+			},
+			{
+				Name:   aws.String("status"),
+				Values: []*string{aws.String(ec2.VolumeStateCreating)},
+			},
+		},
 	}
 
-	res, err := c.ec2.CreateSnapshotWithContext(ctx, request)
+	volumes, err := c.getVolumes(ctx, request)
 	if err != nil {
-		return nil, fmt.Errorf("error creating snapshot of volume %s: %v", volumeID, err)
+		return nil, err
 	}
-	if res == nil {
+
+	var disks []*Disk
+	deadline := time.Now().Add(-stuckAfter)
+	for _, volume := range volumes {
+		if aws.TimeValue(volume.CreateTime).Before(deadline) {
+			disks = append(disks, c.ec2VolumeResponseToStruct(volume))
+		}
+	}
+
+	return disks, nil
+}
+
+// CheckVolumeConfigDrift compares the type, IOPS and size of the volume with
+// the desired ones, as they would be set by CreateDisk, e.g. for a reconciler
+// to apply them again after a modification was clamped. A zero desired
+// capacity leaves the size out of the comparison.
+func (c *cloud) CheckVolumeConfigDrift(ctx context.Context, volumeID string, desired *DiskOptions) (*ConfigDrift, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	volume, err := c.getVolume(ctx, &ec2.DescribeVolumesInput{
+		VolumeIds: []*string{aws.String(volumeID)},
+	})
+	if err != nil {
+		if isAWSErrorVolumeNotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	desiredType := desired.VolumeType
+	if volumeType, ok := c.volumeTypeAlias(desiredType); ok {
+		desiredType = volumeType
+	}
+	if desiredType == "" {
+		desiredType = DefaultVolumeType
+	}
+
+	drift := &ConfigDrift{
+		ActualVolumeType:  aws.StringValue(volume.VolumeType),
+		DesiredVolumeType: desiredType,
+		ActualIOPS:        aws.Int64Value(volume.Iops),
+		ActualSizeGiB:     aws.Int64Value(volume.Size),
+		DesiredSizeGiB:    util.RoundUpGiB(desired.CapacityBytes),
+	}
+	drift.VolumeTypeDrifted = drift.ActualVolumeType != drift.DesiredVolumeType
+	if desired.CapacityBytes > 0 {
+		drift.SizeDrifted = drift.ActualSizeGiB != drift.DesiredSizeGiB
+	}
+	if desiredType == VolumeTypeIO1 || desiredType == VolumeTypeIO2 {
+		sizeGiB := drift.DesiredSizeGiB
+		if desired.CapacityBytes == 0 {
+			sizeGiB = drift.ActualSizeGiB
+		}
+		drift.DesiredIOPS = resolveIOPS(desiredType, sizeGiB, desired)
+		drift.IOPSDrifted = drift.ActualIOPS != drift.DesiredIOPS
+	}
+
+	return drift, nil
+}
+
+// FindVolumesAttachedToDeadNodes returns the volumes created by the driver
+// that are attached to an instance missing from liveNodeIDs, e.g. left stuck
+// on a terminated node.
+func (c *cloud) FindVolumesAttachedToDeadNodes(ctx context.Context, liveNodeIDs map[string]bool) ([]*Disk, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	request := &ec2.DescribeVolumesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("tag-key"),
+				Values: []*string{aws.String(VolumeNameTagKey)},
+			},
+			{
+				Name:   aws.String("status"),
+				Values: []*string{aws.String(ec2.VolumeStateInUse)},
+			},
+		},
+	}
+
+	volumes, err := c.getVolumes(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	var disks []*Disk
+	for _, volume := range volumes {
+		for _, a := range volume.Attachments {
+			if !liveNodeIDs[aws.StringValue(a.InstanceId)] {
+				disks = append(disks, c.ec2VolumeResponseToStruct(volume))
+				break
+			}
+		}
+	}
+
+	return disks, nil
+}
+
+// ListDisksByTag returns all the volumes with the tag, whatever its value if
+// tagValue is empty.
+func (c *cloud) ListDisksByTag(ctx context.Context, tagKey, tagValue string) ([]*Disk, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	request := &ec2.DescribeVolumesInput{
+		Filters: []*ec2.Filter{tagFilter(tagKey, tagValue)},
+	}
+
+	volumes, err := c.getVolumes(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("could not list volumes with tag %q: %w", tagKey, err)
+	}
+
+	disks := make([]*Disk, 0, len(volumes))
+	for _, volume := range volumes {
+		disks = append(disks, c.ec2VolumeResponseToStruct(volume))
+	}
+	return disks, nil
+}
+
+// ListVolumes lists a page of the volumes created by the driver, i.e. with
+// the volume name tag. The next page is listed with the returned NextToken,
+// which is empty on the last page.
+func (c *cloud) ListVolumes(ctx context.Context, maxResults int64, nextToken string) (*ListVolumesResponse, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	if maxResults > 0 && maxResults < 5 {
+		return nil, ErrInvalidMaxResults
+	}
+
+	request := &ec2.DescribeVolumesInput{
+		Filters: []*ec2.Filter{tagFilter(VolumeNameTagKey, "")},
+	}
+	if maxResults > 0 {
+		request.MaxResults = aws.Int64(maxResults)
+	}
+	if len(nextToken) != 0 {
+		request.NextToken = aws.String(nextToken)
+	}
+
+	response, err := c.ec2.DescribeVolumesWithContext(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("could not list volumes: %w", err)
+	}
+
+	disks := make([]*Disk, 0, len(response.Volumes))
+	for _, volume := range response.Volumes {
+		disks = append(disks, c.ec2VolumeResponseToStruct(volume))
+	}
+	return &ListVolumesResponse{
+		Disks:     disks,
+		NextToken: aws.StringValue(response.NextToken),
+	}, nil
+}
+
+// ListOrphanedDisks returns the volumes created by the driver with the
+// cluster tag that are attached to an instance that doesn't exist anymore,
+// which need to be force detached. Unlike with IsExistInstance, an instance
+// that can't be described fails the call rather than being taken as missing.
+// The instances are looked up without the instance cluster tag filter, so that
+// an instance outside the cluster isn't taken as missing either.
+func (c *cloud) ListOrphanedDisks(ctx context.Context, clusterTagKey, clusterTagValue string) ([]*Disk, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	request := &ec2.DescribeVolumesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("tag-key"),
+				Values: []*string{aws.String(VolumeNameTagKey)},
+			},
+			tagFilter(clusterTagKey, clusterTagValue),
+			{
+				Name:   aws.String("attachment.status"),
+				Values: aws.StringSlice([]string{ec2.VolumeAttachmentStateAttaching, ec2.VolumeAttachmentStateAttached, ec2.VolumeAttachmentStateDetaching}),
+			},
+		},
+	}
+
+	volumes, err := c.getVolumes(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("could not list volumes with tag %q: %w", clusterTagKey, err)
+	}
+
+	// Several volumes are usually attached to the same instance
+	exists := map[string]bool{}
+	var disks []*Disk
+	for _, volume := range volumes {
+		for _, a := range volume.Attachments {
+			nodeID := aws.StringValue(a.InstanceId)
+			found, ok := exists[nodeID]
+			if !ok {
+				_, err := c.describeInstance(ctx, nodeID, nil)
+				if err != nil && err != ErrNotFound {
+					return nil, fmt.Errorf("could not describe instance %q of volume %q: %w", nodeID, aws.StringValue(volume.VolumeId), err)
+				}
+				found = err == nil
+				exists[nodeID] = found
+			}
+			if !found {
+				disks = append(disks, c.ec2VolumeResponseToStruct(volume))
+				break
+			}
+		}
+	}
+
+	return disks, nil
+}
+
+// tagFilter returns the describe filter on the tag, whatever its value
+// if value is empty.
+func tagFilter(key, value string) *ec2.Filter {
+	if value == "" {
+		return &ec2.Filter{
+			Name:   aws.String("tag-key"),
+			Values: []*string{aws.String(key)},
+		}
+	}
+	return &ec2.Filter{
+		Name:   aws.String("tag:" + key),
+		Values: []*string{aws.String(value)},
+	}
+}
+
+// VolumeExists reports whether the volume exists. Unlike GetDiskByID, a
+// missing volume is not treated as an error.
+func (c *cloud) VolumeExists(ctx context.Context, volumeID string) (bool, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	request := &ec2.DescribeVolumesInput{
+		VolumeIds: []*string{
+			aws.String(volumeID),
+		},
+	}
+
+	if _, err := c.getVolume(ctx, request); err != nil {
+		if err == ErrNotFound || isAWSErrorVolumeNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// GetVolumeHealth returns the status checks of the volume and its events, or
+// ErrNotFound if it does not exist.
+func (c *cloud) GetVolumeHealth(ctx context.Context, volumeID string) (VolumeHealth, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	if err := validateVolumeID(volumeID); err != nil {
+		return VolumeHealth{}, err
+	}
+
+	request := &ec2.DescribeVolumeStatusInput{
+		VolumeIds: []*string{
+			aws.String(volumeID),
+		},
+	}
+	response, err := c.ec2.DescribeVolumeStatusWithContext(ctx, request)
+	if err != nil {
+		if isAWSErrorVolumeNotFound(err) {
+			return VolumeHealth{}, ErrNotFound
+		}
+		return VolumeHealth{}, fmt.Errorf("could not get the status of volume %q: %w", volumeID, err)
+	}
+
+	if l := len(response.VolumeStatuses); l > 1 {
+		return VolumeHealth{}, ErrMultiDisks
+	} else if l < 1 {
+		return VolumeHealth{}, ErrNotFound
+	}
+
+	item := response.VolumeStatuses[0]
+	health := VolumeHealth{
+		VolumeID: aws.StringValue(item.VolumeId),
+	}
+	if item.VolumeStatus != nil {
+		health.Status = aws.StringValue(item.VolumeStatus.Status)
+	}
+	for _, event := range item.Events {
+		health.Events = append(health.Events, VolumeHealthEvent{
+			EventID:     aws.StringValue(event.EventId),
+			EventType:   aws.StringValue(event.EventType),
+			Description: aws.StringValue(event.Description),
+			NotBefore:   aws.TimeValue(event.NotBefore),
+			NotAfter:    aws.TimeValue(event.NotAfter),
+		})
+	}
+	return health, nil
+}
+
+func (c *cloud) IsExistInstance(ctx context.Context, nodeID string) bool {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	instance, err := c.getInstance(ctx, nodeID)
+	if err != nil || instance == nil {
+		return false
+	}
+	return true
+}
+
+// GetInstanceType returns the type of the instance, or ErrNotFound if the
+// instance doesn't exist.
+func (c *cloud) GetInstanceType(ctx context.Context, nodeID string) (string, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	instance, err := c.getInstance(ctx, nodeID)
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(instance.InstanceType), nil
+}
+
+// GetInstanceTypeInfo returns the EBS capabilities of the instance type. The
+// result is cached, so the API is only called once per instance type.
+func (c *cloud) GetInstanceTypeInfo(ctx context.Context, instanceType string) (*InstanceTypeInfo, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	if info, ok := c.instanceTypes.Load(instanceType); ok {
+		return info.(*InstanceTypeInfo), nil
+	}
+
+	request := &ec2.DescribeInstanceTypesInput{
+		InstanceTypes: []*string{aws.String(instanceType)},
+	}
+
+	response, err := c.ec2.DescribeInstanceTypesWithContext(ctx, request)
+	if err != nil {
+		if isAWSErrorInvalidInstanceType(err) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("could not describe instance type %q: %w", instanceType, err)
+	}
+	if len(response.InstanceTypes) == 0 {
+		return nil, ErrNotFound
+	}
+
+	info := ec2InstanceTypeResponseToStruct(response.InstanceTypes[0])
+	c.instanceTypes.Store(instanceType, info)
+	return info, nil
+}
+
+// Helper method converting EC2 instance type info to the internal struct
+func ec2InstanceTypeResponseToStruct(ec2Info *ec2.InstanceTypeInfo) *InstanceTypeInfo {
+	info := &InstanceTypeInfo{
+		InstanceType: aws.StringValue(ec2Info.InstanceType),
+		Hypervisor:   aws.StringValue(ec2Info.Hypervisor),
+	}
+	if ec2Info.NetworkInfo != nil {
+		info.MaxNetworkInterfaces = aws.Int64Value(ec2Info.NetworkInfo.MaximumNetworkInterfaces)
+	}
+	if ebsInfo := ec2Info.EbsInfo; ebsInfo != nil {
+		info.EBSOptimizedSupport = aws.StringValue(ebsInfo.EbsOptimizedSupport)
+		info.NVMeSupport = aws.StringValue(ebsInfo.NvmeSupport)
+		if optimized := ebsInfo.EbsOptimizedInfo; optimized != nil {
+			info.MaxIOPS = aws.Int64Value(optimized.MaximumIops)
+			info.MaxBandwidthInMbps = aws.Int64Value(optimized.MaximumBandwidthInMbps)
+			info.MaxThroughputInMBps = aws.Float64Value(optimized.MaximumThroughputInMBps)
+		}
+	}
+	return info
+}
+
+func (c *cloud) CreateSnapshot(ctx context.Context, volumeID string, snapshotOptions *SnapshotOptions) (snapshot *Snapshot, err error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	descriptions := "Created by AWS EBS CSI driver for volume " + volumeID
+
+	tags := c.withExtraTags(snapshotTags(snapshotOptions))
+	if err := validateTags(tags); err != nil {
+		return nil, fmt.Errorf("invalid tags for snapshot of volume %s: %w", volumeID, err)
+	}
+
+	request := &ec2.CreateSnapshotInput{
+		VolumeId:          aws.String(volumeID),
+		DryRun:            aws.Bool(false),
+		TagSpecifications: buildTagSpecifications(ec2.ResourceTypeSnapshot, tags),
+		Description:       aws.String(descriptions),
+	}
+
+	res, err := c.ec2.CreateSnapshotWithContext(ctx, request)
+	if err != nil {
+		if key, ok := rejectedTagKey(err, tags); ok {
+			return nil, newCloudError(err, fmt.Sprintf("error creating snapshot of volume %s: tag %q was rejected", volumeID, key))
+		}
+		return nil, newCloudError(err, fmt.Sprintf("error creating snapshot of volume %s", volumeID))
+	}
+	if res == nil {
 		return nil, fmt.Errorf("nil CreateSnapshotResponse")
 	}
 
 	return c.ec2SnapshotResponseToStruct(res), nil
 }
 
+// CreateEncryptedSnapshot creates an encrypted snapshot of the volume, which
+// may be unencrypted: the volume is snapshotted, then the snapshot is copied
+// with encryption under the KMS key, the default EBS key if empty. The
+// intermediate snapshot doesn't carry the snapshot name tag, so that it isn't
+// found by name along with the copy, and is deleted once the copy is
+// completed, on a best-effort basis. The completed encrypted copy is returned,
+// or deleted too, on a best-effort basis, if it doesn't complete.
+func (c *cloud) CreateEncryptedSnapshot(ctx context.Context, volumeID, kmsKeyID string, snapshotOptions *SnapshotOptions) (*Snapshot, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	intermediateOptions := *snapshotOptions
+	intermediateOptions.Tags = make(map[string]string, len(snapshotOptions.Tags))
+	for key, value := range snapshotOptions.Tags {
+		if key != SnapshotNameTagKey {
+			intermediateOptions.Tags[key] = value
+		}
+	}
+	source, err := c.CreateSnapshot(ctx, volumeID, &intermediateOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if deleteErr := c.rollbackDeleteSnapshot(source.SnapshotID); deleteErr != nil {
+			klog.Warningf("Could not delete intermediate snapshot %q of volume %q: %v", source.SnapshotID, volumeID, deleteErr)
+		}
+	}()
+
+	// Only completed snapshots can be copied
+	if _, err := c.WaitForSnapshotCompleted(ctx, source.SnapshotID); err != nil {
+		return nil, err
+	}
+
+	tags := c.withExtraTags(snapshotTags(snapshotOptions))
+
+	request := &ec2.CopySnapshotInput{
+		SourceSnapshotId:  aws.String(source.SnapshotID),
+		SourceRegion:      aws.String(c.region),
+		Encrypted:         aws.Bool(true),
+		TagSpecifications: buildTagSpecifications(ec2.ResourceTypeSnapshot, tags),
+		Description:       aws.String("Created by AWS EBS CSI driver for volume " + volumeID),
+	}
+	if len(kmsKeyID) > 0 {
+		request.KmsKeyId = aws.String(kmsKeyID)
+	}
+
+	res, err := c.ec2.CopySnapshotWithContext(ctx, request)
+	if err != nil {
+		return nil, newCloudError(err, fmt.Sprintf("error copying snapshot %s of volume %s with encryption", source.SnapshotID, volumeID))
+	}
+	if res == nil || res.SnapshotId == nil {
+		return nil, fmt.Errorf("nil CopySnapshotResponse")
+	}
+
+	// The intermediate snapshot is kept until the copy no longer reads it
+	copyID := aws.StringValue(res.SnapshotId)
+	snapshot, err := c.WaitForSnapshotCompleted(ctx, copyID)
+	if err != nil {
+		if deleteErr := c.rollbackDeleteSnapshot(copyID); deleteErr != nil {
+			klog.Warningf("Could not delete encrypted copy %q of snapshot %q: %v", copyID, source.SnapshotID, deleteErr)
+			return nil, fmt.Errorf("encrypted copy %q of snapshot %q left behind: %w", copyID, source.SnapshotID, err)
+		}
+		return nil, err
+	}
+	// EC2 doesn't report the volume of copied snapshots
+	snapshot.SourceVolumeID = volumeID
+	return snapshot, nil
+}
+
+// rollbackDeleteSnapshot deletes the snapshot with a context of its own, as
+// the operation rolled back may have failed because its context is done.
+func (c *cloud) rollbackDeleteSnapshot(snapshotID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), rollbackDeleteTimeout)
+	defer cancel()
+	_, err := c.DeleteSnapshot(ctx, snapshotID)
+	return err
+}
+
+// volumeClientToken returns the client token of the creation of the volume,
+// the SHA-256 of its name, so that the retries of a creation are idempotent.
+// It fits the 64 characters limit of EC2 whatever the length of the name.
+func volumeClientToken(volumeName string) string {
+	sum := sha256.Sum256([]byte(volumeName))
+	return hex.EncodeToString(sum[:])
+}
+
+// withExtraTags returns the tags merged with the configured extra tags, the
+// given tags winning on key collisions.
+func (c *cloud) withExtraTags(tags map[string]string) map[string]string {
+	if len(c.options.extraTags) == 0 {
+		return tags
+	}
+	merged := make(map[string]string, len(c.options.extraTags)+len(tags))
+	for key, value := range c.options.extraTags {
+		merged[key] = value
+	}
+	for key, value := range tags {
+		merged[key] = value
+	}
+	return merged
+}
+
+// snapshotTags returns the tags of a snapshot created with the options.
+func snapshotTags(snapshotOptions *SnapshotOptions) map[string]string {
+	if len(snapshotOptions.SourceRegion) == 0 {
+		return snapshotOptions.Tags
+	}
+	tags := make(map[string]string, len(snapshotOptions.Tags)+1)
+	for key, value := range snapshotOptions.Tags {
+		tags[key] = value
+	}
+	tags[SnapshotSourceRegionTagKey] = snapshotOptions.SourceRegion
+	return tags
+}
+
 func (c *cloud) DeleteSnapshot(ctx context.Context, snapshotID string) (success bool, err error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	if err := validateSnapshotID(snapshotID); err != nil {
+		return false, err
+	}
+
 	request := &ec2.DeleteSnapshotInput{}
 	request.SnapshotId = aws.String(snapshotID)
 	request.DryRun = aws.Bool(false)
 	if _, err := c.ec2.DeleteSnapshotWithContext(ctx, request); err != nil {
 		if isAWSErrorSnapshotNotFound(err) {
-			return false, ErrNotFound
+			klog.V(5).Infof("DeleteSnapshot: snapshot %q not found, assuming it is already deleted", snapshotID)
+			return true, nil
+		}
+		if isAWSErrorSnapshotInUse(err) {
+			return false, ErrSnapshotInUse
 		}
-		return false, fmt.Errorf("DeleteSnapshot could not delete volume: %v", err)
+		return false, fmt.Errorf("could not delete snapshot %q: %w", snapshotID, err)
 	}
 	return true, nil
 }
 
+// DeleteSnapshots deletes the snapshots, concurrency of them at once, default
+// to DefaultDeleteSnapshotsConcurrency. Like DeleteSnapshot, a snapshot that
+// no longer exists is considered deleted. It returns the error of every
+// snapshot, nil for the deleted ones, and an error if any deletion failed.
+func (c *cloud) DeleteSnapshots(ctx context.Context, snapshotIDs []string, concurrency int) (map[string]error, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	if concurrency < 0 {
+		return nil, fmt.Errorf("invalid concurrency %d: must not be negative", concurrency)
+	}
+	if concurrency == 0 {
+		concurrency = DefaultDeleteSnapshotsConcurrency
+	}
+
+	var (
+		mux  sync.Mutex
+		wg   sync.WaitGroup
+		errs = make(map[string]error, len(snapshotIDs))
+	)
+	ids := make(chan string)
+	for i := 0; i < concurrency && i < len(snapshotIDs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for snapshotID := range ids {
+				_, err := c.DeleteSnapshot(ctx, snapshotID)
+				mux.Lock()
+				errs[snapshotID] = err
+				mux.Unlock()
+			}
+		}()
+	}
+	for _, snapshotID := range snapshotIDs {
+		ids <- snapshotID
+	}
+	close(ids)
+	wg.Wait()
+
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errs, fmt.Errorf("could not delete %d of %d snapshots", failed, len(errs))
+	}
+	return errs, nil
+}
+
 func (c *cloud) GetSnapshotByName(ctx context.Context, name string) (snapshot *Snapshot, err error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	request := &ec2.DescribeSnapshotsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("tag:" + SnapshotNameTagKey),
+				Values: []*string{aws.String(name)},
+			},
+		},
+	}
+
+	ec2snapshot, err := c.getSnapshot(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.ec2SnapshotResponseToStruct(ec2snapshot), nil
+}
+
+// GetNewestSnapshotByName is like GetSnapshotByName, but when several snapshots
+// share the name (e.g. left behind by failed retries) it returns the most
+// recently started completed one instead of ErrMultiSnapshots. It still returns
+// ErrMultiSnapshots if none of the matching snapshots is completed.
+func (c *cloud) GetNewestSnapshotByName(ctx context.Context, name string) (snapshot *Snapshot, err error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
 	request := &ec2.DescribeSnapshotsInput{
 		Filters: []*ec2.Filter{
 			{
@@ -772,70 +2306,609 @@ func (c *cloud) GetSnapshotByName(ctx context.Context, name string) (snapshot *S
 		},
 	}
 
-	ec2snapshot, err := c.getSnapshot(ctx, request)
-	if err != nil {
-		return nil, err
+	ec2snapshots, err := c.getSnapshots(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(ec2snapshots) {
+	case 0:
+		return nil, ErrNotFound
+	case 1:
+		return c.ec2SnapshotResponseToStruct(ec2snapshots[0]), nil
+	}
+
+	var newest *ec2.Snapshot
+	for _, s := range ec2snapshots {
+		if aws.StringValue(s.State) != ec2.SnapshotStateCompleted {
+			continue
+		}
+		if newest == nil || aws.TimeValue(s.StartTime).After(aws.TimeValue(newest.StartTime)) {
+			newest = s
+		}
+	}
+	if newest == nil {
+		return nil, ErrMultiSnapshots
+	}
+
+	klog.Warningf("Found %d snapshots with name %q, using the newest completed one %q", len(ec2snapshots), name, aws.StringValue(newest.SnapshotId))
+	return c.ec2SnapshotResponseToStruct(newest), nil
+}
+
+func (c *cloud) GetSnapshotByID(ctx context.Context, snapshotID string) (snapshot *Snapshot, err error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	if err := validateSnapshotID(snapshotID); err != nil {
+		return nil, err
+	}
+
+	request := &ec2.DescribeSnapshotsInput{
+		SnapshotIds: []*string{
+			aws.String(snapshotID),
+		},
+	}
+
+	ec2snapshot, err := c.getSnapshot(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.ec2SnapshotResponseToStruct(ec2snapshot), nil
+}
+
+// Snapshots of large volumes take well over ten minutes, so their polling has
+// no number of steps: it starts every snapshotPollInterval, growing by half
+// each time up to every snapshotPollMaxInterval, reached after about a minute.
+const (
+	snapshotPollInterval    = 1 * time.Second
+	snapshotPollMaxInterval = 30 * time.Second
+)
+
+// WaitForSnapshotCompleted polls the snapshot until it is completed and
+// returns it, for as long as the context, the operation timeout and Close let
+// it. It fails with ErrSnapshotFailed if the snapshot ends up in the error
+// state.
+func (c *cloud) WaitForSnapshotCompleted(ctx context.Context, snapshotID string) (*Snapshot, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	// Stop waiting as soon as either the caller gives up or the cloud is closed
+	waitCtx, cancelWait := context.WithCancel(ctx)
+	defer cancelWait()
+	go func() {
+		select {
+		case <-c.rootCtx.Done():
+			cancelWait()
+		case <-waitCtx.Done():
+		}
+	}()
+
+	request := &ec2.DescribeSnapshotsInput{
+		SnapshotIds: []*string{
+			aws.String(snapshotID),
+		},
+	}
+
+	var snapshot *ec2.Snapshot
+	err := pollWithCappedBackoff(waitCtx, snapshotPollInterval, snapshotPollMaxInterval, func() (bool, error) {
+		var err error
+		snapshot, err = c.getSnapshot(waitCtx, request)
+		if err != nil {
+			return false, err
+		}
+
+		if s := c.ec2SnapshotResponseToStruct(snapshot); s.IsError() {
+			return false, fmt.Errorf("snapshot %q: %w: %s", snapshotID, ErrSnapshotFailed, aws.StringValue(snapshot.StateMessage))
+		} else if s.ReadyToUse {
+			return true, nil
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not wait for snapshot %q to be completed: %w", snapshotID, err)
+	}
+
+	return c.ec2SnapshotResponseToStruct(snapshot), nil
+}
+
+// GetSnapshotActualSize returns the storage actually consumed by the snapshot,
+// i.e. the size of the blocks it holds, as opposed to the size of its source
+// volume. It is computed from the block listing of the EBS direct APIs.
+func (c *cloud) GetSnapshotActualSize(ctx context.Context, snapshotID string) (int64, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	if c.ebs == nil {
+		return 0, fmt.Errorf("could not get the size of snapshot %q: no EBS client", snapshotID)
+	}
+
+	request := &ebs.ListSnapshotBlocksInput{
+		SnapshotId: aws.String(snapshotID),
+	}
+
+	var blocks, blockSize int64
+	for {
+		response, err := c.ebs.ListSnapshotBlocksWithContext(ctx, request)
+		if err != nil {
+			if isAWSError(err, ebs.ErrCodeResourceNotFoundException) {
+				return 0, ErrNotFound
+			}
+			return 0, fmt.Errorf("could not list blocks of snapshot %q: %w", snapshotID, err)
+		}
+		blocks += int64(len(response.Blocks))
+		blockSize = aws.Int64Value(response.BlockSize)
+		if aws.StringValue(response.NextToken) == "" {
+			break
+		}
+		request.NextToken = response.NextToken
+	}
+
+	return blocks * blockSize, nil
+}
+
+// ListSnapshots retrieves AWS EBS snapshots for an optionally specified volume ID.  If maxResults is set, it will return up to maxResults snapshots.  If there are more snapshots than maxResults,
+// a next token value will be returned to the client as well.  They can use this token with subsequent calls to retrieve the next page of results.  If maxResults is not set (0),
+// there will be no restriction up to 1000 results (https://docs.aws.amazon.com/sdk-for-go/api/service/ec2/#DescribeSnapshotsInput).
+func (c *cloud) ListSnapshots(ctx context.Context, volumeID string, maxResults int64, nextToken string) (listSnapshotsResponse *ListSnapshotsResponse, err error) {
+	return c.ListSnapshotsWithOptions(ctx, &ListSnapshotsOptions{
+		VolumeID:   volumeID,
+		MaxResults: maxResults,
+		NextToken:  nextToken,
+	})
+}
+
+// ListSnapshotsWithOptions lists a page of the snapshots matching the filters
+// of the options. The next page is listed with the returned NextToken.
+func (c *cloud) ListSnapshotsWithOptions(ctx context.Context, options *ListSnapshotsOptions) (listSnapshotsResponse *ListSnapshotsResponse, err error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	if options.MaxResults > 0 && options.MaxResults < 5 {
+		return nil, ErrInvalidMaxResults
+	}
+
+	describeSnapshotsInput := &ec2.DescribeSnapshotsInput{
+		MaxResults: aws.Int64(options.MaxResults),
+		OwnerIds:   c.snapshotOwnerIDs(),
+	}
+
+	if len(options.NextToken) != 0 {
+		describeSnapshotsInput.NextToken = aws.String(options.NextToken)
+	}
+	if len(options.VolumeID) != 0 {
+		describeSnapshotsInput.Filters = append(describeSnapshotsInput.Filters, &ec2.Filter{
+			Name:   aws.String("volume-id"),
+			Values: []*string{aws.String(options.VolumeID)},
+		})
+	}
+	describeSnapshotsInput.Filters = append(describeSnapshotsInput.Filters, buildTagFilters(options.Tags)...)
+
+	ec2SnapshotsResponse, err := c.listSnapshots(ctx, describeSnapshotsInput)
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []*Snapshot
+	for _, ec2Snapshot := range ec2SnapshotsResponse.Snapshots {
+		snapshots = append(snapshots, c.ec2SnapshotResponseToStruct(ec2Snapshot))
+	}
+
+	if len(snapshots) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return &ListSnapshotsResponse{
+		Snapshots: snapshots,
+		NextToken: aws.StringValue(ec2SnapshotsResponse.NextToken),
+	}, nil
+}
+
+// ListSnapshotsOlderThan returns all the snapshots owned by the account,
+// carrying the tags of tagFilters, that were started before cutoff. It pages
+// through the snapshots itself, e.g. to find the candidates for deletion of a
+// retention policy.
+func (c *cloud) ListSnapshotsOlderThan(ctx context.Context, cutoff time.Time, tagFilters map[string]string) ([]*Snapshot, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	request := &ec2.DescribeSnapshotsInput{
+		OwnerIds: []*string{aws.String("self")},
+		Filters:  buildTagFilters(tagFilters),
+	}
+
+	ec2Snapshots, err := c.getSnapshots(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []*Snapshot
+	for _, ec2Snapshot := range ec2Snapshots {
+		if aws.TimeValue(ec2Snapshot.StartTime).Before(cutoff) {
+			snapshots = append(snapshots, c.ec2SnapshotResponseToStruct(ec2Snapshot))
+		}
+	}
+
+	return snapshots, nil
+}
+
+// buildTagSpecifications returns the tag specifications applying the tags to
+// the created resource of the given type. It returns nil if there are no tags,
+// as EC2 rejects tag specifications without any tag.
+func buildTagSpecifications(resourceType string, tags map[string]string) []*ec2.TagSpecification {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	return []*ec2.TagSpecification{
+		{
+			ResourceType: aws.String(resourceType),
+			Tags:         buildTags(tags),
+		},
+	}
+}
+
+// buildTags returns the EC2 tags, sorted by key.
+func buildTags(tags map[string]string) []*ec2.Tag {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	ec2Tags := make([]*ec2.Tag, 0, len(keys))
+	for _, key := range keys {
+		ec2Tags = append(ec2Tags, &ec2.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(tags[key]),
+		})
+	}
+	return ec2Tags
+}
+
+// buildTagFilters returns the filters matching the resources carrying all
+// the tags, sorted by key.
+func buildTagFilters(tags map[string]string) []*ec2.Filter {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	filters := make([]*ec2.Filter, 0, len(tags))
+	for _, k := range keys {
+		filters = append(filters, &ec2.Filter{
+			Name:   aws.String("tag:" + k),
+			Values: []*string{aws.String(tags[k])},
+		})
+	}
+	return filters
+}
+
+// ModifyDiskTags adds or updates the tags of addOrUpdate on the volume, and
+// removes the tags with the keys of remove.
+func (c *cloud) ModifyDiskTags(ctx context.Context, volumeID string, addOrUpdate map[string]string, remove []string) error {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	err := c.modifyTags(ctx, volumeID, addOrUpdate, remove)
+	c.volumeCache.invalidate(volumeID)
+	if err != nil {
+		if isAWSErrorVolumeNotFound(err) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("could not modify tags of volume %q: %w", volumeID, err)
+	}
+	return nil
+}
+
+// tagBatchSize is the maximum number of volumes described or tagged at once
+// by BatchReconcileVolumeTags.
+const tagBatchSize = 200
+
+// isOwnedVolumeTag reports whether the volume tag is set by AWS or the driver
+// rather than by the user: the tags with the reserved AWS or Kubernetes
+// prefixes, the volume name tag and the extra tags of the options. The lookups
+// of the volumes by name or by tag depend on them.
+func (c *cloud) isOwnedVolumeTag(key string) bool {
+	if key == VolumeNameTagKey || strings.HasPrefix(key, AWSTagKeyPrefix) || strings.HasPrefix(key, KubernetesTagKeyPrefix) {
+		return true
+	}
+	_, ok := c.options.extraTags[key]
+	return ok
+}
+
+// BatchReconcileVolumeTags sets the tags of each volume of volumeTags to its
+// desired tags: the missing or different tags are created, the other tags are
+// deleted, except the ones owned by AWS or the driver, see isOwnedVolumeTag.
+// The desired tags are thus only the user tags. The volumes needing
+// the same tags created, or deleted, share the CreateTags, or DeleteTags,
+// calls. The error of each volume is returned, nil if it was reconciled, along
+// with an error if any volume failed.
+func (c *cloud) BatchReconcileVolumeTags(ctx context.Context, volumeTags map[string]map[string]string) (map[string]error, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	errs := make(map[string]error, len(volumeTags))
+	volumeIDs := make([]string, 0, len(volumeTags))
+	for volumeID, tags := range volumeTags {
+		if err := validateTags(tags); err != nil {
+			errs[volumeID] = fmt.Errorf("invalid tags: %w", err)
+			continue
+		}
+		volumeIDs = append(volumeIDs, volumeID)
+	}
+	sort.Strings(volumeIDs)
+
+	// The volumes are filtered by ID rather than described by ID, so that a
+	// missing volume doesn't fail the whole batch
+	actualTags := make(map[string]map[string]string, len(volumeIDs))
+	for start := 0; start < len(volumeIDs); start += tagBatchSize {
+		end := start + tagBatchSize
+		if end > len(volumeIDs) {
+			end = len(volumeIDs)
+		}
+		request := &ec2.DescribeVolumesInput{
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String("volume-id"),
+					Values: aws.StringSlice(volumeIDs[start:end]),
+				},
+			},
+		}
+		volumes, err := c.getVolumes(ctx, request)
+		if err != nil {
+			return nil, fmt.Errorf("could not describe volumes: %w", err)
+		}
+		for _, volume := range volumes {
+			tags := make(map[string]string, len(volume.Tags))
+			for _, tag := range volume.Tags {
+				tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+			}
+			actualTags[aws.StringValue(volume.VolumeId)] = tags
+		}
+	}
+
+	// Group the volumes by the tags to create and by the tag keys to delete
+	var (
+		createGroups = make(map[string][]string)
+		createTags   = make(map[string]map[string]string)
+		deleteGroups = make(map[string][]string)
+		deleteKeys   = make(map[string][]string)
+	)
+	for _, volumeID := range volumeIDs {
+		actual, ok := actualTags[volumeID]
+		if !ok {
+			errs[volumeID] = ErrNotFound
+			continue
+		}
+		errs[volumeID] = nil
+
+		desired := volumeTags[volumeID]
+		toCreate := make(map[string]string)
+		for key, value := range desired {
+			if actualValue, ok := actual[key]; !ok || actualValue != value {
+				toCreate[key] = value
+			}
+		}
+		var toDelete []string
+		for key := range actual {
+			if _, ok := desired[key]; !ok && !c.isOwnedVolumeTag(key) {
+				toDelete = append(toDelete, key)
+			}
+		}
+		sort.Strings(toDelete)
+
+		if len(toCreate) > 0 {
+			groupKey := tagGroupKey(toCreate)
+			createGroups[groupKey] = append(createGroups[groupKey], volumeID)
+			createTags[groupKey] = toCreate
+		}
+		if len(toDelete) > 0 {
+			groupKey := strings.Join(toDelete, "\x00")
+			deleteGroups[groupKey] = append(deleteGroups[groupKey], volumeID)
+			deleteKeys[groupKey] = toDelete
+		}
+	}
+
+	for groupKey, groupVolumeIDs := range createGroups {
+		c.batchTagCall(groupVolumeIDs, errs, func(ids []string) error {
+			request := &ec2.CreateTagsInput{
+				Resources: aws.StringSlice(ids),
+				Tags:      buildTags(createTags[groupKey]),
+			}
+			_, err := c.ec2.CreateTagsWithContext(ctx, request)
+			return err
+		})
+	}
+	for groupKey, groupVolumeIDs := range deleteGroups {
+		c.batchTagCall(groupVolumeIDs, errs, func(ids []string) error {
+			tags := make([]*ec2.Tag, 0, len(deleteKeys[groupKey]))
+			for _, key := range deleteKeys[groupKey] {
+				tags = append(tags, &ec2.Tag{Key: aws.String(key)})
+			}
+			request := &ec2.DeleteTagsInput{
+				Resources: aws.StringSlice(ids),
+				Tags:      tags,
+			}
+			_, err := c.ec2.DeleteTagsWithContext(ctx, request)
+			return err
+		})
+	}
+
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errs, fmt.Errorf("could not reconcile the tags of %d of %d volumes", failed, len(errs))
+	}
+	return errs, nil
+}
+
+// batchTagCall calls tag on the volumes by batches of tagBatchSize, recording
+// the error of a failed batch for each of its volumes that didn't fail yet.
+func (c *cloud) batchTagCall(volumeIDs []string, errs map[string]error, tag func(ids []string) error) {
+	for start := 0; start < len(volumeIDs); start += tagBatchSize {
+		end := start + tagBatchSize
+		if end > len(volumeIDs) {
+			end = len(volumeIDs)
+		}
+		ids := volumeIDs[start:end]
+		err := tag(ids)
+		for _, volumeID := range ids {
+			c.volumeCache.invalidate(volumeID)
+			if err != nil && errs[volumeID] == nil {
+				errs[volumeID] = fmt.Errorf("could not modify tags of volume %q: %w", volumeID, err)
+			}
+		}
+	}
+}
+
+// tagGroupKey returns a key identifying the set of tags.
+func tagGroupKey(tags map[string]string) string {
+	var key strings.Builder
+	for _, tag := range buildTags(tags) {
+		key.WriteString(aws.StringValue(tag.Key))
+		key.WriteString("=")
+		key.WriteString(aws.StringValue(tag.Value))
+		key.WriteString("\x00")
+	}
+	return key.String()
+}
+
+// ModifySnapshotTags adds or updates the tags of addOrUpdate on the snapshot,
+// and removes the tags with the keys of remove.
+func (c *cloud) ModifySnapshotTags(ctx context.Context, snapshotID string, addOrUpdate map[string]string, remove []string) error {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	if err := c.modifyTags(ctx, snapshotID, addOrUpdate, remove); err != nil {
+		if isAWSErrorSnapshotNotFound(err) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("could not modify tags of snapshot %q: %w", snapshotID, err)
+	}
+	return nil
+}
+
+// modifyTags creates the tags of addOrUpdate on the resource and deletes the
+// tags with the keys of remove. The AWS errors are returned as is.
+func (c *cloud) modifyTags(ctx context.Context, resourceID string, addOrUpdate map[string]string, remove []string) error {
+	if err := validateTags(addOrUpdate); err != nil {
+		return fmt.Errorf("invalid tags: %w", err)
+	}
+	for _, key := range remove {
+		if strings.HasPrefix(key, AWSTagKeyPrefix) {
+			return fmt.Errorf("invalid tags: tag key %q uses the reserved prefix '%s'", key, AWSTagKeyPrefix)
+		}
+	}
+
+	if len(addOrUpdate) > 0 {
+		request := &ec2.CreateTagsInput{
+			Resources: []*string{aws.String(resourceID)},
+			Tags:      buildTags(addOrUpdate),
+		}
+		if _, err := c.ec2.CreateTagsWithContext(ctx, request); err != nil {
+			return err
+		}
+	}
+
+	if len(remove) > 0 {
+		tags := make([]*ec2.Tag, 0, len(remove))
+		for _, key := range remove {
+			tags = append(tags, &ec2.Tag{Key: aws.String(key)})
+		}
+		request := &ec2.DeleteTagsInput{
+			Resources: []*string{aws.String(resourceID)},
+			Tags:      tags,
+		}
+		if _, err := c.ec2.DeleteTagsWithContext(ctx, request); err != nil {
+			return err
+		}
 	}
 
-	return c.ec2SnapshotResponseToStruct(ec2snapshot), nil
+	return nil
 }
 
-func (c *cloud) GetSnapshotByID(ctx context.Context, snapshotID string) (snapshot *Snapshot, err error) {
-	request := &ec2.DescribeSnapshotsInput{
-		SnapshotIds: []*string{
-			aws.String(snapshotID),
-		},
-	}
+// volumeIDPattern matches the volume IDs, whose digits are upper case in some
+// EC2 compatible clouds.
+var volumeIDPattern = regexp.MustCompile(`^vol-[0-9a-fA-F]+$`)
 
-	ec2snapshot, err := c.getSnapshot(ctx, request)
-	if err != nil {
-		return nil, err
+// validateVolumeID returns an error if the ID isn't a well-formed volume ID,
+// "vol-" followed by hexadecimal digits, to fail before EC2 rejects it with an
+// obscure InvalidParameterValue.
+func validateVolumeID(volumeID string) error {
+	if !volumeIDPattern.MatchString(volumeID) {
+		return fmt.Errorf("invalid volume ID %q: expected \"vol-\" followed by hexadecimal digits", volumeID)
 	}
-
-	return c.ec2SnapshotResponseToStruct(ec2snapshot), nil
+	return nil
 }
 
-// ListSnapshots retrieves AWS EBS snapshots for an optionally specified volume ID.  If maxResults is set, it will return up to maxResults snapshots.  If there are more snapshots than maxResults,
-// a next token value will be returned to the client as well.  They can use this token with subsequent calls to retrieve the next page of results.  If maxResults is not set (0),
-// there will be no restriction up to 1000 results (https://docs.aws.amazon.com/sdk-for-go/api/service/ec2/#DescribeSnapshotsInput).
-func (c *cloud) ListSnapshots(ctx context.Context, volumeID string, maxResults int64, nextToken string) (listSnapshotsResponse *ListSnapshotsResponse, err error) {
-	if maxResults > 0 && maxResults < 5 {
-		return nil, ErrInvalidMaxResults
-	}
+// snapshotIDPattern matches the snapshot IDs, like volumeIDPattern.
+var snapshotIDPattern = regexp.MustCompile(`^snap-[0-9a-fA-F]+$`)
 
-	describeSnapshotsInput := &ec2.DescribeSnapshotsInput{
-		MaxResults: aws.Int64(maxResults),
+// validateSnapshotID returns an error if the ID isn't a well-formed snapshot
+// ID, "snap-" followed by hexadecimal digits.
+func validateSnapshotID(snapshotID string) error {
+	if !snapshotIDPattern.MatchString(snapshotID) {
+		return fmt.Errorf("invalid snapshot ID %q: expected \"snap-\" followed by hexadecimal digits", snapshotID)
 	}
+	return nil
+}
 
-	if len(nextToken) != 0 {
-		describeSnapshotsInput.NextToken = aws.String(nextToken)
+// validateTags checks the tags against the AWS tag restrictions, naming the
+// offending tag key, so that they aren't rejected by EC2 as a whole.
+func validateTags(tags map[string]string) error {
+	if len(tags) > MaxNumTagsPerResource {
+		return fmt.Errorf("too many tags (actual: %d, limit: %d)", len(tags), MaxNumTagsPerResource)
 	}
-	if len(volumeID) != 0 {
-		describeSnapshotsInput.Filters = []*ec2.Filter{
-			{
-				Name:   aws.String("volume-id"),
-				Values: []*string{aws.String(volumeID)},
-			},
+
+	for k, v := range tags {
+		if len(k) > MaxTagKeyLength {
+			return fmt.Errorf("tag key %q too long (actual: %d, limit: %d)", k, len(k), MaxTagKeyLength)
+		}
+		if len(v) > MaxTagValueLength {
+			return fmt.Errorf("tag value of key %q too long (actual: %d, limit: %d)", k, len(v), MaxTagValueLength)
+		}
+		if strings.HasPrefix(k, AWSTagKeyPrefix) {
+			return fmt.Errorf("tag key %q uses the reserved prefix '%s'", k, AWSTagKeyPrefix)
 		}
 	}
 
-	ec2SnapshotsResponse, err := c.listSnapshots(ctx, describeSnapshotsInput)
-	if err != nil {
-		return nil, err
+	return nil
+}
+
+// rejectedTagKey returns the key of the tag an AWS tag error is about. EC2
+// doesn't report it in a field, so the error message is searched for the
+// tag keys, the longest first so that a key isn't mistaken for its prefix.
+func rejectedTagKey(err error, tags map[string]string) (string, bool) {
+	awsErr, ok := err.(awserr.Error)
+	if !ok {
+		return "", false
 	}
-	var snapshots []*Snapshot
-	for _, ec2Snapshot := range ec2SnapshotsResponse.Snapshots {
-		snapshots = append(snapshots, c.ec2SnapshotResponseToStruct(ec2Snapshot))
+	if !strings.Contains(strings.ToLower(awsErr.Code()+" "+awsErr.Message()), "tag") {
+		return "", false
 	}
 
-	if len(snapshots) == 0 {
-		return nil, ErrNotFound
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
 	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
 
-	return &ListSnapshotsResponse{
-		Snapshots: snapshots,
-		NextToken: aws.StringValue(ec2SnapshotsResponse.NextToken),
-	}, nil
+	for _, key := range keys {
+		if strings.Contains(awsErr.Message(), key) {
+			return key, true
+		}
+	}
+	return "", false
 }
 
 // Helper method converting EC2 snapshot type to the internal struct
@@ -849,6 +2922,9 @@ func (c *cloud) ec2SnapshotResponseToStruct(ec2Snapshot *ec2.Snapshot) *Snapshot
 		SourceVolumeID: aws.StringValue(ec2Snapshot.VolumeId),
 		Size:           snapshotSize,
 		CreationTime:   aws.TimeValue(ec2Snapshot.StartTime),
+		Progress:       snapshotProgress(ec2Snapshot),
+		SourceRegion:   snapshotSourceRegion(ec2Snapshot),
+		State:          aws.StringValue(ec2Snapshot.State),
 	}
 	if aws.StringValue(ec2Snapshot.State) == "completed" {
 		snapshot.ReadyToUse = true
@@ -859,11 +2935,143 @@ func (c *cloud) ec2SnapshotResponseToStruct(ec2Snapshot *ec2.Snapshot) *Snapshot
 	return snapshot
 }
 
+// snapshotProgress returns the completion percentage of the snapshot, parsed
+// from its progress like "43%", or 0 if it is missing or malformed.
+func snapshotProgress(ec2Snapshot *ec2.Snapshot) int {
+	progress := strings.TrimSpace(aws.StringValue(ec2Snapshot.Progress))
+	if !strings.HasSuffix(progress, "%") {
+		return 0
+	}
+	percent, err := strconv.Atoi(strings.TrimSuffix(progress, "%"))
+	if err != nil || percent < 0 || percent > 100 {
+		return 0
+	}
+	return percent
+}
+
+// copiedSnapshotDescription matches the description EC2 gives to the
+// snapshots copied without one, capturing the source region.
+var copiedSnapshotDescription = regexp.MustCompile(`^\[Copied snap-[0-9a-f]+ from ([a-z0-9-]+)\]`)
+
+// snapshotSourceRegion returns the region the data of the snapshot originates
+// from, as recorded in its tags or in the description of a copy, empty if
+// unknown.
+func snapshotSourceRegion(ec2Snapshot *ec2.Snapshot) string {
+	for _, tag := range ec2Snapshot.Tags {
+		if aws.StringValue(tag.Key) == SnapshotSourceRegionTagKey {
+			return aws.StringValue(tag.Value)
+		}
+	}
+	if match := copiedSnapshotDescription.FindStringSubmatch(aws.StringValue(ec2Snapshot.Description)); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// Helper method converting EC2 volume type to the internal struct
+func (c *cloud) ec2VolumeResponseToStruct(volume *ec2.Volume) *Disk {
+	if volume == nil {
+		return nil
+	}
+	return &Disk{
+		VolumeID:         aws.StringValue(volume.VolumeId),
+		CapacityGiB:      aws.Int64Value(volume.Size),
+		AvailabilityZone: aws.StringValue(volume.AvailabilityZone),
+		KmsKeyID:         aws.StringValue(volume.KmsKeyId),
+		IOPS:             aws.Int64Value(volume.Iops),
+	}
+}
+
 func (c *cloud) getVolume(ctx context.Context, request *ec2.DescribeVolumesInput) (*ec2.Volume, error) {
+	volumes, err := c.getVolumes(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if l := len(volumes); l > 1 {
+		return c.resolveMultiDisks(volumes)
+	} else if l < 1 {
+		return nil, ErrNotFound
+	}
+
+	return volumes[0], nil
+}
+
+// getVolumeOfSize is getVolume for the lookups expecting a volume of the size
+// in GiB: with MultiDiskStrategyMatchingSize, the volumes of another size are
+// left out when several volumes match, ErrDiskExistsDiffSize if all of them
+// are.
+func (c *cloud) getVolumeOfSize(ctx context.Context, request *ec2.DescribeVolumesInput, sizeGiB int64) (*ec2.Volume, error) {
+	if c.options.multiDiskStrategy != MultiDiskStrategyMatchingSize {
+		return c.getVolume(ctx, request)
+	}
+
+	volumes, err := c.getVolumes(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(volumes) > 1 {
+		var matching []*ec2.Volume
+		for _, v := range volumes {
+			if aws.Int64Value(v.Size) == sizeGiB {
+				matching = append(matching, v)
+			}
+		}
+		if len(matching) == 0 {
+			return nil, ErrDiskExistsDiffSize
+		}
+		volumes = matching
+	}
+
+	if l := len(volumes); l > 1 {
+		return c.resolveMultiDisks(volumes)
+	} else if l < 1 {
+		return nil, ErrNotFound
+	}
+
+	return volumes[0], nil
+}
+
+// resolveMultiDisks picks one of several volumes matching a lookup, according
+// to the configured strategy.
+func (c *cloud) resolveMultiDisks(volumes []*ec2.Volume) (*ec2.Volume, error) {
+	var pick func(a, b *ec2.Volume) bool
+	switch c.options.multiDiskStrategy {
+	case MultiDiskStrategyOldest, MultiDiskStrategyMatchingSize:
+		pick = func(a, b *ec2.Volume) bool { return aws.TimeValue(a.CreateTime).Before(aws.TimeValue(b.CreateTime)) }
+	case MultiDiskStrategyNewest:
+		pick = func(a, b *ec2.Volume) bool { return aws.TimeValue(a.CreateTime).After(aws.TimeValue(b.CreateTime)) }
+	default:
+		return nil, ErrMultiDisks
+	}
+
+	picked := volumes[0]
+	for _, v := range volumes[1:] {
+		if pick(v, picked) {
+			picked = v
+		}
+	}
+
+	klog.Warningf("Found %d volumes matching the same name, using %q (strategy: %s)", len(volumes), aws.StringValue(picked.VolumeId), c.options.multiDiskStrategy)
+	return picked, nil
+}
+
+// getVolumes returns all volumes matching the request, following pagination.
+func (c *cloud) getVolumes(ctx context.Context, request *ec2.DescribeVolumesInput) ([]*ec2.Volume, error) {
 	var volumes []*ec2.Volume
 	var nextToken *string
 
+	if c.options.volumesMaxResults > 0 && len(request.VolumeIds) == 0 {
+		request.MaxResults = aws.Int64(c.options.volumesMaxResults)
+	}
+
 	for {
+		// Don't issue a doomed call if the caller already gave up
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		response, err := c.ec2.DescribeVolumesWithContext(ctx, request)
 		if err != nil {
 			return nil, err
@@ -876,29 +3084,59 @@ func (c *cloud) getVolume(ctx context.Context, request *ec2.DescribeVolumesInput
 		request.NextToken = nextToken
 	}
 
-	if l := len(volumes); l > 1 {
-		return nil, ErrMultiDisks
+	return volumes, nil
+}
+
+// getInstance returns the instance, or ErrNotFound if it does not exist or
+// lacks the instance cluster tag set in the options.
+func (c *cloud) getInstance(ctx context.Context, nodeID string) (*ec2.Instance, error) {
+	var filters []*ec2.Filter
+	if c.options.instanceClusterTagKey != "" {
+		filters = append(filters, tagFilter(c.options.instanceClusterTagKey, c.options.instanceClusterTagValue))
+	}
+	return c.describeInstance(ctx, nodeID, filters)
+}
+
+func (c *cloud) describeInstance(ctx context.Context, nodeID string, filters []*ec2.Filter) (*ec2.Instance, error) {
+	request := &ec2.DescribeInstancesInput{
+		InstanceIds: []*string{&nodeID},
+		Filters:     filters,
+	}
+
+	instances, err := c.getInstances(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if l := len(instances); l > 1 {
+		return nil, fmt.Errorf("found %d instances with ID %q", l, nodeID)
 	} else if l < 1 {
 		return nil, ErrNotFound
 	}
 
-	return volumes[0], nil
+	return instances[0], nil
 }
 
-func (c *cloud) getInstance(ctx context.Context, nodeID string) (*ec2.Instance, error) {
+// getInstances returns all instances matching the request, following pagination.
+func (c *cloud) getInstances(ctx context.Context, request *ec2.DescribeInstancesInput) ([]*ec2.Instance, error) {
 	instances := []*ec2.Instance{}
-	request := &ec2.DescribeInstancesInput{
-		InstanceIds: []*string{&nodeID},
+	var nextToken *string
+
+	if c.options.instancesMaxResults > 0 && len(request.InstanceIds) == 0 {
+		request.MaxResults = aws.Int64(c.options.instancesMaxResults)
 	}
 
-	var nextToken *string
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		response, err := c.ec2.DescribeInstancesWithContext(ctx, request)
 		if err != nil {
 			if isAWSErrorInstanceNotFound(err) {
 				return nil, ErrNotFound
 			}
-			return nil, fmt.Errorf("error listing AWS instances: %q", err)
+			return nil, newCloudError(err, "error listing AWS instances")
 		}
 
 		for _, reservation := range response.Reservations {
@@ -912,19 +3150,48 @@ func (c *cloud) getInstance(ctx context.Context, nodeID string) (*ec2.Instance,
 		request.NextToken = nextToken
 	}
 
-	if l := len(instances); l > 1 {
-		return nil, fmt.Errorf("found %d instances with ID %q", l, nodeID)
+	return instances, nil
+}
+
+// snapshotOwnerIDs returns the owners the listed snapshots are limited to:
+// the account itself, unless the shared snapshots are included. The lookups
+// of a given snapshot aren't limited, so that volumes can still be created
+// from public or shared snapshots.
+func (c *cloud) snapshotOwnerIDs() []*string {
+	if c.options.includeSharedSnapshots {
+		return nil
+	}
+	return []*string{aws.String("self")}
+}
+
+func (c *cloud) getSnapshot(ctx context.Context, request *ec2.DescribeSnapshotsInput) (*ec2.Snapshot, error) {
+	snapshots, err := c.getSnapshots(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if l := len(snapshots); l > 1 {
+		return nil, ErrMultiSnapshots
 	} else if l < 1 {
 		return nil, ErrNotFound
 	}
 
-	return instances[0], nil
+	return snapshots[0], nil
 }
 
-func (c *cloud) getSnapshot(ctx context.Context, request *ec2.DescribeSnapshotsInput) (*ec2.Snapshot, error) {
+// getSnapshots returns all snapshots matching the request, following pagination.
+func (c *cloud) getSnapshots(ctx context.Context, request *ec2.DescribeSnapshotsInput) ([]*ec2.Snapshot, error) {
 	var snapshots []*ec2.Snapshot
 	var nextToken *string
+
+	if c.options.snapshotsMaxResults > 0 && len(request.SnapshotIds) == 0 {
+		request.MaxResults = aws.Int64(c.options.snapshotsMaxResults)
+	}
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		response, err := c.ec2.DescribeSnapshotsWithContext(ctx, request)
 		if err != nil {
 			return nil, err
@@ -937,13 +3204,7 @@ func (c *cloud) getSnapshot(ctx context.Context, request *ec2.DescribeSnapshotsI
 		request.NextToken = nextToken
 	}
 
-	if l := len(snapshots); l > 1 {
-		return nil, ErrMultiSnapshots
-	} else if l < 1 {
-		return nil, ErrNotFound
-	}
-
-	return snapshots[0], nil
+	return snapshots, nil
 }
 
 // listSnapshots returns all snapshots based from a request
@@ -968,9 +3229,39 @@ func (c *cloud) listSnapshots(ctx context.Context, request *ec2.DescribeSnapshot
 	}, nil
 }
 
-// waitForVolume waits for volume to be in the "available" state.
+// checkSnapshotSize verifies that a disk of capacityGiB can be restored from
+// the given snapshot, so an undersized request fails before reaching EC2.
+func (c *cloud) checkSnapshotSize(ctx context.Context, snapshotID string, capacityGiB int64) error {
+	request := &ec2.DescribeSnapshotsInput{
+		SnapshotIds: []*string{
+			aws.String(snapshotID),
+		},
+	}
+
+	snapshot, err := c.getSnapshot(ctx, request)
+	if err != nil {
+		if isAWSErrorSnapshotNotFound(err) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	snapshotSizeGiB := aws.Int64Value(snapshot.VolumeSize)
+	if capacityGiB < snapshotSizeGiB {
+		klog.V(5).Infof("Requested size %d GiB is smaller than snapshot %q size %d GiB", capacityGiB, snapshotID, snapshotSizeGiB)
+		return ErrDiskSmallerThanSnapshot
+	}
+
+	return nil
+}
+
+// volumeNotFoundGracePeriod is how long after its creation a volume is waited
+// for while EC2 reports it as not found, because of its eventual consistency.
+const volumeNotFoundGracePeriod = 15 * time.Second
+
+// waitForVolume waits for volume to be in the "available" state and returns it.
 // On a random AWS account (shared among several developers) it took 4s on average.
-func (c *cloud) waitForVolume(ctx context.Context, volumeID string) error {
+func (c *cloud) waitForVolume(ctx context.Context, volumeID string) (*ec2.Volume, error) {
 	var (
 		checkInterval = 3 * time.Second
 		// This timeout can be "ovewritten" if the value returned by ctx.Deadline()
@@ -984,25 +3275,44 @@ func (c *cloud) waitForVolume(ctx context.Context, volumeID string) error {
 		},
 	}
 
-	err := wait.Poll(checkInterval, checkTimeout, func() (done bool, err error) {
+	pollCtx, cancel := context.WithTimeout(c.rootCtx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	var volume *ec2.Volume
+	err := wait.PollUntil(checkInterval, func() (done bool, err error) {
 		vol, err := c.getVolume(ctx, request)
 		if err != nil {
+			// A volume just created may not be visible yet
+			if (err == ErrNotFound || isAWSErrorVolumeNotFound(err)) && time.Since(start) < volumeNotFoundGracePeriod {
+				klog.V(4).Infof("Volume %q not found yet, waiting for it to be visible: %v", volumeID, err)
+				return false, nil
+			}
 			return true, err
 		}
+		volume = vol
 		if vol.State != nil {
 			return *vol.State == "available", nil
 		}
 		return false, nil
-	})
+	}, pollCtx.Done())
 
-	return err
+	if err == wait.ErrWaitTimeout && c.rootCtx.Err() != nil {
+		return nil, c.rootCtx.Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return volume, nil
 }
 
 // isAWSError returns a boolean indicating whether the error is AWS-related
-// and has the given code. More information on AWS error codes at:
+// and has the given code, the AWS error possibly being wrapped, e.g. in a
+// *CloudError. More information on AWS error codes at:
 // https://docs.aws.amazon.com/AWSEC2/latest/APIReference/errors-overview.html
 func isAWSError(err error, code string) bool {
-	if awsError, ok := err.(awserr.Error); ok {
+	var awsError awserr.Error
+	if errors.As(err, &awsError) {
 		if awsError.Code() == code {
 			return true
 		}
@@ -1010,6 +3320,13 @@ func isAWSError(err error, code string) bool {
 	return false
 }
 
+// isAWSErrorModificationTooFrequent returns a boolean indicating whether the
+// given error is an AWS error rejecting a modification of an EBS volume
+// modified less than 6 hours ago.
+func isAWSErrorModificationTooFrequent(err error) bool {
+	return isAWSError(err, "VolumeModificationRateExceeded")
+}
+
 // isAWSErrorIncorrectModification returns a boolean indicating whether the given error
 // is an AWS IncorrectModificationState error. This error means that a modification action
 // on an EBS volume cannot occur because the volume is currently being modified.
@@ -1024,6 +3341,20 @@ func isAWSErrorInstanceNotFound(err error) bool {
 	return isAWSError(err, "InvalidInstanceID.NotFound")
 }
 
+// isAWSErrorInvalidInstanceType returns a boolean indicating whether the
+// given error is an AWS InvalidInstanceType error. This error is
+// reported when the specified instance type doesn't exist.
+func isAWSErrorInvalidInstanceType(err error) bool {
+	return isAWSError(err, "InvalidInstanceType")
+}
+
+// isAWSErrorIdempotentParameterMismatch returns a boolean indicating whether
+// the given error is an AWS IdempotentParameterMismatch error. This error is
+// reported when a client token is reused with different parameters.
+func isAWSErrorIdempotentParameterMismatch(err error) bool {
+	return isAWSError(err, "IdempotentParameterMismatch")
+}
+
 // isAWSErrorVolumeNotFound returns a boolean indicating whether the
 // given error is an AWS InvalidVolume.NotFound error. This error is
 // reported when the specified volume doesn't exist.
@@ -1052,9 +3383,39 @@ func isAWSErrorSnapshotNotFound(err error) bool {
 	return isAWSError(err, "InvalidSnapshot.NotFound")
 }
 
+// isAWSErrorSnapshotInUse returns a boolean indicating whether the given
+// error is an AWS InvalidSnapshot.InUse error. This error is reported when
+// deleting a snapshot in use, e.g. by a volume being created from it.
+func isAWSErrorSnapshotInUse(err error) bool {
+	return isAWSError(err, "InvalidSnapshot.InUse")
+}
+
+// isAWSErrorUnsupportedVolumeType returns a boolean indicating whether the
+// given error reports the volume type as unsupported in the availability zone.
+// InvalidParameterValue isn't matched, as it also reports e.g. invalid sizes
+// or IOPS.
+func isAWSErrorUnsupportedVolumeType(err error) bool {
+	return isAWSError(err, "UnsupportedOperation") || isAWSError(err, "Unsupported")
+}
+
 // ResizeDisk resizes an EBS volume in GiB increments, rouding up to the next possible allocatable unit.
 // It returns the volume size after this call or an error if the size couldn't be determined.
 func (c *cloud) ResizeDisk(ctx context.Context, volumeID string, newSizeBytes int64) (int64, error) {
+	return c.ModifyDisk(ctx, volumeID, &ModifyOptions{SizeBytes: newSizeBytes})
+}
+
+// ModifyDisk applies all the changes of the options to the volume in a single
+// modification, since AWS allows only one modification per volume every 6
+// hours, and waits for it. The options already matching the volume are left
+// out. It returns the size of the volume in GiB.
+func (c *cloud) ModifyDisk(ctx context.Context, volumeID string, options *ModifyOptions) (int64, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	if err := validateVolumeID(volumeID); err != nil {
+		return 0, err
+	}
+
 	request := &ec2.DescribeVolumesInput{
 		VolumeIds: []*string{
 			aws.String(volumeID),
@@ -1065,25 +3426,52 @@ func (c *cloud) ResizeDisk(ctx context.Context, volumeID string, newSizeBytes in
 		return 0, err
 	}
 
-	// AWS resizes in chunks of GiB (not GB)
-	newSizeGiB := util.RoundUpGiB(newSizeBytes)
+	req := &ec2.ModifyVolumeInput{
+		VolumeId: aws.String(volumeID),
+	}
+
 	oldSizeGiB := aws.Int64Value(volume.Size)
+	if options.SizeBytes > 0 {
+		// AWS resizes in chunks of GiB (not GB)
+		newSizeGiB := util.RoundUpGiB(options.SizeBytes)
 
-	if oldSizeGiB >= newSizeGiB {
-		klog.V(5).Infof("Volume %q's current size (%d GiB) is greater or equal to the new size (%d GiB)", volumeID, oldSizeGiB, newSizeGiB)
-		return oldSizeGiB, nil
+		if oldSizeGiB > newSizeGiB {
+			klog.V(5).Infof("Volume %q's current size (%d GiB) is greater than the new size (%d GiB)", volumeID, oldSizeGiB, newSizeGiB)
+			return 0, ErrCannotShrinkVolume
+		}
+		if oldSizeGiB == newSizeGiB {
+			klog.V(5).Infof("Volume %q's current size (%d GiB) is equal to the new size (%d GiB)", volumeID, oldSizeGiB, newSizeGiB)
+		} else {
+			req.Size = aws.Int64(newSizeGiB)
+		}
 	}
 
-	req := &ec2.ModifyVolumeInput{
-		VolumeId: aws.String(volumeID),
-		Size:     aws.Int64(newSizeGiB),
+	if options.IOPS > 0 && options.IOPS != aws.Int64Value(volume.Iops) {
+		if volumeType := aws.StringValue(volume.VolumeType); volumeType != VolumeTypeIO1 && volumeType != VolumeTypeIO2 {
+			return 0, fmt.Errorf("IOPS can only be modified on %s and %s volumes, not %s", VolumeTypeIO1, VolumeTypeIO2, volumeType)
+		}
+		req.Iops = aws.Int64(options.IOPS)
+	}
+
+	if req.Size == nil && req.Iops == nil {
+		return oldSizeGiB, nil
 	}
 
 	var mod *ec2.VolumeModification
 	response, err := c.ec2.ModifyVolumeWithContext(ctx, req)
+	c.volumeCache.invalidate(volumeID)
 	if err != nil {
+		if isAWSErrorModificationTooFrequent(err) {
+			return 0, ErrModificationRateLimited
+		}
+		if isAWSErrorVolumeNotFound(err) {
+			return 0, ErrNotFound
+		}
+		if isAWSErrorIncorrectState(err) {
+			return 0, ErrIncorrectVolumeState
+		}
 		if !isAWSErrorIncorrectModification(err) {
-			return 0, fmt.Errorf("could not modify AWS volume %q: %v", volumeID, err)
+			return 0, newCloudError(err, fmt.Sprintf("could not modify AWS volume %q", volumeID))
 		}
 
 		m, err := c.getLatestVolumeModification(ctx, volumeID)
@@ -1114,7 +3502,7 @@ func (c *cloud) waitForVolumeSize(ctx context.Context, volumeID string) (int64,
 	}
 
 	var modVolSizeGiB int64
-	waitErr := wait.ExponentialBackoff(backoff, func() (bool, error) {
+	waitErr := exponentialBackoff(c.rootCtx, backoff, func() (bool, error) {
 		m, err := c.getLatestVolumeModification(ctx, volumeID)
 		if err != nil {
 			return false, err
@@ -1145,17 +3533,63 @@ func (c *cloud) getLatestVolumeModification(ctx context.Context, volumeID string
 	}
 	mod, err := c.ec2.DescribeVolumesModificationsWithContext(ctx, request)
 	if err != nil {
-		return nil, fmt.Errorf("error describing modifications in volume %q: %v", volumeID, err)
+		return nil, newCloudError(err, fmt.Sprintf("error describing modifications in volume %q", volumeID))
 	}
 
 	volumeMods := mod.VolumesModifications
 	if len(volumeMods) == 0 {
-		return nil, fmt.Errorf("could not find any modifications for volume %q", volumeID)
+		return nil, fmt.Errorf("could not find any modifications for volume %q: %w", volumeID, ErrNotFound)
 	}
 
 	return volumeMods[len(volumeMods)-1], nil
 }
 
+// GetAvailabilityZonesForVolumeType returns the zones of the region in which
+// volumes of the type can be created. There is no API listing them, so the
+// creation is tried in every zone as a dry run.
+func (c *cloud) GetAvailabilityZonesForVolumeType(ctx context.Context, volumeType string) ([]string, error) {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	if aliased, ok := c.volumeTypeAlias(volumeType); ok {
+		volumeType = aliased
+	}
+	if !isValidVolumeType(volumeType) {
+		return nil, fmt.Errorf("invalid AWS VolumeType %q", volumeType)
+	}
+
+	response, err := c.ec2.DescribeAvailabilityZonesWithContext(ctx, &ec2.DescribeAvailabilityZonesInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	zones := []string{}
+	for _, zone := range response.AvailabilityZones {
+		zoneName := aws.StringValue(zone.ZoneName)
+		request := &ec2.CreateVolumeInput{
+			AvailabilityZone: aws.String(zoneName),
+			Size:             aws.Int64(util.BytesToGiB(DefaultVolumeSize)),
+			VolumeType:       aws.String(volumeType),
+			DryRun:           aws.Bool(true),
+		}
+		if volumeType == VolumeTypeIO1 || volumeType == VolumeTypeIO2 {
+			request.Iops = aws.Int64(MinTotalIOPS)
+		}
+
+		_, err := c.ec2.CreateVolumeWithContext(ctx, request)
+		switch {
+		case err == nil, isAWSError(err, "DryRunOperation"):
+			zones = append(zones, zoneName)
+		case isAWSErrorUnsupportedVolumeType(err):
+			klog.V(4).Infof("Volume type %s is not supported in zone %s: %v", volumeType, zoneName, err)
+		default:
+			return nil, fmt.Errorf("could not check volume type %s in zone %s: %w", volumeType, zoneName, err)
+		}
+	}
+
+	return zones, nil
+}
+
 // randomAvailabilityZone returns a random zone from the given region
 // the randomness relies on the response of DescribeAvailabilityZones
 func (c *cloud) randomAvailabilityZone(ctx context.Context, region string) (string, error) {
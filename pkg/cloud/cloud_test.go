@@ -20,11 +20,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ebs"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/golang/mock/gomock"
 	dm "github.com/c2devel/aws-ebs-csi-driver/pkg/cloud/devicemanager"
@@ -50,13 +61,13 @@ func TestCreateDisk(t *testing.T) {
 	}{
 		{
 			name:       "success: normal",
-			volumeName: "vol-test-name",
+			volumeName: "vol-000000ae",
 			diskOptions: &DiskOptions{
 				CapacityBytes: util.GiBToBytes(1),
-				Tags:          map[string]string{VolumeNameTagKey: "vol-test"},
+				Tags:          map[string]string{VolumeNameTagKey: "vol-000000a8"},
 			},
 			expDisk: &Disk{
-				VolumeID:         "vol-test",
+				VolumeID:         "vol-000000a8",
 				CapacityGiB:      1,
 				AvailabilityZone: defaultZone,
 			},
@@ -64,14 +75,14 @@ func TestCreateDisk(t *testing.T) {
 		},
 		{
 			name:       "success: normal with provided zone",
-			volumeName: "vol-test-name",
+			volumeName: "vol-000000ae",
 			diskOptions: &DiskOptions{
 				CapacityBytes:    util.GiBToBytes(1),
-				Tags:             map[string]string{VolumeNameTagKey: "vol-test"},
+				Tags:             map[string]string{VolumeNameTagKey: "vol-000000a8"},
 				AvailabilityZone: expZone,
 			},
 			expDisk: &Disk{
-				VolumeID:         "vol-test",
+				VolumeID:         "vol-000000a8",
 				CapacityGiB:      1,
 				AvailabilityZone: expZone,
 			},
@@ -79,16 +90,16 @@ func TestCreateDisk(t *testing.T) {
 		},
 		{
 			name:       "success: normal with encrypted volume",
-			volumeName: "vol-test-name",
+			volumeName: "vol-000000ae",
 			diskOptions: &DiskOptions{
 				CapacityBytes:    util.GiBToBytes(1),
-				Tags:             map[string]string{VolumeNameTagKey: "vol-test"},
+				Tags:             map[string]string{VolumeNameTagKey: "vol-000000a8"},
 				AvailabilityZone: expZone,
 				Encrypted:        true,
 				KmsKeyID:         "arn:aws:kms:us-east-1:012345678910:key/abcd1234-a123-456a-a12b-a123b4cd56ef",
 			},
 			expDisk: &Disk{
-				VolumeID:         "vol-test",
+				VolumeID:         "vol-000000a8",
 				CapacityGiB:      1,
 				AvailabilityZone: expZone,
 			},
@@ -96,10 +107,10 @@ func TestCreateDisk(t *testing.T) {
 		},
 		{
 			name:       "fail: CreateVolume returned CreateVolume error",
-			volumeName: "vol-test-name-error",
+			volumeName: "vol-000000af",
 			diskOptions: &DiskOptions{
 				CapacityBytes:    util.GiBToBytes(1),
-				Tags:             map[string]string{VolumeNameTagKey: "vol-test"},
+				Tags:             map[string]string{VolumeNameTagKey: "vol-000000a8"},
 				AvailabilityZone: expZone,
 			},
 			expErr:             fmt.Errorf("could not create volume in EC2: CreateVolume generic error"),
@@ -107,11 +118,11 @@ func TestCreateDisk(t *testing.T) {
 		},
 		{
 			name:       "fail: CreateVolume returned a DescribeVolumes error",
-			volumeName: "vol-test-name-error",
+			volumeName: "vol-000000af",
 			volState:   "creating",
 			diskOptions: &DiskOptions{
 				CapacityBytes:    util.GiBToBytes(1),
-				Tags:             map[string]string{VolumeNameTagKey: "vol-test"},
+				Tags:             map[string]string{VolumeNameTagKey: "vol-000000a8"},
 				AvailabilityZone: "",
 			},
 			expErr:             fmt.Errorf("could not create volume in EC2: DescribeVolumes generic error"),
@@ -119,26 +130,26 @@ func TestCreateDisk(t *testing.T) {
 		},
 		{
 			name:       "fail: CreateVolume returned a volume with wrong state",
-			volumeName: "vol-test-name-error",
+			volumeName: "vol-000000af",
 			volState:   "creating",
 			diskOptions: &DiskOptions{
 				CapacityBytes:    util.GiBToBytes(1),
-				Tags:             map[string]string{VolumeNameTagKey: "vol-test"},
+				Tags:             map[string]string{VolumeNameTagKey: "vol-000000a8"},
 				AvailabilityZone: "",
 			},
 			expErr: fmt.Errorf("failed to get an available volume in EC2: timed out waiting for the condition"),
 		},
 		{
 			name:       "success: normal from snapshot",
-			volumeName: "vol-test-name",
+			volumeName: "vol-000000ae",
 			diskOptions: &DiskOptions{
 				CapacityBytes:    util.GiBToBytes(1),
-				Tags:             map[string]string{VolumeNameTagKey: "vol-test"},
+				Tags:             map[string]string{VolumeNameTagKey: "vol-000000a8"},
 				AvailabilityZone: expZone,
 				SnapshotID:       "snapshot-test",
 			},
 			expDisk: &Disk{
-				VolumeID:         "vol-test",
+				VolumeID:         "vol-000000a8",
 				CapacityGiB:      1,
 				AvailabilityZone: expZone,
 			},
@@ -165,7 +176,7 @@ func TestCreateDisk(t *testing.T) {
 			}
 			snapshot := &ec2.Snapshot{
 				SnapshotId: aws.String(tc.diskOptions.SnapshotID),
-				VolumeId:   aws.String("snap-test-volume"),
+				VolumeId:   aws.String("snap-000000af"),
 				State:      aws.String("completed"),
 			}
 			ctx := context.Background()
@@ -211,30 +222,3615 @@ func TestCreateDisk(t *testing.T) {
 	}
 }
 
+func TestCreateDiskProvisionedIOPS(t *testing.T) {
+	testCases := []struct {
+		name         string
+		volumeType   string
+		iopsPerGB    int
+		responseIOPS int64
+		describeIOPS int64
+		expIOPS      int64
+	}{
+		{
+			name:         "io1 provisioned by EC2",
+			volumeType:   VolumeTypeIO1,
+			iopsPerGB:    50,
+			responseIOPS: 1000,
+			describeIOPS: 1000,
+			expIOPS:      1000,
+		},
+		{
+			name:         "gp2 baseline",
+			volumeType:   VolumeTypeGP2,
+			responseIOPS: 300,
+			describeIOPS: 300,
+			expIOPS:      300,
+		},
+		{
+			name:         "missing from the response",
+			volumeType:   VolumeTypeGP2,
+			describeIOPS: 300,
+			expIOPS:      300,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			response := &ec2.Volume{
+				VolumeId:         aws.String("vol-000000a8"),
+				Size:             aws.Int64(100),
+				State:            aws.String("creating"),
+				AvailabilityZone: aws.String(expZone),
+			}
+			if tc.responseIOPS > 0 {
+				response.Iops = aws.Int64(tc.responseIOPS)
+			}
+			described := &ec2.Volume{
+				VolumeId:         aws.String("vol-000000a8"),
+				Size:             aws.Int64(100),
+				State:            aws.String("available"),
+				AvailabilityZone: aws.String(expZone),
+				Iops:             aws.Int64(tc.describeIOPS),
+			}
+
+			ctx := context.Background()
+			mockEC2.EXPECT().CreateVolumeWithContext(gomock.Eq(ctx), gomock.Any()).Return(response, nil)
+			mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{described}}, nil)
+
+			diskOptions := &DiskOptions{
+				CapacityBytes:    util.GiBToBytes(100),
+				Tags:             map[string]string{VolumeNameTagKey: "vol-000000a8"},
+				AvailabilityZone: expZone,
+				VolumeType:       tc.volumeType,
+				IOPSPerGB:        tc.iopsPerGB,
+			}
+			disk, err := c.CreateDisk(ctx, "vol-000000ae", diskOptions)
+			if err != nil {
+				t.Fatalf("CreateDisk() failed: expected no error, got: %v", err)
+			}
+			if disk.IOPS != tc.expIOPS {
+				t.Fatalf("CreateDisk() failed: expected IOPS %d, got %d", tc.expIOPS, disk.IOPS)
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestCreateDiskClientToken(t *testing.T) {
+	volumeName := "pvc-5b7c7c1b-0b7a-4b2e-9a1e-2f8c6d4a3e10"
+	testCases := []struct {
+		name           string
+		clientToken    string
+		createErr      error
+		expClientToken string
+		expErr         error
+	}{
+		{
+			name:           "success: derived from the volume name",
+			expClientToken: volumeClientToken(volumeName),
+		},
+		{
+			name:           "success: explicit",
+			clientToken:    "token",
+			expClientToken: "token",
+		},
+		{
+			name:           "fail: reused with different parameters",
+			createErr:      awserr.New("IdempotentParameterMismatch", "", nil),
+			expClientToken: volumeClientToken(volumeName),
+			expErr:         ErrIdempotentParameterMismatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			ctx := context.Background()
+			mockEC2.EXPECT().CreateVolumeWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+				func(_ context.Context, input *ec2.CreateVolumeInput, _ ...request.Option) (*ec2.Volume, error) {
+					if clientToken := aws.StringValue(input.ClientToken); clientToken != tc.expClientToken {
+						t.Fatalf("CreateDisk() failed: expected client token %q, got %q", tc.expClientToken, clientToken)
+					}
+					if tc.createErr != nil {
+						return nil, tc.createErr
+					}
+					return &ec2.Volume{VolumeId: aws.String("vol-000000a8"), Size: aws.Int64(1), State: aws.String("creating")}, nil
+				})
+			if tc.createErr == nil {
+				mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{
+					Volumes: []*ec2.Volume{{VolumeId: aws.String("vol-000000a8"), Size: aws.Int64(1), State: aws.String("available")}},
+				}, nil)
+			}
+
+			diskOptions := &DiskOptions{
+				CapacityBytes:    util.GiBToBytes(1),
+				Tags:             map[string]string{VolumeNameTagKey: volumeName},
+				AvailabilityZone: expZone,
+				ClientToken:      tc.clientToken,
+			}
+			_, err := c.CreateDisk(ctx, volumeName, diskOptions)
+			if err != tc.expErr {
+				t.Fatalf("CreateDisk() failed: expected error %v, got: %v", tc.expErr, err)
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestVolumeClientToken(t *testing.T) {
+	token := volumeClientToken("pvc-5b7c7c1b-0b7a-4b2e-9a1e-2f8c6d4a3e10")
+	if len(token) > 64 {
+		t.Fatalf("volumeClientToken() failed: expected at most 64 characters, got %d", len(token))
+	}
+	if other := volumeClientToken("pvc-5b7c7c1b-0b7a-4b2e-9a1e-2f8c6d4a3e10"); other != token {
+		t.Fatalf("volumeClientToken() failed: expected the same token %q, got %q", token, other)
+	}
+	if other := volumeClientToken("pvc-other"); other == token {
+		t.Fatalf("volumeClientToken() failed: expected different tokens for different names, got %q", token)
+	}
+}
+
+func TestExtraTags(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockEC2 := mocks.NewMockEC2(mockCtrl)
+	c := newCloud(mockEC2)
+	c.(*cloud).options.extraTags = map[string]string{
+		"cost-center": "storage",
+		"owner":       "platform",
+	}
+
+	expTags := map[string]string{
+		"cost-center":    "storage",
+		"owner":          "team",
+		VolumeNameTagKey: "vol-000000ae",
+	}
+	tagsOf := func(specs []*ec2.TagSpecification) map[string]string {
+		tags := map[string]string{}
+		for _, spec := range specs {
+			for _, tag := range spec.Tags {
+				tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+			}
+		}
+		return tags
+	}
+
+	ctx := context.Background()
+	mockEC2.EXPECT().CreateVolumeWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+		func(_ context.Context, input *ec2.CreateVolumeInput, _ ...request.Option) (*ec2.Volume, error) {
+			if tags := tagsOf(input.TagSpecifications); !reflect.DeepEqual(tags, expTags) {
+				t.Fatalf("CreateDisk() failed: expected tags %v, got %v", expTags, tags)
+			}
+			return &ec2.Volume{VolumeId: aws.String("vol-000000a8"), Size: aws.Int64(1), State: aws.String("creating")}, nil
+		})
+	mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{
+		Volumes: []*ec2.Volume{{VolumeId: aws.String("vol-000000a8"), Size: aws.Int64(1), State: aws.String("available")}},
+	}, nil)
+	mockEC2.EXPECT().CreateSnapshotWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+		func(_ context.Context, input *ec2.CreateSnapshotInput, _ ...request.Option) (*ec2.Snapshot, error) {
+			if tags := tagsOf(input.TagSpecifications); !reflect.DeepEqual(tags, expTags) {
+				t.Fatalf("CreateSnapshot() failed: expected tags %v, got %v", expTags, tags)
+			}
+			return &ec2.Snapshot{SnapshotId: aws.String("snap-000000a9"), VolumeId: aws.String("vol-000000a8"), State: aws.String("pending")}, nil
+		})
+
+	tags := map[string]string{
+		"owner":          "team",
+		VolumeNameTagKey: "vol-000000ae",
+	}
+	diskOptions := &DiskOptions{
+		CapacityBytes:    util.GiBToBytes(1),
+		Tags:             tags,
+		AvailabilityZone: expZone,
+	}
+	if _, err := c.CreateDisk(ctx, "vol-000000ae", diskOptions); err != nil {
+		t.Fatalf("CreateDisk() failed: expected no error, got: %v", err)
+	}
+	if _, err := c.CreateSnapshot(ctx, "vol-000000a8", &SnapshotOptions{Tags: tags}); err != nil {
+		t.Fatalf("CreateSnapshot() failed: expected no error, got: %v", err)
+	}
+
+	// The limits apply to the tags merged with the extra tags
+	c.(*cloud).options.extraTags["aws:owner"] = "platform"
+	if _, err := c.CreateDisk(ctx, "vol-000000ae", diskOptions); err == nil {
+		t.Fatal("CreateDisk() failed: expected error, got nothing")
+	}
+}
+
+func TestCreateDiskFromSnapshotSize(t *testing.T) {
+	testCases := []struct {
+		name            string
+		capacityBytes   int64
+		snapshotSizeGiB int64
+		describeSnapErr error
+		expCreateVolume bool
+		expErr          error
+	}{
+		{
+			name:            "success: same size as snapshot",
+			capacityBytes:   util.GiBToBytes(10),
+			snapshotSizeGiB: 10,
+			expCreateVolume: true,
+		},
+		{
+			name:            "success: larger than snapshot",
+			capacityBytes:   util.GiBToBytes(20),
+			snapshotSizeGiB: 10,
+			expCreateVolume: true,
+		},
+		{
+			name:            "fail: smaller than snapshot",
+			capacityBytes:   util.GiBToBytes(5),
+			snapshotSizeGiB: 10,
+			expErr:          ErrDiskSmallerThanSnapshot,
+		},
+		{
+			name:            "fail: snapshot not found",
+			capacityBytes:   util.GiBToBytes(10),
+			describeSnapErr: awserr.New("InvalidSnapshot.NotFound", "", nil),
+			expErr:          ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			vol := &ec2.Volume{
+				VolumeId:         aws.String("vol-000000a8"),
+				Size:             aws.Int64(util.BytesToGiB(tc.capacityBytes)),
+				State:            aws.String("available"),
+				AvailabilityZone: aws.String(expZone),
+			}
+			snapshot := &ec2.Snapshot{
+				SnapshotId: aws.String("snap-000000a9"),
+				VolumeSize: aws.Int64(tc.snapshotSizeGiB),
+				State:      aws.String("completed"),
+			}
+
+			ctx := context.Background()
+			mockEC2.EXPECT().DescribeSnapshotsWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeSnapshotsOutput{Snapshots: []*ec2.Snapshot{snapshot}}, tc.describeSnapErr)
+			if tc.expCreateVolume {
+				mockEC2.EXPECT().CreateVolumeWithContext(gomock.Eq(ctx), gomock.Any()).Return(vol, nil)
+				mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{vol}}, nil).AnyTimes()
+			}
+
+			diskOptions := &DiskOptions{
+				CapacityBytes:    tc.capacityBytes,
+				Tags:             map[string]string{VolumeNameTagKey: "vol-000000a8"},
+				AvailabilityZone: expZone,
+				SnapshotID:       "snap-000000a9",
+			}
+			disk, err := c.CreateDisk(ctx, "vol-000000ae", diskOptions)
+			if err != tc.expErr {
+				t.Fatalf("CreateDisk() failed: expected error %v, got: %v", tc.expErr, err)
+			}
+			if err == nil && disk.CapacityGiB != util.BytesToGiB(tc.capacityBytes) {
+				t.Fatalf("CreateDisk() failed: expected capacity %d, got %d", util.BytesToGiB(tc.capacityBytes), disk.CapacityGiB)
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestCreateDiskFromSnapshotGrown(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockEC2 := mocks.NewMockEC2(mockCtrl)
+	c := newCloud(mockEC2)
+
+	snapshot := &ec2.Snapshot{
+		SnapshotId: aws.String("snap-000000a9"),
+		VolumeSize: aws.Int64(10),
+		State:      aws.String("completed"),
+	}
+	// The CreateVolume response reports the size of the snapshot
+	createdVol := &ec2.Volume{
+		VolumeId:         aws.String("vol-000000a8"),
+		Size:             aws.Int64(10),
+		State:            aws.String("creating"),
+		AvailabilityZone: aws.String(expZone),
+	}
+	availableVol := &ec2.Volume{
+		VolumeId:         aws.String("vol-000000a8"),
+		Size:             aws.Int64(20),
+		State:            aws.String("available"),
+		AvailabilityZone: aws.String(expZone),
+	}
+
+	ctx := context.Background()
+	mockEC2.EXPECT().DescribeSnapshotsWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeSnapshotsOutput{Snapshots: []*ec2.Snapshot{snapshot}}, nil)
+	mockEC2.EXPECT().CreateVolumeWithContext(gomock.Eq(ctx), gomock.Any()).Return(createdVol, nil)
+	mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{availableVol}}, nil).AnyTimes()
+
+	diskOptions := &DiskOptions{
+		CapacityBytes:    util.GiBToBytes(20),
+		Tags:             map[string]string{VolumeNameTagKey: "vol-000000a8"},
+		AvailabilityZone: expZone,
+		SnapshotID:       "snap-000000a9",
+	}
+	disk, err := c.CreateDisk(ctx, "vol-000000ae", diskOptions)
+	if err != nil {
+		t.Fatalf("CreateDisk() failed: expected no error, got: %v", err)
+	}
+	if disk.CapacityGiB != 20 {
+		t.Fatalf("CreateDisk() failed: expected capacity %d, got %d", 20, disk.CapacityGiB)
+	}
+}
+
+func TestCreateDiskFromSnapshotVolumeType(t *testing.T) {
+	testCases := []struct {
+		name       string
+		volumeType string
+		iopsPerGB  int
+		expIOPS    int64
+	}{
+		{
+			name:       "io2 with explicit IOPS",
+			volumeType: VolumeTypeIO2,
+			iopsPerGB:  50,
+			expIOPS:    500,
+		},
+		{
+			name:       "st2 without IOPS",
+			volumeType: VolumeTypeST2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			vol := &ec2.Volume{
+				VolumeId:         aws.String("vol-000000a8"),
+				Size:             aws.Int64(10),
+				State:            aws.String("available"),
+				AvailabilityZone: aws.String(expZone),
+				VolumeType:       aws.String(tc.volumeType),
+			}
+			// The snapshot was taken from a gp2 volume
+			snapshot := &ec2.Snapshot{
+				SnapshotId: aws.String("snap-000000a9"),
+				VolumeId:   aws.String("vol-000000a7"),
+				VolumeSize: aws.Int64(10),
+				State:      aws.String("completed"),
+			}
+
+			ctx := context.Background()
+			mockEC2.EXPECT().DescribeSnapshotsWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeSnapshotsOutput{Snapshots: []*ec2.Snapshot{snapshot}}, nil)
+			mockEC2.EXPECT().CreateVolumeWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+				func(_ aws.Context, input *ec2.CreateVolumeInput, _ ...request.Option) (*ec2.Volume, error) {
+					if aws.StringValue(input.SnapshotId) != "snap-000000a9" {
+						t.Fatalf("CreateVolume() called with snapshot %q, expected %q", aws.StringValue(input.SnapshotId), "snap-000000a9")
+					}
+					if aws.StringValue(input.VolumeType) != tc.volumeType {
+						t.Fatalf("CreateVolume() called with volume type %q, expected %q", aws.StringValue(input.VolumeType), tc.volumeType)
+					}
+					if aws.Int64Value(input.Iops) != tc.expIOPS {
+						t.Fatalf("CreateVolume() called with IOPS %d, expected %d", aws.Int64Value(input.Iops), tc.expIOPS)
+					}
+					return vol, nil
+				})
+			mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{vol}}, nil).AnyTimes()
+
+			diskOptions := &DiskOptions{
+				CapacityBytes:    util.GiBToBytes(10),
+				Tags:             map[string]string{VolumeNameTagKey: "vol-000000a8"},
+				AvailabilityZone: expZone,
+				VolumeType:       tc.volumeType,
+				IOPSPerGB:        tc.iopsPerGB,
+				SnapshotID:       "snap-000000a9",
+			}
+			if _, err := c.CreateDisk(ctx, "vol-000000ae", diskOptions); err != nil {
+				t.Fatalf("CreateDisk() failed: expected no error, got: %v", err)
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestCreateDiskSkipSnapshotPreChecks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockEC2 := mocks.NewMockEC2(mockCtrl)
+	c := newCloud(mockEC2).(*cloud)
+	c.options.skipSnapshotPreChecks = true
+
+	vol := &ec2.Volume{
+		VolumeId:         aws.String("vol-000000a8"),
+		Size:             aws.Int64(1),
+		State:            aws.String("available"),
+		AvailabilityZone: aws.String(expZone),
+	}
+
+	ctx := context.Background()
+	mockEC2.EXPECT().DescribeSnapshotsWithContext(gomock.Any(), gomock.Any()).Times(0)
+	mockEC2.EXPECT().CreateVolumeWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+		func(_ aws.Context, input *ec2.CreateVolumeInput, _ ...request.Option) (*ec2.Volume, error) {
+			if aws.StringValue(input.SnapshotId) != "snap-000000a9" {
+				t.Fatalf("CreateVolume() called with snapshot %q, expected %q", aws.StringValue(input.SnapshotId), "snap-000000a9")
+			}
+			return vol, nil
+		})
+	mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{vol}}, nil).AnyTimes()
+
+	diskOptions := &DiskOptions{
+		CapacityBytes:    util.GiBToBytes(1),
+		Tags:             map[string]string{VolumeNameTagKey: "vol-000000a8"},
+		AvailabilityZone: expZone,
+		SnapshotID:       "snap-000000a9",
+	}
+	if _, err := c.CreateDisk(ctx, "vol-000000ae", diskOptions); err != nil {
+		t.Fatalf("CreateDisk() failed: expected no error, got: %v", err)
+	}
+
+	mockCtrl.Finish()
+}
+
+func TestCreateDiskRequireExplicitAZ(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockEC2 := mocks.NewMockEC2(mockCtrl)
+	c := newCloud(mockEC2).(*cloud)
+	c.options.requireExplicitAZ = true
+
+	ctx := context.Background()
+	mockEC2.EXPECT().DescribeAvailabilityZonesWithContext(gomock.Any(), gomock.Any()).Times(0)
+	mockEC2.EXPECT().CreateVolumeWithContext(gomock.Any(), gomock.Any()).Times(0)
+
+	diskOptions := &DiskOptions{
+		CapacityBytes: util.GiBToBytes(1),
+		Tags:          map[string]string{VolumeNameTagKey: "vol-000000a8"},
+	}
+	if _, err := c.CreateDisk(ctx, "vol-000000ae", diskOptions); err == nil {
+		t.Fatal("CreateDisk() failed: expected error, got nothing")
+	}
+}
+
+func TestCreateDiskVolumeNotFoundYet(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockEC2 := mocks.NewMockEC2(mockCtrl)
+	c := newCloud(mockEC2)
+
+	vol := &ec2.Volume{
+		VolumeId:         aws.String("vol-000000a8"),
+		Size:             aws.Int64(1),
+		State:            aws.String("available"),
+		AvailabilityZone: aws.String(expZone),
+	}
+
+	// EC2 doesn't know about the volume right after its creation
+	ctx := context.Background()
+	gomock.InOrder(
+		mockEC2.EXPECT().CreateVolumeWithContext(gomock.Eq(ctx), gomock.Any()).Return(vol, nil),
+		mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(nil, awserr.New("InvalidVolume.NotFound", "", nil)),
+		mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{vol}}, nil),
+	)
+
+	diskOptions := &DiskOptions{
+		CapacityBytes:    util.GiB,
+		Tags:             map[string]string{VolumeNameTagKey: "vol-000000a8"},
+		AvailabilityZone: expZone,
+	}
+	disk, err := c.CreateDisk(ctx, "vol-000000ae", diskOptions)
+	if err != nil {
+		t.Fatalf("CreateDisk() failed: expected no error, got: %v", err)
+	}
+	if disk.VolumeID != "vol-000000a8" {
+		t.Fatalf("CreateDisk() failed: expected volume %q, got %q", "vol-000000a8", disk.VolumeID)
+	}
+
+	mockCtrl.Finish()
+}
+
+func TestCreateDiskAutoVolumeType(t *testing.T) {
+	testCases := []struct {
+		name          string
+		threshold     int64
+		capacityBytes int64
+		expType       string
+	}{
+		{
+			name:          "small volume",
+			capacityBytes: util.GiBToBytes(100),
+			expType:       VolumeTypeGP2,
+		},
+		{
+			name:          "large volume",
+			capacityBytes: util.GiBToBytes(2048),
+			expType:       VolumeTypeST2,
+		},
+		{
+			name:          "volume at the configured threshold",
+			threshold:     util.GiBToBytes(500),
+			capacityBytes: util.GiBToBytes(500),
+			expType:       VolumeTypeST2,
+		},
+		{
+			name:          "volume below the configured threshold",
+			threshold:     util.GiBToBytes(500),
+			capacityBytes: util.GiBToBytes(499),
+			expType:       VolumeTypeGP2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2).(*cloud)
+			c.options.autoVolumeTypeThreshold = tc.threshold
+
+			vol := &ec2.Volume{
+				VolumeId:         aws.String("vol-000000a8"),
+				Size:             aws.Int64(util.BytesToGiB(tc.capacityBytes)),
+				State:            aws.String("available"),
+				AvailabilityZone: aws.String(expZone),
+			}
+
+			ctx := context.Background()
+			mockEC2.EXPECT().CreateVolumeWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+				func(_ aws.Context, input *ec2.CreateVolumeInput, _ ...request.Option) (*ec2.Volume, error) {
+					if aws.StringValue(input.VolumeType) != tc.expType {
+						t.Fatalf("CreateVolume() called with volume type %q, expected %q", aws.StringValue(input.VolumeType), tc.expType)
+					}
+					return vol, nil
+				})
+			mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{vol}}, nil).AnyTimes()
+
+			diskOptions := &DiskOptions{
+				CapacityBytes:    tc.capacityBytes,
+				Tags:             map[string]string{VolumeNameTagKey: "vol-000000a8"},
+				AvailabilityZone: expZone,
+				VolumeType:       VolumeTypeAuto,
+			}
+			if _, err := c.CreateDisk(ctx, "vol-000000ae", diskOptions); err != nil {
+				t.Fatalf("CreateDisk() failed: expected no error, got: %v", err)
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestCreateDiskOutpost(t *testing.T) {
+	outpostArn := "arn:aws:outposts:us-west-2:012345678910:outpost/op-0123456789abcdef0"
+	testCases := []struct {
+		name             string
+		availabilityZone string
+		expErr           bool
+	}{
+		{
+			name:             "success: outpost with provided zone",
+			availabilityZone: expZone,
+		},
+		{
+			name:   "fail: outpost without zone",
+			expErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			vol := &ec2.Volume{
+				VolumeId:         aws.String("vol-000000a8"),
+				Size:             aws.Int64(1),
+				State:            aws.String("available"),
+				AvailabilityZone: aws.String(tc.availabilityZone),
+				OutpostArn:       aws.String(outpostArn),
+			}
+
+			ctx := context.Background()
+			if !tc.expErr {
+				mockEC2.EXPECT().CreateVolumeWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+					func(_ aws.Context, input *ec2.CreateVolumeInput, _ ...request.Option) (*ec2.Volume, error) {
+						if aws.StringValue(input.OutpostArn) != outpostArn {
+							t.Fatalf("CreateVolume() called with outpost ARN %q, expected %q", aws.StringValue(input.OutpostArn), outpostArn)
+						}
+						return vol, nil
+					})
+				mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{vol}}, nil).AnyTimes()
+			}
+
+			diskOptions := &DiskOptions{
+				CapacityBytes:    util.GiBToBytes(1),
+				Tags:             map[string]string{VolumeNameTagKey: "vol-000000a8"},
+				AvailabilityZone: tc.availabilityZone,
+				OutpostArn:       outpostArn,
+			}
+			_, err := c.CreateDisk(ctx, "vol-000000ae", diskOptions)
+			if tc.expErr && err == nil {
+				t.Fatal("CreateDisk() failed: expected error, got nothing")
+			}
+			if !tc.expErr && err != nil {
+				t.Fatalf("CreateDisk() failed: expected no error, got: %v", err)
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestCreateDiskIOPS(t *testing.T) {
+	testCases := []struct {
+		name         string
+		volumeType   string
+		capacityGiB  int64
+		iopsPerGB    int
+		blockExpress bool
+		expIOPS      int64
+		expErr       bool
+	}{
+		{
+			name:        "io1: below the minimum",
+			volumeType:  VolumeTypeIO1,
+			capacityGiB: 4,
+			iopsPerGB:   10,
+			expIOPS:     MinTotalIOPS,
+		},
+		{
+			name:        "io1: within the limits",
+			volumeType:  VolumeTypeIO1,
+			capacityGiB: 100,
+			iopsPerGB:   50,
+			expIOPS:     5000,
+		},
+		{
+			name:        "io2: clamped to the maximum",
+			volumeType:  VolumeTypeIO2,
+			capacityGiB: 1000,
+			iopsPerGB:   100,
+			expIOPS:     MaxTotalIOPS,
+		},
+		{
+			name:         "io1: block express ignored",
+			volumeType:   VolumeTypeIO1,
+			capacityGiB:  1000,
+			iopsPerGB:    50,
+			blockExpress: true,
+			expIOPS:      MaxTotalIOPS,
+		},
+		{
+			name:         "io2 block express: above the io2 maximum",
+			volumeType:   VolumeTypeIO2,
+			capacityGiB:  1000,
+			iopsPerGB:    100,
+			blockExpress: true,
+			expIOPS:      100000,
+		},
+		{
+			name:         "io2 block express: clamped to the maximum",
+			volumeType:   VolumeTypeIO2,
+			capacityGiB:  4000,
+			iopsPerGB:    100,
+			blockExpress: true,
+			expIOPS:      MaxIO2BlockExpressIOPS,
+		},
+		{
+			name:        "fail: io1 above the IOPS per GiB limit",
+			volumeType:  VolumeTypeIO1,
+			capacityGiB: 100,
+			iopsPerGB:   MaxIO1IOPSPerGB + 1,
+			expErr:      true,
+		},
+		{
+			name:        "fail: io2 above the IOPS per GiB limit",
+			volumeType:  VolumeTypeIO2,
+			capacityGiB: 100,
+			iopsPerGB:   MaxIO2IOPSPerGB + 1,
+			expErr:      true,
+		},
+		{
+			name:         "io2 block express: above the io2 IOPS per GiB limit",
+			volumeType:   VolumeTypeIO2,
+			capacityGiB:  100,
+			iopsPerGB:    MaxIO2IOPSPerGB + 1,
+			blockExpress: true,
+			expIOPS:      100 * (MaxIO2IOPSPerGB + 1),
+		},
+		{
+			name:         "fail: io2 block express above the IOPS per GiB limit",
+			volumeType:   VolumeTypeIO2,
+			capacityGiB:  100,
+			iopsPerGB:    MaxIO2BlockExpressIOPSPerGB + 1,
+			blockExpress: true,
+			expErr:       true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			vol := &ec2.Volume{
+				VolumeId:         aws.String("vol-000000a8"),
+				Size:             aws.Int64(tc.capacityGiB),
+				State:            aws.String("available"),
+				AvailabilityZone: aws.String(expZone),
+			}
+
+			ctx := context.Background()
+			if !tc.expErr {
+				mockEC2.EXPECT().CreateVolumeWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+					func(_ aws.Context, input *ec2.CreateVolumeInput, _ ...request.Option) (*ec2.Volume, error) {
+						if iops := aws.Int64Value(input.Iops); iops != tc.expIOPS {
+							t.Fatalf("CreateVolume() called with IOPS %d, expected %d", iops, tc.expIOPS)
+						}
+						return vol, nil
+					})
+				mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{vol}}, nil).AnyTimes()
+			}
+
+			diskOptions := &DiskOptions{
+				CapacityBytes:    util.GiBToBytes(tc.capacityGiB),
+				Tags:             map[string]string{VolumeNameTagKey: "vol-000000a8"},
+				VolumeType:       tc.volumeType,
+				IOPSPerGB:        tc.iopsPerGB,
+				AvailabilityZone: expZone,
+				BlockExpress:     tc.blockExpress,
+			}
+			_, err := c.CreateDisk(ctx, "vol-000000ae", diskOptions)
+			if tc.expErr && err == nil {
+				t.Fatal("CreateDisk() failed: expected error, got nothing")
+			}
+			if !tc.expErr && err != nil {
+				t.Fatalf("CreateDisk() failed: expected no error, got: %v", err)
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestWillBeEncrypted(t *testing.T) {
+	defaultKeyID := "arn:aws:kms:us-east-1:012345678910:key/default"
+	requestKeyID := "arn:aws:kms:us-east-1:012345678910:key/request"
+
+	testCases := []struct {
+		name                string
+		diskOptions         *DiskOptions
+		encryptionByDefault bool
+		expEncryptionCheck  bool
+		expDefaultKeyCheck  bool
+		expEncrypted        bool
+		expKmsKeyID         string
+	}{
+		{
+			name:         "request encrypted with key",
+			diskOptions:  &DiskOptions{Encrypted: true, KmsKeyID: requestKeyID},
+			expEncrypted: true,
+			expKmsKeyID:  requestKeyID,
+		},
+		{
+			name:               "request encrypted with default key",
+			diskOptions:        &DiskOptions{Encrypted: true},
+			expDefaultKeyCheck: true,
+			expEncrypted:       true,
+			expKmsKeyID:        defaultKeyID,
+		},
+		{
+			name:                "account default encrypted",
+			diskOptions:         &DiskOptions{},
+			encryptionByDefault: true,
+			expEncryptionCheck:  true,
+			expDefaultKeyCheck:  true,
+			expEncrypted:        true,
+			expKmsKeyID:         defaultKeyID,
+		},
+		{
+			name:               "plaintext",
+			diskOptions:        &DiskOptions{},
+			expEncryptionCheck: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			ctx := context.Background()
+			if tc.expEncryptionCheck {
+				mockEC2.EXPECT().GetEbsEncryptionByDefaultWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.GetEbsEncryptionByDefaultOutput{EbsEncryptionByDefault: aws.Bool(tc.encryptionByDefault)}, nil)
+			}
+			if tc.expDefaultKeyCheck {
+				mockEC2.EXPECT().GetEbsDefaultKmsKeyIdWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.GetEbsDefaultKmsKeyIdOutput{KmsKeyId: aws.String(defaultKeyID)}, nil)
+			}
+
+			encrypted, kmsKeyID, err := c.WillBeEncrypted(ctx, tc.diskOptions)
+			if err != nil {
+				t.Fatalf("WillBeEncrypted() failed: expected no error, got: %v", err)
+			}
+			if encrypted != tc.expEncrypted {
+				t.Fatalf("WillBeEncrypted() failed: expected encrypted %t, got %t", tc.expEncrypted, encrypted)
+			}
+			if kmsKeyID != tc.expKmsKeyID {
+				t.Fatalf("WillBeEncrypted() failed: expected KMS key ID %q, got %q", tc.expKmsKeyID, kmsKeyID)
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestCreateDiskMultiAttach(t *testing.T) {
+	testCases := []struct {
+		name       string
+		volumeType string
+		expErr     bool
+	}{
+		{
+			name:       "success: io1",
+			volumeType: VolumeTypeIO1,
+		},
+		{
+			name:       "success: io2",
+			volumeType: VolumeTypeIO2,
+		},
+		{
+			name:       "fail: gp2",
+			volumeType: VolumeTypeGP2,
+			expErr:     true,
+		},
+		{
+			name:   "fail: default type",
+			expErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			vol := &ec2.Volume{
+				VolumeId:           aws.String("vol-000000a8"),
+				Size:               aws.Int64(4),
+				State:              aws.String("available"),
+				AvailabilityZone:   aws.String(expZone),
+				MultiAttachEnabled: aws.Bool(true),
+			}
+
+			ctx := context.Background()
+			if !tc.expErr {
+				mockEC2.EXPECT().CreateVolumeWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+					func(_ aws.Context, input *ec2.CreateVolumeInput, _ ...request.Option) (*ec2.Volume, error) {
+						if !aws.BoolValue(input.MultiAttachEnabled) {
+							t.Fatal("CreateVolume() called without multi-attach enabled")
+						}
+						return vol, nil
+					})
+				mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{vol}}, nil).AnyTimes()
+			}
+
+			diskOptions := &DiskOptions{
+				CapacityBytes:      util.GiBToBytes(4),
+				Tags:               map[string]string{VolumeNameTagKey: "vol-000000a8"},
+				VolumeType:         tc.volumeType,
+				IOPSPerGB:          25,
+				AvailabilityZone:   expZone,
+				MultiAttachEnabled: true,
+			}
+			_, err := c.CreateDisk(ctx, "vol-000000ae", diskOptions)
+			if tc.expErr && err == nil {
+				t.Fatal("CreateDisk() failed: expected error, got nothing")
+			}
+			if !tc.expErr && err != nil {
+				t.Fatalf("CreateDisk() failed: expected no error, got: %v", err)
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestModifyDiskTags(t *testing.T) {
+	volumeID := "vol-000000aa"
+
+	testCases := []struct {
+		name           string
+		addOrUpdate    map[string]string
+		remove         []string
+		expCreateTags  []*ec2.Tag
+		expDeleteTags  []*ec2.Tag
+		createTagsErr  error
+		expErr         bool
+		expErrNotFound bool
+	}{
+		{
+			name:          "success: add and remove",
+			addOrUpdate:   map[string]string{"team": "storage", "env": "prod"},
+			remove:        []string{"owner"},
+			expCreateTags: []*ec2.Tag{{Key: aws.String("env"), Value: aws.String("prod")}, {Key: aws.String("team"), Value: aws.String("storage")}},
+			expDeleteTags: []*ec2.Tag{{Key: aws.String("owner")}},
+		},
+		{
+			name:          "success: add only",
+			addOrUpdate:   map[string]string{"team": "storage"},
+			expCreateTags: []*ec2.Tag{{Key: aws.String("team"), Value: aws.String("storage")}},
+		},
+		{
+			name:          "success: remove only",
+			remove:        []string{"owner"},
+			expDeleteTags: []*ec2.Tag{{Key: aws.String("owner")}},
+		},
+		{
+			name:        "fail: oversized tag value",
+			addOrUpdate: map[string]string{"team": strings.Repeat("a", MaxTagValueLength+1)},
+			expErr:      true,
+		},
+		{
+			name:        "fail: reserved prefix added",
+			addOrUpdate: map[string]string{"aws:team": "storage"},
+			expErr:      true,
+		},
+		{
+			name:   "fail: reserved prefix removed",
+			remove: []string{"aws:team"},
+			expErr: true,
+		},
+		{
+			name:           "fail: volume not found",
+			addOrUpdate:    map[string]string{"team": "storage"},
+			expCreateTags:  []*ec2.Tag{{Key: aws.String("team"), Value: aws.String("storage")}},
+			createTagsErr:  awserr.New("InvalidVolume.NotFound", "", nil),
+			expErr:         true,
+			expErrNotFound: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			ctx := context.Background()
+			if tc.expCreateTags != nil {
+				expRequest := &ec2.CreateTagsInput{Resources: []*string{aws.String(volumeID)}, Tags: tc.expCreateTags}
+				mockEC2.EXPECT().CreateTagsWithContext(gomock.Eq(ctx), gomock.Eq(expRequest)).Return(&ec2.CreateTagsOutput{}, tc.createTagsErr)
+			}
+			if tc.expDeleteTags != nil {
+				expRequest := &ec2.DeleteTagsInput{Resources: []*string{aws.String(volumeID)}, Tags: tc.expDeleteTags}
+				mockEC2.EXPECT().DeleteTagsWithContext(gomock.Eq(ctx), gomock.Eq(expRequest)).Return(&ec2.DeleteTagsOutput{}, nil)
+			}
+
+			err := c.ModifyDiskTags(ctx, volumeID, tc.addOrUpdate, tc.remove)
+			if tc.expErr && err == nil {
+				t.Fatal("ModifyDiskTags() failed: expected error, got nothing")
+			}
+			if !tc.expErr && err != nil {
+				t.Fatalf("ModifyDiskTags() failed: expected no error, got: %v", err)
+			}
+			if tc.expErrNotFound && err != ErrNotFound {
+				t.Fatalf("ModifyDiskTags() failed: expected error %v, got: %v", ErrNotFound, err)
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestBatchReconcileVolumeTags(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockEC2 := mocks.NewMockEC2(mockCtrl)
+	c := newCloud(mockEC2)
+
+	volumeTags := map[string]map[string]string{
+		"vol-000000a9": {"team": "storage"},
+		"vol-000000ab": {"team": "storage"},
+		"vol-000000ac": {"team": "storage", "env": "prod"},
+		"vol-000000ad": {"team": "storage"},
+	}
+	volumes := []*ec2.Volume{
+		{
+			VolumeId: aws.String("vol-000000a9"),
+			Tags:     []*ec2.Tag{{Key: aws.String("team"), Value: aws.String("compute")}, {Key: aws.String("owner"), Value: aws.String("alice")}},
+		},
+		{
+			VolumeId: aws.String("vol-000000ab"),
+			Tags:     []*ec2.Tag{{Key: aws.String("owner"), Value: aws.String("bob")}},
+		},
+		{
+			VolumeId: aws.String("vol-000000ac"),
+			Tags:     []*ec2.Tag{{Key: aws.String("team"), Value: aws.String("storage")}, {Key: aws.String("aws:cloudformation:stack-name"), Value: aws.String("stack")}},
+		},
+	}
+
+	expDescribeRequest := &ec2.DescribeVolumesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("volume-id"),
+				Values: aws.StringSlice([]string{"vol-000000a9", "vol-000000ab", "vol-000000ac", "vol-000000ad"}),
+			},
+		},
+	}
+	mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Any(), gomock.Eq(expDescribeRequest)).Return(&ec2.DescribeVolumesOutput{Volumes: volumes}, nil)
+
+	// vol-000000a9 and vol-000000ab need the same changes and share the calls
+	mockEC2.EXPECT().CreateTagsWithContext(gomock.Any(), gomock.Eq(&ec2.CreateTagsInput{
+		Resources: aws.StringSlice([]string{"vol-000000a9", "vol-000000ab"}),
+		Tags:      []*ec2.Tag{{Key: aws.String("team"), Value: aws.String("storage")}},
+	})).Return(&ec2.CreateTagsOutput{}, nil)
+	mockEC2.EXPECT().DeleteTagsWithContext(gomock.Any(), gomock.Eq(&ec2.DeleteTagsInput{
+		Resources: aws.StringSlice([]string{"vol-000000a9", "vol-000000ab"}),
+		Tags:      []*ec2.Tag{{Key: aws.String("owner")}},
+	})).Return(&ec2.DeleteTagsOutput{}, nil)
+	// vol-000000ac only misses a tag and keeps its reserved tag
+	mockEC2.EXPECT().CreateTagsWithContext(gomock.Any(), gomock.Eq(&ec2.CreateTagsInput{
+		Resources: aws.StringSlice([]string{"vol-000000ac"}),
+		Tags:      []*ec2.Tag{{Key: aws.String("env"), Value: aws.String("prod")}},
+	})).Return(&ec2.CreateTagsOutput{}, awserr.New("RequestLimitExceeded", "", nil))
+
+	errs, err := c.BatchReconcileVolumeTags(context.Background(), volumeTags)
+	if err == nil {
+		t.Fatal("BatchReconcileVolumeTags() failed: expected error, got nothing")
+	}
+	if len(errs) != len(volumeTags) {
+		t.Fatalf("BatchReconcileVolumeTags() failed: expected %d results, got: %d", len(volumeTags), len(errs))
+	}
+	for _, volumeID := range []string{"vol-000000a9", "vol-000000ab"} {
+		if errs[volumeID] != nil {
+			t.Fatalf("BatchReconcileVolumeTags() failed: expected no error for %s, got: %v", volumeID, errs[volumeID])
+		}
+	}
+	if errs["vol-000000ac"] == nil {
+		t.Fatal("BatchReconcileVolumeTags() failed: expected error for vol-000000ac, got nothing")
+	}
+	if errs["vol-000000ad"] != ErrNotFound {
+		t.Fatalf("BatchReconcileVolumeTags() failed: expected error %v for vol-000000ad, got: %v", ErrNotFound, errs["vol-000000ad"])
+	}
+
+	mockCtrl.Finish()
+}
+
+func TestBatchReconcileVolumeTagsOwnedTags(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockEC2 := mocks.NewMockEC2(mockCtrl)
+	c := newCloud(mockEC2)
+	c.(*cloud).options.extraTags = map[string]string{"cost-center": "storage"}
+
+	volumeTags := map[string]map[string]string{
+		"vol-000000a9": {"team": "storage"},
+	}
+	volumes := []*ec2.Volume{
+		{
+			VolumeId: aws.String("vol-000000a9"),
+			Tags: []*ec2.Tag{
+				{Key: aws.String("team"), Value: aws.String("storage")},
+				{Key: aws.String("owner"), Value: aws.String("alice")},
+				{Key: aws.String(VolumeNameTagKey), Value: aws.String("pvc-1234")},
+				{Key: aws.String("kubernetes.io/created-for/pvc/name"), Value: aws.String("data")},
+				{Key: aws.String("cost-center"), Value: aws.String("storage")},
+			},
+		},
+	}
+
+	mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Any(), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: volumes}, nil)
+	// Only the user tag missing from the desired tags is deleted
+	mockEC2.EXPECT().DeleteTagsWithContext(gomock.Any(), gomock.Eq(&ec2.DeleteTagsInput{
+		Resources: aws.StringSlice([]string{"vol-000000a9"}),
+		Tags:      []*ec2.Tag{{Key: aws.String("owner")}},
+	})).Return(&ec2.DeleteTagsOutput{}, nil)
+
+	errs, err := c.BatchReconcileVolumeTags(context.Background(), volumeTags)
+	if err != nil {
+		t.Fatalf("BatchReconcileVolumeTags() failed: expected no error, got: %v", err)
+	}
+	if errs["vol-000000a9"] != nil {
+		t.Fatalf("BatchReconcileVolumeTags() failed: expected no error for vol-000000a9, got: %v", errs["vol-000000a9"])
+	}
+
+	mockCtrl.Finish()
+}
+
+func TestCreateDiskVolumeTypeAliases(t *testing.T) {
+	testCases := []struct {
+		name          string
+		aliases       map[string]string
+		volumeType    string
+		expCreateType string
+		expErr        bool
+	}{
+		{
+			name:          "success: built-in alias",
+			volumeType:    "hdd",
+			expCreateType: VolumeTypeST2,
+		},
+		{
+			name:          "success: custom alias",
+			aliases:       map[string]string{"fast": VolumeTypeIO2},
+			volumeType:    "fast",
+			expCreateType: VolumeTypeIO2,
+		},
+		{
+			name:          "success: built-in alias overridden",
+			aliases:       map[string]string{"ssd": VolumeTypeIO1},
+			volumeType:    "ssd",
+			expCreateType: VolumeTypeIO1,
+		},
+		{
+			name:       "fail: unknown alias",
+			volumeType: "nvme",
+			expErr:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2).(*cloud)
+			c.options.volumeTypeAliases = tc.aliases
+
+			vol := &ec2.Volume{
+				VolumeId:         aws.String("vol-000000a8"),
+				Size:             aws.Int64(10),
+				State:            aws.String("available"),
+				AvailabilityZone: aws.String(expZone),
+			}
+
+			ctx := context.Background()
+			if !tc.expErr {
+				mockEC2.EXPECT().CreateVolumeWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+					func(_ aws.Context, input *ec2.CreateVolumeInput, _ ...request.Option) (*ec2.Volume, error) {
+						if volumeType := aws.StringValue(input.VolumeType); volumeType != tc.expCreateType {
+							t.Fatalf("CreateVolume() called with volume type %q, expected %q", volumeType, tc.expCreateType)
+						}
+						return vol, nil
+					})
+				mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{vol}}, nil).AnyTimes()
+			}
+
+			diskOptions := &DiskOptions{
+				CapacityBytes:    util.GiBToBytes(10),
+				Tags:             map[string]string{VolumeNameTagKey: "vol-000000a8"},
+				VolumeType:       tc.volumeType,
+				IOPSPerGB:        10,
+				AvailabilityZone: expZone,
+			}
+			_, err := c.CreateDisk(ctx, "vol-000000ae", diskOptions)
+			if tc.expErr && err == nil {
+				t.Fatal("CreateDisk() failed: expected error, got nothing")
+			}
+			if !tc.expErr && err != nil {
+				t.Fatalf("CreateDisk() failed: expected no error, got: %v", err)
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestValidateVolumeID(t *testing.T) {
+	testCases := []struct {
+		volumeID string
+		expErr   bool
+	}{
+		{volumeID: "vol-0123456789abcdef0"},
+		{volumeID: "vol-12345678"},
+		{volumeID: "vol-4C58F2B6"},
+		{volumeID: "", expErr: true},
+		{volumeID: "vol-", expErr: true},
+		{volumeID: "0123456789abcdef0", expErr: true},
+		{volumeID: "snap-0123456789abcdef0", expErr: true},
+		{volumeID: "vol-test", expErr: true},
+		{volumeID: "vol-12345678 ", expErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.volumeID, func(t *testing.T) {
+			err := validateVolumeID(tc.volumeID)
+			if tc.expErr && err == nil {
+				t.Fatal("validateVolumeID() failed: expected error, got nothing")
+			}
+			if !tc.expErr && err != nil {
+				t.Fatalf("validateVolumeID() failed: expected no error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestMalformedVolumeID(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	// No EC2 call is expected
+	c := newCloud(mocks.NewMockEC2(mockCtrl))
+
+	ctx := context.Background()
+	volumeID := "test-volume"
+	if _, err := c.DeleteDisk(ctx, volumeID); err == nil {
+		t.Fatal("DeleteDisk() failed: expected error, got nothing")
+	}
+	if _, err := c.AttachDisk(ctx, volumeID, "node-test"); err == nil {
+		t.Fatal("AttachDisk() failed: expected error, got nothing")
+	}
+	if err := c.DetachDisk(ctx, volumeID, "node-test"); err == nil {
+		t.Fatal("DetachDisk() failed: expected error, got nothing")
+	}
+	if _, err := c.ResizeDisk(ctx, volumeID, util.GiBToBytes(2)); err == nil {
+		t.Fatal("ResizeDisk() failed: expected error, got nothing")
+	}
+	if _, err := c.GetDiskByID(ctx, volumeID); err == nil {
+		t.Fatal("GetDiskByID() failed: expected error, got nothing")
+	}
+}
+
 func TestDeleteDisk(t *testing.T) {
 	testCases := []struct {
-		name     string
-		volumeID string
-		expResp  bool
-		expErr   error
+		name      string
+		volumeID  string
+		deleteErr error
+		expResp   bool
+		expErr    error
+	}{
+		{
+			name:     "success: normal",
+			volumeID: "vol-000000aa",
+			expResp:  true,
+			expErr:   nil,
+		},
+		{
+			name:      "success: DeleteVolume returned not found error",
+			volumeID:  "vol-000000aa",
+			deleteErr: awserr.New("InvalidVolume.NotFound", "", nil),
+			expResp:   true,
+			expErr:    nil,
+		},
+		{
+			name:      "fail: DeleteVolume returned generic error",
+			volumeID:  "vol-000000aa",
+			deleteErr: fmt.Errorf("DeleteVolume generic error"),
+			expResp:   false,
+			expErr:    fmt.Errorf("DeleteVolume generic error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			ctx := context.Background()
+			mockEC2.EXPECT().DeleteVolumeWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DeleteVolumeOutput{}, tc.deleteErr)
+
+			ok, err := c.DeleteDisk(ctx, tc.volumeID)
+			if err != nil && tc.expErr == nil {
+				t.Fatalf("DeleteDisk() failed: expected no error, got: %v", err)
+			}
+
+			if err == nil && tc.expErr != nil {
+				t.Fatal("DeleteDisk() failed: expected error, got nothing")
+			}
+
+			if tc.expResp != ok {
+				t.Fatalf("DeleteDisk() failed: expected return %v, got %v", tc.expResp, ok)
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestAttachDisk(t *testing.T) {
+	testCases := []struct {
+		name     string
+		volumeID string
+		nodeID   string
+		expErr   error
+	}{
+		{
+			name:     "success: normal",
+			volumeID: "vol-000000aa",
+			nodeID:   "node-1234",
+			expErr:   nil,
+		},
+		{
+			name:     "fail: AttachVolume returned generic error",
+			volumeID: "vol-000000aa",
+			nodeID:   "node-1234",
+			expErr:   fmt.Errorf(""),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			detachedVol := &ec2.Volume{
+				VolumeId: aws.String(tc.volumeID),
+			}
+			attachedVol := &ec2.Volume{
+				VolumeId: aws.String(tc.volumeID),
+				Attachments: []*ec2.VolumeAttachment{
+					{
+						InstanceId: aws.String(tc.nodeID),
+						Device:     aws.String("/dev/disk/by-id/virtio-" + tc.volumeID),
+						State:      aws.String("attached"),
+					},
+				},
+			}
+
+			ctx := context.Background()
+			mockEC2.EXPECT().DescribeInstancesWithContext(gomock.Eq(ctx), gomock.Any()).Return(newDescribeInstancesOutput(tc.nodeID), nil)
+			describeDetached := mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{detachedVol}}, nil)
+			attach := mockEC2.EXPECT().AttachVolumeWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).Return(&ec2.VolumeAttachment{}, tc.expErr).After(describeDetached)
+			if tc.expErr == nil {
+				mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{attachedVol}}, nil).After(attach).AnyTimes()
+			}
+
+			devicePath, err := c.AttachDisk(ctx, tc.volumeID, tc.nodeID)
+			if err != nil {
+				if tc.expErr == nil {
+					t.Fatalf("AttachDisk() failed: expected no error, got: %v", err)
+				}
+			} else {
+				if tc.expErr != nil {
+					t.Fatal("AttachDisk() failed: expected error, got nothing")
+				}
+				if !strings.HasPrefix(devicePath, "/dev/") {
+					t.Fatal("AttachDisk() failed: expected valid device path, got empty string")
+				}
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestAttachDiskAttachedResponse(t *testing.T) {
+	volumeID := "vol-000000aa"
+	nodeID := "node-1234"
+	devicePath := "/dev/disk/by-id/virtio-" + volumeID
+
+	mockCtrl := gomock.NewController(t)
+	mockEC2 := mocks.NewMockEC2(mockCtrl)
+	c := newCloud(mockEC2)
+
+	detachedVol := &ec2.Volume{
+		VolumeId: aws.String(volumeID),
+	}
+	attachedVol := &ec2.Volume{
+		VolumeId: aws.String(volumeID),
+		Attachments: []*ec2.VolumeAttachment{
+			{InstanceId: aws.String(nodeID), Device: aws.String(devicePath), State: aws.String("attached")},
+		},
+	}
+	attachment := &ec2.VolumeAttachment{
+		VolumeId:   aws.String(volumeID),
+		InstanceId: aws.String(nodeID),
+		State:      aws.String("attached"),
+	}
+
+	// The attachment isn't polled, only described once to be verified
+	ctx := context.Background()
+	mockEC2.EXPECT().DescribeInstancesWithContext(gomock.Eq(ctx), gomock.Any()).Return(newDescribeInstancesOutput(nodeID), nil)
+	gomock.InOrder(
+		mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{detachedVol}}, nil),
+		mockEC2.EXPECT().AttachVolumeWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).Return(attachment, nil),
+		mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{attachedVol}}, nil).Times(1),
+	)
+
+	path, err := c.AttachDisk(ctx, volumeID, nodeID)
+	if err != nil {
+		t.Fatalf("AttachDisk() failed: expected no error, got: %v", err)
+	}
+	if path != devicePath {
+		t.Fatalf("AttachDisk() failed: expected device path %q, got %q", devicePath, path)
+	}
+
+	mockCtrl.Finish()
+}
+
+func TestAttachDiskConcurrent(t *testing.T) {
+	nodeID := "node-1234"
+	const volumes = 20
+
+	mockCtrl := gomock.NewController(t)
+	mockEC2 := mocks.NewMockEC2(mockCtrl)
+	c := newCloud(mockEC2)
+
+	var mux sync.Mutex
+	attached := map[string]bool{}
+	var attaching, overlaps int
+
+	ctx := context.Background()
+	mockEC2.EXPECT().DescribeInstancesWithContext(gomock.Eq(ctx), gomock.Any()).Return(newDescribeInstancesOutput(nodeID), nil).Times(volumes)
+	mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+		func(_ aws.Context, input *ec2.DescribeVolumesInput, _ ...request.Option) (*ec2.DescribeVolumesOutput, error) {
+			volumeID := aws.StringValue(input.VolumeIds[0])
+			volume := &ec2.Volume{VolumeId: aws.String(volumeID)}
+			mux.Lock()
+			defer mux.Unlock()
+			if attached[volumeID] {
+				volume.Attachments = []*ec2.VolumeAttachment{
+					{InstanceId: aws.String(nodeID), Device: aws.String(dm.DevicePath(volumeID)), State: aws.String("attached")},
+				}
+			}
+			return &ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{volume}}, nil
+		}).Times(2 * volumes)
+	mockEC2.EXPECT().AttachVolumeWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ aws.Context, input *ec2.AttachVolumeInput, _ ...request.Option) (*ec2.VolumeAttachment, error) {
+			volumeID := aws.StringValue(input.VolumeId)
+			mux.Lock()
+			attaching++
+			if attaching > 1 {
+				overlaps++
+			}
+			mux.Unlock()
+
+			// Leave time for another attachment to the instance to start
+			time.Sleep(time.Millisecond)
+
+			mux.Lock()
+			defer mux.Unlock()
+			attaching--
+			attached[volumeID] = true
+			return &ec2.VolumeAttachment{VolumeId: input.VolumeId, InstanceId: input.InstanceId, State: aws.String("attached")}, nil
+		}).Times(volumes)
+
+	var wg sync.WaitGroup
+	paths := make([]string, volumes)
+	errs := make([]error, volumes)
+	for i := 0; i < volumes; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			paths[i], errs[i] = c.AttachDisk(ctx, fmt.Sprintf("vol-%08x", i), nodeID)
+		}(i)
+	}
+	wg.Wait()
+
+	seen := map[string]bool{}
+	for i := 0; i < volumes; i++ {
+		if errs[i] != nil {
+			t.Fatalf("AttachDisk() failed: expected no error, got: %v", errs[i])
+		}
+		if expected := dm.DevicePath(fmt.Sprintf("vol-%08x", i)); paths[i] != expected {
+			t.Fatalf("AttachDisk() failed: expected device path %q, got %q", expected, paths[i])
+		}
+		if seen[paths[i]] {
+			t.Fatalf("AttachDisk() failed: device path %q assigned twice", paths[i])
+		}
+		seen[paths[i]] = true
+	}
+	if overlaps > 0 {
+		t.Fatalf("AttachDisk() failed: expected the attachments to the instance to be serialized, %d overlapped", overlaps)
+	}
+
+	mockCtrl.Finish()
+}
+
+func TestAttachDiskVolumeNotFoundYet(t *testing.T) {
+	volumeID := "vol-000000aa"
+	nodeID := "node-1234"
+	volumeNotFound := awserr.New("InvalidVolume.NotFound", "", nil)
+
+	testCases := []struct {
+		name       string
+		retries    int
+		attachErrs []error
+		expErr     bool
+	}{
+		{
+			name:       "success: found on retry",
+			retries:    2,
+			attachErrs: []error{volumeNotFound, volumeNotFound, nil},
+		},
+		{
+			name:       "fail: not found after the retries",
+			retries:    1,
+			attachErrs: []error{volumeNotFound, volumeNotFound},
+			expErr:     true,
+		},
+		{
+			name:       "fail: not found without retries",
+			attachErrs: []error{volumeNotFound},
+			expErr:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2).(*cloud)
+			c.options.attachNotFoundRetries = tc.retries
+			c.options.attachNotFoundRetryInterval = time.Millisecond
+
+			detachedVol := &ec2.Volume{
+				VolumeId: aws.String(volumeID),
+			}
+			attachedVol := &ec2.Volume{
+				VolumeId: aws.String(volumeID),
+				Attachments: []*ec2.VolumeAttachment{
+					{InstanceId: aws.String(nodeID), Device: aws.String(dm.DevicePath(volumeID)), State: aws.String("attached")},
+				},
+			}
+
+			ctx := context.Background()
+			instances := newDescribeInstancesOutput(nodeID)
+			mockEC2.EXPECT().DescribeInstancesWithContext(gomock.Eq(ctx), gomock.Any()).Return(instances, nil)
+			calls := []*gomock.Call{
+				mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{detachedVol}}, nil),
+			}
+			for _, attachErr := range tc.attachErrs {
+				calls = append(calls, mockEC2.EXPECT().AttachVolumeWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).Return(&ec2.VolumeAttachment{}, attachErr))
+			}
+			gomock.InOrder(calls...)
+			if !tc.expErr {
+				mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{attachedVol}}, nil).After(calls[len(calls)-1]).AnyTimes()
+			}
+
+			_, err := c.AttachDisk(ctx, volumeID, nodeID)
+			if tc.expErr {
+				if err == nil {
+					t.Fatal("AttachDisk() failed: expected error, got nothing")
+				}
+				// The device is released for the next attempt
+				device, err := c.dm.NewDevice(instances.Reservations[0].Instances[0], volumeID)
+				if err != nil {
+					t.Fatalf("NewDevice() failed: expected no error, got: %v", err)
+				}
+				if device.IsAlreadyAssigned {
+					t.Fatal("AttachDisk() failed: expected the device to be released")
+				}
+			}
+			if !tc.expErr && err != nil {
+				t.Fatalf("AttachDisk() failed: expected no error, got: %v", err)
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestAttachDiskMultiAttach(t *testing.T) {
+	volumeID := "vol-000000aa"
+	nodeID := "node-1234"
+	devicePath := "/dev/disk/by-id/virtio-" + volumeID
+	otherAttachment := &ec2.VolumeAttachment{InstanceId: aws.String("node-5678"), Device: aws.String(devicePath), State: aws.String("attached")}
+
+	testCases := []struct {
+		name        string
+		multiAttach bool
+		attachErr   error
+		expErr      error
+	}{
+		{
+			name:        "success: attached to another instance",
+			multiAttach: true,
+		},
+		{
+			name:      "fail: in use without multi-attach",
+			attachErr: awserr.New("VolumeInUse", "", nil),
+			expErr:    ErrAlreadyExists,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			inUseVol := &ec2.Volume{
+				VolumeId:           aws.String(volumeID),
+				MultiAttachEnabled: aws.Bool(tc.multiAttach),
+				Attachments:        []*ec2.VolumeAttachment{otherAttachment},
+			}
+			attachedVol := &ec2.Volume{
+				VolumeId:           aws.String(volumeID),
+				MultiAttachEnabled: aws.Bool(tc.multiAttach),
+				Attachments: []*ec2.VolumeAttachment{
+					otherAttachment,
+					{InstanceId: aws.String(nodeID), Device: aws.String(devicePath), State: aws.String("attached")},
+				},
+			}
+
+			ctx := context.Background()
+			mockEC2.EXPECT().DescribeInstancesWithContext(gomock.Eq(ctx), gomock.Any()).Return(newDescribeInstancesOutput(nodeID), nil)
+			describeInUse := mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{inUseVol}}, nil)
+			attach := mockEC2.EXPECT().AttachVolumeWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).Return(&ec2.VolumeAttachment{}, tc.attachErr).After(describeInUse)
+			if tc.attachErr == nil {
+				mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{attachedVol}}, nil).After(attach).AnyTimes()
+			} else {
+				// Described again to check the volume isn't in use by the node
+				mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{inUseVol}}, nil).After(attach)
+			}
+
+			path, err := c.AttachDisk(ctx, volumeID, nodeID)
+			if err != tc.expErr {
+				t.Fatalf("AttachDisk() failed: expected error %v, got: %v", tc.expErr, err)
+			}
+			if err == nil && path != devicePath {
+				t.Fatalf("AttachDisk() failed: expected device path %q, got %q", devicePath, path)
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestAttachDiskInUseByNode(t *testing.T) {
+	volumeID := "vol-000000aa"
+	nodeID := "node-1234"
+	devicePath := "/dev/disk/by-id/virtio-" + volumeID
+
+	testCases := []struct {
+		name  string
+		state string
+	}{
+		{
+			name:  "success: attached by a concurrent call",
+			state: "attached",
+		},
+		{
+			name:  "success: being attached by a concurrent call",
+			state: "attaching",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			detachedVol := &ec2.Volume{
+				VolumeId: aws.String(volumeID),
+			}
+			inUseVol := &ec2.Volume{
+				VolumeId: aws.String(volumeID),
+				Attachments: []*ec2.VolumeAttachment{
+					{InstanceId: aws.String(nodeID), Device: aws.String(devicePath), State: aws.String(tc.state)},
+				},
+			}
+			attachedVol := &ec2.Volume{
+				VolumeId: aws.String(volumeID),
+				Attachments: []*ec2.VolumeAttachment{
+					{InstanceId: aws.String(nodeID), Device: aws.String(devicePath), State: aws.String("attached")},
+				},
+			}
+
+			ctx := context.Background()
+			mockEC2.EXPECT().DescribeInstancesWithContext(gomock.Eq(ctx), gomock.Any()).Return(newDescribeInstancesOutput(nodeID), nil)
+			gomock.InOrder(
+				mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{detachedVol}}, nil),
+				mockEC2.EXPECT().AttachVolumeWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).Return(nil, awserr.New("VolumeInUse", "", nil)),
+				mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{inUseVol}}, nil),
+				mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{attachedVol}}, nil).AnyTimes(),
+			)
+
+			path, err := c.AttachDisk(ctx, volumeID, nodeID)
+			if err != nil {
+				t.Fatalf("AttachDisk() failed: expected no error, got: %v", err)
+			}
+			if path != devicePath {
+				t.Fatalf("AttachDisk() failed: expected device path %q, got %q", devicePath, path)
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestAttachDiskPendingInstance(t *testing.T) {
+	volumeID := "vol-000000aa"
+	nodeID := "node-1234"
+
+	newInstancesOutput := func(state string) *ec2.DescribeInstancesOutput {
+		output := newDescribeInstancesOutput(nodeID)
+		output.Reservations[0].Instances[0].State = &ec2.InstanceState{Name: aws.String(state)}
+		return output
+	}
+
+	testCases := []struct {
+		name       string
+		finalState string
+		expErr     bool
+	}{
+		{
+			name:       "success: pending then running",
+			finalState: ec2.InstanceStateNameRunning,
+		},
+		{
+			name:       "fail: pending then terminated",
+			finalState: ec2.InstanceStateNameTerminated,
+			expErr:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2).(*cloud)
+			c.options.instanceRunningTimeout = time.Minute
+
+			detachedVol := &ec2.Volume{
+				VolumeId: aws.String(volumeID),
+			}
+			attachedVol := &ec2.Volume{
+				VolumeId: aws.String(volumeID),
+				Attachments: []*ec2.VolumeAttachment{
+					{
+						InstanceId: aws.String(nodeID),
+						Device:     aws.String("/dev/disk/by-id/virtio-" + volumeID),
+						State:      aws.String("attached"),
+					},
+				},
+			}
+
+			ctx := context.Background()
+			gomock.InOrder(
+				mockEC2.EXPECT().DescribeInstancesWithContext(gomock.Eq(ctx), gomock.Any()).Return(newInstancesOutput(ec2.InstanceStateNamePending), nil).Times(2),
+				mockEC2.EXPECT().DescribeInstancesWithContext(gomock.Eq(ctx), gomock.Any()).Return(newInstancesOutput(tc.finalState), nil),
+			)
+			if !tc.expErr {
+				describeDetached := mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{detachedVol}}, nil)
+				attach := mockEC2.EXPECT().AttachVolumeWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).Return(&ec2.VolumeAttachment{}, nil).After(describeDetached)
+				mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{attachedVol}}, nil).After(attach).AnyTimes()
+			}
+
+			_, err := c.AttachDisk(ctx, volumeID, nodeID)
+			if tc.expErr && err == nil {
+				t.Fatal("AttachDisk() failed: expected error, got nothing")
+			}
+			if !tc.expErr && err != nil {
+				t.Fatalf("AttachDisk() failed: expected no error, got: %v", err)
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestAttachDiskInstanceState(t *testing.T) {
+	volumeID := "vol-000000aa"
+	nodeID := "node-1234"
+
+	testCases := []struct {
+		name   string
+		state  string
+		expErr bool
+	}{
+		{
+			name:  "success: running",
+			state: ec2.InstanceStateNameRunning,
+		},
+		{
+			name:  "success: stopped",
+			state: ec2.InstanceStateNameStopped,
+		},
+		{
+			name:   "fail: stopping",
+			state:  ec2.InstanceStateNameStopping,
+			expErr: true,
+		},
+		{
+			name:   "fail: shutting down",
+			state:  ec2.InstanceStateNameShuttingDown,
+			expErr: true,
+		},
+		{
+			name:   "fail: terminated",
+			state:  ec2.InstanceStateNameTerminated,
+			expErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			instancesOutput := newDescribeInstancesOutput(nodeID)
+			instancesOutput.Reservations[0].Instances[0].State = &ec2.InstanceState{Name: aws.String(tc.state)}
+			detachedVol := &ec2.Volume{
+				VolumeId: aws.String(volumeID),
+			}
+			attachedVol := &ec2.Volume{
+				VolumeId: aws.String(volumeID),
+				Attachments: []*ec2.VolumeAttachment{
+					{
+						InstanceId: aws.String(nodeID),
+						Device:     aws.String("/dev/disk/by-id/virtio-" + volumeID),
+						State:      aws.String("attached"),
+					},
+				},
+			}
+
+			ctx := context.Background()
+			mockEC2.EXPECT().DescribeInstancesWithContext(gomock.Eq(ctx), gomock.Any()).Return(instancesOutput, nil)
+			if !tc.expErr {
+				describeDetached := mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{detachedVol}}, nil)
+				attach := mockEC2.EXPECT().AttachVolumeWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).Return(&ec2.VolumeAttachment{}, nil).After(describeDetached)
+				mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{attachedVol}}, nil).After(attach).AnyTimes()
+			}
+
+			_, err := c.AttachDisk(ctx, volumeID, nodeID)
+			if tc.expErr {
+				if err == nil {
+					t.Fatal("AttachDisk() failed: expected error, got nothing")
+				}
+				if !strings.Contains(err.Error(), tc.state) {
+					t.Fatalf("AttachDisk() failed: expected error naming state %q, got: %v", tc.state, err)
+				}
+			}
+			if !tc.expErr && err != nil {
+				t.Fatalf("AttachDisk() failed: expected no error, got: %v", err)
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestWithoutDeviceName(t *testing.T) {
+	var (
+		form     url.Values
+		requests int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("could not parse request: %v", err)
+		}
+		form = r.PostForm
+		fmt.Fprint(w, `<AttachVolumeResponse><volumeId>vol-000000aa</volumeId><status>attaching</status></AttachVolumeResponse>`)
+	}))
+	defer server.Close()
+
+	sess := session.Must(session.NewSession(&aws.Config{
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		Endpoint:    aws.String(server.URL),
+		Region:      aws.String("test-region"),
+		MaxRetries:  aws.Int(0),
+	}))
+	input := &ec2.AttachVolumeInput{
+		InstanceId: aws.String("node-1234"),
+		VolumeId:   aws.String("vol-000000aa"),
+	}
+
+	if _, err := ec2.New(sess).AttachVolumeWithContext(context.Background(), input, withoutDeviceName); err != nil {
+		t.Fatalf("AttachVolume() failed: expected no error, got: %v", err)
+	}
+	if form.Get("VolumeId") != "vol-000000aa" {
+		t.Fatalf("AttachVolume() failed: expected volume ID %q, got %q", "vol-000000aa", form.Get("VolumeId"))
+	}
+	if _, ok := form["Device"]; ok {
+		t.Fatalf("AttachVolume() failed: expected no device name, got %q", form.Get("Device"))
+	}
+
+	input = &ec2.AttachVolumeInput{
+		InstanceId: aws.String("node-1234"),
+	}
+	_, err := ec2.New(sess).AttachVolumeWithContext(context.Background(), input, withoutDeviceName)
+	if _, ok := err.(request.ErrInvalidParams); !ok {
+		t.Fatalf("AttachVolume() failed: expected invalid parameters error, got: %v", err)
+	}
+	if requests != 1 {
+		t.Fatalf("AttachVolume() failed: expected the request without volume ID not to be sent, got %d requests", requests)
+	}
+}
+
+func TestWithVolumeInitializationRate(t *testing.T) {
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("could not parse request: %v", err)
+		}
+		form = r.PostForm
+		fmt.Fprint(w, `<CreateVolumeResponse><volumeId>vol-000000aa</volumeId><size>1</size><status>creating</status></CreateVolumeResponse>`)
+	}))
+	defer server.Close()
+
+	sess := session.Must(session.NewSession(&aws.Config{
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		Endpoint:    aws.String(server.URL),
+		Region:      aws.String("test-region"),
+		MaxRetries:  aws.Int(0),
+	}))
+	input := &ec2.CreateVolumeInput{
+		AvailabilityZone: aws.String(defaultZone),
+		Size:             aws.Int64(1),
+		SnapshotId:       aws.String("snap-000000aa"),
+	}
+
+	if _, err := ec2.New(sess).CreateVolumeWithContext(context.Background(), input, withVolumeInitializationRate(200)); err != nil {
+		t.Fatalf("CreateVolume() failed: expected no error, got: %v", err)
+	}
+	if form.Get("VolumeInitializationRate") != "200" {
+		t.Fatalf("CreateVolume() failed: expected volume initialization rate %q, got %q", "200", form.Get("VolumeInitializationRate"))
+	}
+	if form.Get("SnapshotId") != "snap-000000aa" || form.Get("Action") != "CreateVolume" {
+		t.Fatalf("CreateVolume() failed: expected the other parameters to be kept, got %v", form)
+	}
+}
+
+func TestCreateDiskVolumeInitializationRate(t *testing.T) {
+	testCases := []struct {
+		name       string
+		snapshotID string
+		rate       int64
+		expErr     bool
+	}{
+		{
+			name:       "success: rate within range",
+			snapshotID: "snap-000000aa",
+			rate:       MinVolumeInitializationRate,
+		},
+		{
+			name:       "fail: rate below range",
+			snapshotID: "snap-000000aa",
+			rate:       MinVolumeInitializationRate - 1,
+			expErr:     true,
+		},
+		{
+			name:       "fail: rate above range",
+			snapshotID: "snap-000000aa",
+			rate:       MaxVolumeInitializationRate + 1,
+			expErr:     true,
+		},
+		{
+			name:   "fail: rate without snapshot",
+			rate:   MinVolumeInitializationRate,
+			expErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2).(*cloud)
+			c.options.skipSnapshotPreChecks = true
+
+			vol := &ec2.Volume{
+				VolumeId:         aws.String("vol-000000a8"),
+				Size:             aws.Int64(1),
+				State:            aws.String("available"),
+				AvailabilityZone: aws.String(expZone),
+			}
+
+			ctx := context.Background()
+			if !tc.expErr {
+				// The rate is sent as a request option
+				mockEC2.EXPECT().CreateVolumeWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).Return(vol, nil)
+				mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{vol}}, nil)
+			}
+
+			diskOptions := &DiskOptions{
+				CapacityBytes:            util.GiB,
+				Tags:                     map[string]string{VolumeNameTagKey: "vol-000000a8"},
+				AvailabilityZone:         expZone,
+				SnapshotID:               tc.snapshotID,
+				VolumeInitializationRate: tc.rate,
+			}
+			_, err := c.CreateDisk(ctx, "vol-000000ae", diskOptions)
+			if tc.expErr && err == nil {
+				t.Fatal("CreateDisk() failed: expected error, got nothing")
+			}
+			if !tc.expErr && err != nil {
+				t.Fatalf("CreateDisk() failed: expected no error, got: %v", err)
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestAttachDiskAlreadyAttached(t *testing.T) {
+	volumeID := "vol-000000aa"
+	nodeID := "node-1234"
+
+	mockCtrl := gomock.NewController(t)
+	mockEC2 := mocks.NewMockEC2(mockCtrl)
+	c := newCloud(mockEC2)
+
+	vol := &ec2.Volume{
+		VolumeId: aws.String(volumeID),
+		Attachments: []*ec2.VolumeAttachment{
+			{InstanceId: aws.String(nodeID), State: aws.String("attached")},
+		},
+	}
+
+	ctx := context.Background()
+	mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{vol}}, nil)
+	mockEC2.EXPECT().DescribeInstancesWithContext(gomock.Eq(ctx), gomock.Any()).Return(newDescribeInstancesOutput(nodeID), nil)
+	mockEC2.EXPECT().AttachVolumeWithContext(gomock.Any(), gomock.Any()).Times(0)
+
+	devicePath, err := c.AttachDisk(ctx, volumeID, nodeID)
+	if err != nil {
+		t.Fatalf("AttachDisk() failed: expected no error, got: %v", err)
+	}
+	if expPath := "/dev/disk/by-id/virtio-" + volumeID; devicePath != expPath {
+		t.Fatalf("AttachDisk() failed: expected device path %q, got %q", expPath, devicePath)
+	}
+
+	mockCtrl.Finish()
+}
+
+func TestVerifyAttachment(t *testing.T) {
+	volumeID := "vol-000000aa"
+	nodeID := "node-1234"
+	devicePath := "/dev/disk/by-id/virtio-" + volumeID
+
+	testCases := []struct {
+		name        string
+		attachments []*ec2.VolumeAttachment
+		expErr      bool
+	}{
+		{
+			name: "success: attached at the device",
+			attachments: []*ec2.VolumeAttachment{
+				{InstanceId: aws.String(nodeID), Device: aws.String(devicePath), State: aws.String("attached")},
+			},
+		},
+		{
+			name:   "fail: no attachment",
+			expErr: true,
+		},
+		{
+			name: "fail: several attachments",
+			attachments: []*ec2.VolumeAttachment{
+				{InstanceId: aws.String(nodeID), Device: aws.String(devicePath), State: aws.String("attached")},
+				{InstanceId: aws.String("node-5678"), Device: aws.String(devicePath), State: aws.String("attached")},
+			},
+			expErr: true,
+		},
+		{
+			name: "fail: attached to another instance",
+			attachments: []*ec2.VolumeAttachment{
+				{InstanceId: aws.String("node-5678"), Device: aws.String(devicePath), State: aws.String("attached")},
+			},
+			expErr: true,
+		},
+		{
+			name: "fail: attached at another device",
+			attachments: []*ec2.VolumeAttachment{
+				{InstanceId: aws.String(nodeID), Device: aws.String("/dev/xvdba"), State: aws.String("attached")},
+			},
+			expErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2).(*cloud)
+
+			vol := &ec2.Volume{
+				VolumeId:    aws.String(volumeID),
+				Attachments: tc.attachments,
+			}
+
+			ctx := context.Background()
+			mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{vol}}, nil)
+
+			err := c.verifyAttachment(ctx, volumeID, nodeID, devicePath)
+			if err != nil {
+				if !tc.expErr {
+					t.Fatalf("verifyAttachment() failed: expected no error, got: %v", err)
+				}
+			} else {
+				if tc.expErr {
+					t.Fatal("verifyAttachment() failed: expected error, got nothing")
+				}
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestCreateAndAttachDisk(t *testing.T) {
+	volumeID := "vol-000000aa"
+	nodeID := "node-1234"
+
+	testCases := []struct {
+		name      string
+		attachErr error
+		expErr    bool
+	}{
+		{
+			name: "success: normal",
+		},
+		{
+			name:      "fail: AttachVolume returned generic error",
+			attachErr: fmt.Errorf("AttachVolume generic error"),
+			expErr:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			instances := newDescribeInstancesOutput(nodeID)
+			instances.Reservations[0].Instances[0].Placement = &ec2.Placement{AvailabilityZone: aws.String(expZone)}
+			availableVol := &ec2.Volume{
+				VolumeId:         aws.String(volumeID),
+				Size:             aws.Int64(1),
+				State:            aws.String("available"),
+				AvailabilityZone: aws.String(expZone),
+			}
+			attachedVol := &ec2.Volume{
+				VolumeId:         aws.String(volumeID),
+				Size:             aws.Int64(1),
+				State:            aws.String("in-use"),
+				AvailabilityZone: aws.String(expZone),
+				Attachments: []*ec2.VolumeAttachment{
+					{
+						InstanceId: aws.String(nodeID),
+						Device:     aws.String("/dev/disk/by-id/virtio-" + volumeID),
+						State:      aws.String("attached"),
+					},
+				},
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			mockEC2.EXPECT().DescribeInstancesWithContext(gomock.Eq(ctx), gomock.Any()).Return(instances, nil).Times(2)
+			gomock.InOrder(
+				mockEC2.EXPECT().CreateVolumeWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+					func(_ aws.Context, input *ec2.CreateVolumeInput, _ ...request.Option) (*ec2.Volume, error) {
+						if zone := aws.StringValue(input.AvailabilityZone); zone != expZone {
+							t.Fatalf("CreateVolume() called with zone %q, expected %q", zone, expZone)
+						}
+						return availableVol, nil
+					}),
+				// waitForVolume, then isVolumeAttachedToNode
+				mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{availableVol}}, nil).Times(2),
+				mockEC2.EXPECT().AttachVolumeWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ aws.Context, _ *ec2.AttachVolumeInput, _ ...request.Option) (*ec2.VolumeAttachment, error) {
+						if tc.attachErr != nil {
+							// The caller gives up, the volume must be deleted all the same.
+							cancel()
+						}
+						return &ec2.VolumeAttachment{}, tc.attachErr
+					}),
+			)
+			if tc.expErr {
+				mockEC2.EXPECT().DeleteVolumeWithContext(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(deleteCtx aws.Context, _ *ec2.DeleteVolumeInput, _ ...request.Option) (*ec2.DeleteVolumeOutput, error) {
+						if err := deleteCtx.Err(); err != nil {
+							t.Fatalf("DeleteVolume() called with a done context: %v", err)
+						}
+						if _, ok := deleteCtx.Deadline(); !ok {
+							t.Fatal("DeleteVolume() called with a context without deadline")
+						}
+						return &ec2.DeleteVolumeOutput{}, nil
+					})
+			} else {
+				mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{attachedVol}}, nil).AnyTimes()
+			}
+
+			diskOptions := &DiskOptions{
+				CapacityBytes: util.GiBToBytes(1),
+				Tags:          map[string]string{VolumeNameTagKey: "vol-000000a8"},
+			}
+			disk, devicePath, err := c.CreateAndAttachDisk(ctx, "vol-000000ae", nodeID, diskOptions)
+			if err != nil {
+				if !tc.expErr {
+					t.Fatalf("CreateAndAttachDisk() failed: expected no error, got: %v", err)
+				}
+			} else {
+				if tc.expErr {
+					t.Fatal("CreateAndAttachDisk() failed: expected error, got nothing")
+				}
+				if disk.VolumeID != volumeID || disk.AvailabilityZone != expZone {
+					t.Fatalf("CreateAndAttachDisk() failed: expected volume %q in zone %q, got %+v", volumeID, expZone, disk)
+				}
+				if expPath := "/dev/disk/by-id/virtio-" + volumeID; devicePath != expPath {
+					t.Fatalf("CreateAndAttachDisk() failed: expected device path %q, got %q", expPath, devicePath)
+				}
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestGetVolumeAttachments(t *testing.T) {
+	volumeID := "vol-000000aa"
+
+	testCases := []struct {
+		name           string
+		attachments    []*ec2.VolumeAttachment
+		describeErr    error
+		expAttachments []VolumeAttachment
+		expErr         error
+	}{
+		{
+			name:           "success: detached",
+			expAttachments: []VolumeAttachment{},
+		},
+		{
+			name: "success: multi-attach",
+			attachments: []*ec2.VolumeAttachment{
+				{InstanceId: aws.String("node-1234"), Device: aws.String("/dev/xvdba"), State: aws.String("attached")},
+				{InstanceId: aws.String("node-5678"), Device: aws.String("/dev/xvdbb"), State: aws.String("attaching")},
+			},
+			expAttachments: []VolumeAttachment{
+				{InstanceID: "node-1234", Device: "/dev/xvdba", State: "attached"},
+				{InstanceID: "node-5678", Device: "/dev/xvdbb", State: "attaching"},
+			},
+		},
+		{
+			name:        "fail: volume not found",
+			describeErr: awserr.New("InvalidVolume.NotFound", "", nil),
+			expErr:      ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			vol := &ec2.Volume{
+				VolumeId:    aws.String(volumeID),
+				Attachments: tc.attachments,
+			}
+
+			ctx := context.Background()
+			mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{vol}}, tc.describeErr)
+
+			attachments, err := c.GetVolumeAttachments(ctx, volumeID)
+			if err != tc.expErr {
+				t.Fatalf("GetVolumeAttachments() failed: expected error %v, got: %v", tc.expErr, err)
+			}
+			if !reflect.DeepEqual(attachments, tc.expAttachments) {
+				t.Fatalf("GetVolumeAttachments() failed: expected attachments %+v, got %+v", tc.expAttachments, attachments)
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestGetExpectedDevicePath(t *testing.T) {
+	volumeID := "vol-000000aa"
+	nodeID := "node-1234"
+
+	mockCtrl := gomock.NewController(t)
+	mockEC2 := mocks.NewMockEC2(mockCtrl)
+	c := newCloud(mockEC2)
+
+	ctx := context.Background()
+	mockEC2.EXPECT().DescribeInstancesWithContext(gomock.Eq(ctx), gomock.Any()).Return(newDescribeInstancesOutput(nodeID), nil)
+
+	devicePath, err := c.GetExpectedDevicePath(ctx, volumeID, nodeID)
+	if err != nil {
+		t.Fatalf("GetExpectedDevicePath() failed: expected no error, got: %v", err)
+	}
+	if expPath := "/dev/disk/by-id/virtio-" + volumeID; devicePath != expPath {
+		t.Fatalf("GetExpectedDevicePath() failed: expected path %q, got %q", expPath, devicePath)
+	}
+
+	if serial := NVMeSerial(volumeID); serial != "voltest1234" {
+		t.Fatalf("NVMeSerial() failed: expected serial %q, got %q", "voltest1234", serial)
+	}
+
+	mockCtrl.Finish()
+}
+
+func TestGetAvailabilityZonesForVolumeType(t *testing.T) {
+	testCases := []struct {
+		name        string
+		volumeType  string
+		zoneErrs    map[string]error
+		expZones    []string
+		expErr      bool
+		expDescribe bool
+	}{
+		{
+			name:       "success: supported in all zones",
+			volumeType: VolumeTypeGP2,
+			zoneErrs: map[string]error{
+				"zone-1": awserr.New("DryRunOperation", "", nil),
+				"zone-2": awserr.New("DryRunOperation", "", nil),
+			},
+			expZones:    []string{"zone-1", "zone-2"},
+			expDescribe: true,
+		},
+		{
+			name:       "success: unsupported zone excluded",
+			volumeType: VolumeTypeIO2,
+			zoneErrs: map[string]error{
+				"zone-1": awserr.New("UnsupportedOperation", "", nil),
+				"zone-2": awserr.New("DryRunOperation", "", nil),
+			},
+			expZones:    []string{"zone-2"},
+			expDescribe: true,
+		},
+		{
+			name:       "success: dry run ignored",
+			volumeType: VolumeTypeGP2,
+			zoneErrs: map[string]error{
+				"zone-1": nil,
+				"zone-2": awserr.New("DryRunOperation", "", nil),
+			},
+			expZones:    []string{"zone-1", "zone-2"},
+			expDescribe: true,
+		},
+		{
+			name:       "fail: invalid parameter",
+			volumeType: VolumeTypeIO2,
+			zoneErrs: map[string]error{
+				"zone-1": awserr.New("InvalidParameterValue", "Invalid IOPS", nil),
+			},
+			expErr:      true,
+			expDescribe: true,
+		},
+		{
+			name:       "fail: dry run failed",
+			volumeType: VolumeTypeGP2,
+			zoneErrs: map[string]error{
+				"zone-1": awserr.New("UnauthorizedOperation", "", nil),
+			},
+			expErr:      true,
+			expDescribe: true,
+		},
+		{
+			name:       "fail: invalid volume type",
+			volumeType: "gp9",
+			expErr:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			ctx := context.Background()
+			if tc.expDescribe {
+				zoneNames := []string{}
+				for zone := range tc.zoneErrs {
+					zoneNames = append(zoneNames, zone)
+				}
+				sort.Strings(zoneNames)
+				zones := []*ec2.AvailabilityZone{}
+				for _, zone := range zoneNames {
+					zones = append(zones, &ec2.AvailabilityZone{ZoneName: aws.String(zone)})
+				}
+				mockEC2.EXPECT().DescribeAvailabilityZonesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeAvailabilityZonesOutput{AvailabilityZones: zones}, nil)
+				mockEC2.EXPECT().CreateVolumeWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+					func(_ aws.Context, input *ec2.CreateVolumeInput, _ ...request.Option) (*ec2.Volume, error) {
+						if !aws.BoolValue(input.DryRun) {
+							t.Fatal("CreateVolume() called without dry run")
+						}
+						if aws.StringValue(input.VolumeType) != tc.volumeType {
+							t.Fatalf("CreateVolume() called with volume type %q, expected %q", aws.StringValue(input.VolumeType), tc.volumeType)
+						}
+						return nil, tc.zoneErrs[aws.StringValue(input.AvailabilityZone)]
+					}).MaxTimes(len(zones))
+			}
+
+			zones, err := c.GetAvailabilityZonesForVolumeType(ctx, tc.volumeType)
+			if tc.expErr {
+				if err == nil {
+					t.Fatal("GetAvailabilityZonesForVolumeType() failed: expected error, got nothing")
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("GetAvailabilityZonesForVolumeType() failed: expected no error, got: %v", err)
+				}
+				if !reflect.DeepEqual(zones, tc.expZones) {
+					t.Fatalf("GetAvailabilityZonesForVolumeType() failed: expected zones %v, got %v", tc.expZones, zones)
+				}
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestDetachDisk(t *testing.T) {
+	testCases := []struct {
+		name     string
+		volumeID string
+		nodeID   string
+		expErr   error
+	}{
+		{
+			name:     "success: normal",
+			volumeID: "vol-000000aa",
+			nodeID:   "node-1234",
+			expErr:   nil,
+		},
+		{
+			name:     "fail: DetachVolume returned generic error",
+			volumeID: "vol-000000aa",
+			nodeID:   "node-1234",
+			expErr:   fmt.Errorf("DetachVolume generic error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			vol := &ec2.Volume{
+				VolumeId:    aws.String(tc.volumeID),
+				Attachments: nil,
+			}
+
+			ctx := context.Background()
+			mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{vol}}, nil).AnyTimes()
+			mockEC2.EXPECT().DescribeInstancesWithContext(gomock.Eq(ctx), gomock.Any()).Return(newDescribeInstancesOutput(tc.nodeID), nil)
+			mockEC2.EXPECT().DetachVolumeWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.VolumeAttachment{}, tc.expErr)
+
+			err := c.DetachDisk(ctx, tc.volumeID, tc.nodeID)
+			if err != nil {
+				if tc.expErr == nil {
+					t.Fatalf("DetachDisk() failed: expected no error, got: %v", err)
+				}
+			} else {
+				if tc.expErr != nil {
+					t.Fatal("DetachDisk() failed: expected error, got nothing")
+				}
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestDetachDiskNotFoundReleasesDevice(t *testing.T) {
+	volumeID := "vol-000000aa"
+	nodeID := "node-1234"
+
+	mockCtrl := gomock.NewController(t)
+	mockEC2 := mocks.NewMockEC2(mockCtrl)
+	c := newCloud(mockEC2).(*cloud)
+
+	ctx := context.Background()
+	mockEC2.EXPECT().DescribeInstancesWithContext(gomock.Eq(ctx), gomock.Any()).Return(newDescribeInstancesOutput(nodeID), nil).Times(2)
+	mockEC2.EXPECT().DetachVolumeWithContext(gomock.Eq(ctx), gomock.Any()).Return(nil, awserr.New("InvalidVolume.NotFound", "", nil))
+
+	// Assign a device to the volume, as an attach in progress would
+	instance, err := c.getInstance(ctx, nodeID)
+	if err != nil {
+		t.Fatalf("getInstance() failed: expected no error, got: %v", err)
+	}
+	if _, err := c.dm.NewDevice(instance, volumeID); err != nil {
+		t.Fatalf("NewDevice() failed: expected no error, got: %v", err)
+	}
+
+	if err := c.DetachDisk(ctx, volumeID, nodeID); err != ErrNotFound {
+		t.Fatalf("DetachDisk() failed: expected %v, got: %v", ErrNotFound, err)
+	}
+
+	device, err := c.dm.GetDevice(instance, volumeID)
+	if err != nil {
+		t.Fatalf("GetDevice() failed: expected no error, got: %v", err)
+	}
+	if device.IsAlreadyAssigned {
+		t.Fatal("DetachDisk() failed: expected the device to be released")
+	}
+
+	mockCtrl.Finish()
+}
+
+func TestOperationTimeout(t *testing.T) {
+	callerCtx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	testCases := []struct {
+		name        string
+		timeout     time.Duration
+		ctx         context.Context
+		expDeadline bool
+		expSameCtx  bool
+	}{
+		{
+			name:       "disabled",
+			ctx:        context.Background(),
+			expSameCtx: true,
+		},
+		{
+			name:        "context without deadline",
+			timeout:     time.Minute,
+			ctx:         context.Background(),
+			expDeadline: true,
+		},
+		{
+			name:        "context with deadline",
+			timeout:     time.Minute,
+			ctx:         callerCtx,
+			expDeadline: true,
+			expSameCtx:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2).(*cloud)
+			c.options.operationTimeout = tc.timeout
+
+			vol := &ec2.Volume{VolumeId: aws.String("vol-000000aa")}
+			mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx aws.Context, _ *ec2.DescribeVolumesInput, _ ...request.Option) (*ec2.DescribeVolumesOutput, error) {
+					if _, ok := ctx.Deadline(); ok != tc.expDeadline {
+						t.Fatalf("DescribeVolumes() called with deadline %t, expected %t", ok, tc.expDeadline)
+					}
+					if (ctx == tc.ctx) != tc.expSameCtx {
+						t.Fatalf("DescribeVolumes() called with the caller context %t, expected %t", ctx == tc.ctx, tc.expSameCtx)
+					}
+					return &ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{vol}}, nil
+				})
+
+			if _, err := c.GetDiskByID(tc.ctx, "vol-000000aa"); err != nil {
+				t.Fatalf("GetDiskByID() failed: expected no error, got: %v", err)
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestCloseAbortsWait(t *testing.T) {
+	volumeID := "vol-000000aa"
+
+	mockCtrl := gomock.NewController(t)
+	mockEC2 := mocks.NewMockEC2(mockCtrl)
+	c := newCloud(mockEC2)
+
+	vol := &ec2.Volume{
+		VolumeId: aws.String(volumeID),
+		Attachments: []*ec2.VolumeAttachment{
+			{InstanceId: aws.String("node-1234"), State: aws.String("attaching")},
+		},
+	}
+
+	ctx := context.Background()
+	mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{vol}}, nil).AnyTimes()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.WaitForAttachmentState(ctx, volumeID, "attached")
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	c.Close()
+
+	select {
+	case err := <-errCh:
+		if err != context.Canceled {
+			t.Fatalf("WaitForAttachmentState() failed: expected error %v, got: %v", context.Canceled, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("WaitForAttachmentState() failed: expected to return after Close")
+	}
+
+	mockCtrl.Finish()
+}
+
+func TestGetDiskByName(t *testing.T) {
+	testCases := []struct {
+		name             string
+		volumeName       string
+		volumeCapacity   int64
+		availabilityZone string
+		expErr           error
+	}{
+		{
+			name:             "success: normal",
+			volumeName:       "vol-000000aa",
+			volumeCapacity:   util.GiBToBytes(1),
+			availabilityZone: expZone,
+			expErr:           nil,
+		},
+		{
+			name:             "success: capacity not a whole GiB",
+			volumeName:       "vol-000000aa",
+			volumeCapacity:   util.GiBToBytes(1) + 1,
+			availabilityZone: expZone,
+			expErr:           nil,
+		},
+		{
+			name:           "fail: DescribeVolumes returned generic error",
+			volumeName:     "vol-000000aa",
+			volumeCapacity: util.GiBToBytes(1),
+			expErr:         fmt.Errorf("DescribeVolumes generic error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			vol := &ec2.Volume{
+				VolumeId:         aws.String(tc.volumeName),
+				Size:             aws.Int64(util.RoundUpGiB(tc.volumeCapacity)),
+				AvailabilityZone: aws.String(tc.availabilityZone),
+			}
+
+			ctx := context.Background()
+			mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{vol}}, tc.expErr)
+
+			disk, err := c.GetDiskByName(ctx, tc.volumeName, tc.volumeCapacity)
+			if err != nil {
+				if tc.expErr == nil {
+					t.Fatalf("GetDiskByName() failed: expected no error, got: %v", err)
+				}
+			} else {
+				if tc.expErr != nil {
+					t.Fatal("GetDiskByName() failed: expected error, got nothing")
+				}
+				if disk.CapacityGiB != util.RoundUpGiB(tc.volumeCapacity) {
+					t.Fatalf("GetDiskByName() failed: expected capacity %d, got %d", util.RoundUpGiB(tc.volumeCapacity), disk.CapacityGiB)
+				}
+				if tc.availabilityZone != disk.AvailabilityZone {
+					t.Fatalf("GetDiskByName() failed: expected availabilityZone %q, got %q", tc.availabilityZone, disk.AvailabilityZone)
+				}
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestDescribeMaxResults(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockEC2 := mocks.NewMockEC2(mockCtrl)
+	c := &cloud{
+		region: "test-region",
+		dm:     dm.NewDeviceManager(),
+		ec2:    mockEC2,
+		options: CloudOptions{
+			volumesMaxResults:   100,
+			snapshotsMaxResults: 200,
+		},
+	}
+
+	vol := &ec2.Volume{
+		VolumeId:         aws.String("vol-000000a8"),
+		Size:             aws.Int64(1),
+		AvailabilityZone: aws.String(expZone),
+	}
+	snapshot := &ec2.Snapshot{
+		SnapshotId: aws.String("snap-000000a9"),
+		VolumeId:   aws.String("vol-000000a8"),
+		State:      aws.String("completed"),
+	}
+
+	ctx := context.Background()
+	gomock.InOrder(
+		// Filtered describes use the configured page size
+		mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+			func(_ aws.Context, input *ec2.DescribeVolumesInput, _ ...request.Option) (*ec2.DescribeVolumesOutput, error) {
+				if aws.Int64Value(input.MaxResults) != 100 {
+					t.Fatalf("expected DescribeVolumes MaxResults 100, got %v", input.MaxResults)
+				}
+				return &ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{vol}}, nil
+			}),
+		// Describes by ID must not set it, since AWS rejects the combination
+		mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+			func(_ aws.Context, input *ec2.DescribeVolumesInput, _ ...request.Option) (*ec2.DescribeVolumesOutput, error) {
+				if input.MaxResults != nil {
+					t.Fatalf("expected DescribeVolumes MaxResults to be unset, got %v", aws.Int64Value(input.MaxResults))
+				}
+				return &ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{vol}}, nil
+			}),
+		mockEC2.EXPECT().DescribeSnapshotsWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+			func(_ aws.Context, input *ec2.DescribeSnapshotsInput, _ ...request.Option) (*ec2.DescribeSnapshotsOutput, error) {
+				if aws.Int64Value(input.MaxResults) != 200 {
+					t.Fatalf("expected DescribeSnapshots MaxResults 200, got %v", input.MaxResults)
+				}
+				return &ec2.DescribeSnapshotsOutput{Snapshots: []*ec2.Snapshot{snapshot}}, nil
+			}),
+	)
+
+	if _, err := c.GetDiskByName(ctx, "vol-000000a8", util.GiBToBytes(1)); err != nil {
+		t.Fatalf("GetDiskByName() failed: expected no error, got: %v", err)
+	}
+	if _, err := c.GetDiskByID(ctx, "vol-000000a8"); err != nil {
+		t.Fatalf("GetDiskByID() failed: expected no error, got: %v", err)
+	}
+	if _, err := c.GetSnapshotByName(ctx, "snap-000000a9"); err != nil {
+		t.Fatalf("GetSnapshotByName() failed: expected no error, got: %v", err)
+	}
+
+	mockCtrl.Finish()
+}
+
+func TestGetDiskByNameMultiDiskStrategy(t *testing.T) {
+	now := time.Now()
+	volumes := []*ec2.Volume{
+		{VolumeId: aws.String("vol-000000a5"), Size: aws.Int64(1), CreateTime: aws.Time(now)},
+		{VolumeId: aws.String("vol-000000a6"), Size: aws.Int64(1), CreateTime: aws.Time(now.Add(-time.Hour))},
+		{VolumeId: aws.String("vol-000000a1"), Size: aws.Int64(2), CreateTime: aws.Time(now.Add(-30 * time.Minute))},
+	}
+
+	testCases := []struct {
+		name        string
+		strategy    MultiDiskStrategy
+		sizeGiB     int64
+		expVolumeID string
+		expErr      error
+	}{
+		{
+			name:   "default: error",
+			expErr: ErrMultiDisks,
+		},
+		{
+			name:     "error",
+			strategy: MultiDiskStrategyError,
+			expErr:   ErrMultiDisks,
+		},
+		{
+			name:        "oldest",
+			strategy:    MultiDiskStrategyOldest,
+			expVolumeID: "vol-000000a6",
+		},
+		{
+			name:        "newest",
+			strategy:    MultiDiskStrategyNewest,
+			expVolumeID: "vol-000000a5",
+		},
+		{
+			name:        "matching size: oldest of the size",
+			strategy:    MultiDiskStrategyMatchingSize,
+			expVolumeID: "vol-000000a6",
+		},
+		{
+			name:        "matching size: single of the size",
+			strategy:    MultiDiskStrategyMatchingSize,
+			sizeGiB:     2,
+			expVolumeID: "vol-000000a1",
+		},
+		{
+			name:     "matching size: none of the size",
+			strategy: MultiDiskStrategyMatchingSize,
+			sizeGiB:  3,
+			expErr:   ErrDiskExistsDiffSize,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := &cloud{
+				region:  "test-region",
+				dm:      dm.NewDeviceManager(),
+				ec2:     mockEC2,
+				options: CloudOptions{multiDiskStrategy: tc.strategy},
+			}
+
+			ctx := context.Background()
+			mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: volumes}, nil)
+
+			sizeGiB := tc.sizeGiB
+			if sizeGiB == 0 {
+				sizeGiB = 1
+			}
+			disk, err := c.GetDiskByName(ctx, "vol-000000a4", util.GiBToBytes(sizeGiB))
+			if err != nil {
+				if tc.expErr == nil {
+					t.Fatalf("GetDiskByName() failed: expected no error, got: %v", err)
+				}
+				if err != tc.expErr {
+					t.Fatalf("GetDiskByName() failed: expected error %v, got: %v", tc.expErr, err)
+				}
+			} else {
+				if tc.expErr != nil {
+					t.Fatal("GetDiskByName() failed: expected error, got nothing")
+				}
+				if disk.VolumeID != tc.expVolumeID {
+					t.Fatalf("GetDiskByName() failed: expected volume %q, got %q", tc.expVolumeID, disk.VolumeID)
+				}
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestGetDiskByID(t *testing.T) {
+	testCases := []struct {
+		name             string
+		volumeID         string
+		availabilityZone string
+		kmsKeyID         string
+		expErr           error
+	}{
+		{
+			name:             "success: normal",
+			volumeID:         "vol-000000aa",
+			availabilityZone: expZone,
+			expErr:           nil,
+		},
+		{
+			name:             "success: encrypted",
+			volumeID:         "vol-000000aa",
+			availabilityZone: expZone,
+			kmsKeyID:         "arn:aws:kms:us-east-1:012345678910:key/abcd1234-a123-456a-a12b-a123b4cd56ef",
+			expErr:           nil,
+		},
+		{
+			name:     "fail: DescribeVolumes returned generic error",
+			volumeID: "vol-000000aa",
+			expErr:   fmt.Errorf("DescribeVolumes generic error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			var kmsKeyID *string
+			if tc.kmsKeyID != "" {
+				kmsKeyID = aws.String(tc.kmsKeyID)
+			}
+
+			ctx := context.Background()
+			mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(
+				&ec2.DescribeVolumesOutput{
+					Volumes: []*ec2.Volume{
+						{
+							VolumeId:         aws.String(tc.volumeID),
+							AvailabilityZone: aws.String(tc.availabilityZone),
+							Encrypted:        aws.Bool(tc.kmsKeyID != ""),
+							KmsKeyId:         kmsKeyID,
+						},
+					},
+				},
+				tc.expErr,
+			)
+
+			disk, err := c.GetDiskByID(ctx, tc.volumeID)
+			if err != nil {
+				if tc.expErr == nil {
+					t.Fatalf("GetDisk() failed: expected no error, got: %v", err)
+				}
+			} else {
+				if tc.expErr != nil {
+					t.Fatal("GetDisk() failed: expected error, got nothing")
+				}
+				if disk.VolumeID != tc.volumeID {
+					t.Fatalf("GetDisk() failed: expected ID %q, got %q", tc.volumeID, disk.VolumeID)
+				}
+				if tc.availabilityZone != disk.AvailabilityZone {
+					t.Fatalf("GetDiskByName() failed: expected availabilityZone %q, got %q", tc.availabilityZone, disk.AvailabilityZone)
+				}
+				if disk.KmsKeyID != tc.kmsKeyID {
+					t.Fatalf("GetDisk() failed: expected KMS key ID %q, got %q", tc.kmsKeyID, disk.KmsKeyID)
+				}
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestVolumeExists(t *testing.T) {
+	testCases := []struct {
+		name      string
+		volumeID  string
+		volumes   []*ec2.Volume
+		expExists bool
+		expErr    error
+	}{
+		{
+			name:     "success: volume exists",
+			volumeID: "vol-000000aa",
+			volumes: []*ec2.Volume{
+				{VolumeId: aws.String("vol-000000aa")},
+			},
+			expExists: true,
+		},
+		{
+			name:      "success: volume not found",
+			volumeID:  "vol-000000aa",
+			expErr:    awserr.New("InvalidVolume.NotFound", "", nil),
+			expExists: false,
+		},
+		{
+			name:      "success: no volumes returned",
+			volumeID:  "vol-000000aa",
+			expExists: false,
+		},
+		{
+			name:     "fail: DescribeVolumes returned generic error",
+			volumeID: "vol-000000aa",
+			expErr:   fmt.Errorf("DescribeVolumes generic error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			ctx := context.Background()
+			mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: tc.volumes}, tc.expErr)
+
+			exists, err := c.VolumeExists(ctx, tc.volumeID)
+			if isAWSErrorVolumeNotFound(tc.expErr) {
+				if err != nil {
+					t.Fatalf("VolumeExists() failed: expected no error, got: %v", err)
+				}
+			} else if tc.expErr != err {
+				t.Fatalf("VolumeExists() failed: expected error %v, got: %v", tc.expErr, err)
+			}
+			if tc.expExists != exists {
+				t.Fatalf("VolumeExists() failed: expected exists %v, got %v", tc.expExists, exists)
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestGetVolumeHealth(t *testing.T) {
+	notBefore := time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name      string
+		volumeID  string
+		statuses  []*ec2.VolumeStatusItem
+		awsErr    error
+		expHealth VolumeHealth
+		expErr    error
+	}{
+		{
+			name:     "success: volume ok",
+			volumeID: "vol-000000aa",
+			statuses: []*ec2.VolumeStatusItem{
+				{
+					VolumeId:     aws.String("vol-000000aa"),
+					VolumeStatus: &ec2.VolumeStatusInfo{Status: aws.String("ok")},
+				},
+			},
+			expHealth: VolumeHealth{VolumeID: "vol-000000aa", Status: "ok"},
+		},
+		{
+			name:     "success: volume impaired with an event",
+			volumeID: "vol-000000aa",
+			statuses: []*ec2.VolumeStatusItem{
+				{
+					VolumeId:     aws.String("vol-000000aa"),
+					VolumeStatus: &ec2.VolumeStatusInfo{Status: aws.String("impaired")},
+					Events: []*ec2.VolumeStatusEvent{
+						{
+							EventId:     aws.String("evol-000000ab"),
+							EventType:   aws.String("potential-data-inconsistency"),
+							Description: aws.String("IO disabled"),
+							NotBefore:   aws.Time(notBefore),
+						},
+					},
+				},
+			},
+			expHealth: VolumeHealth{
+				VolumeID: "vol-000000aa",
+				Status:   "impaired",
+				Events: []VolumeHealthEvent{
+					{
+						EventID:     "evol-000000ab",
+						EventType:   "potential-data-inconsistency",
+						Description: "IO disabled",
+						NotBefore:   notBefore,
+					},
+				},
+			},
+		},
+		{
+			name:     "fail: volume not found",
+			volumeID: "vol-000000aa",
+			awsErr:   awserr.New("InvalidVolume.NotFound", "", nil),
+			expErr:   ErrNotFound,
+		},
+		{
+			name:     "fail: no status returned",
+			volumeID: "vol-000000aa",
+			expErr:   ErrNotFound,
+		},
+		{
+			name:     "fail: DescribeVolumeStatus returned generic error",
+			volumeID: "vol-000000aa",
+			awsErr:   fmt.Errorf("DescribeVolumeStatus generic error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			ctx := context.Background()
+			mockEC2.EXPECT().DescribeVolumeStatusWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+				func(_ aws.Context, input *ec2.DescribeVolumeStatusInput, _ ...request.Option) (*ec2.DescribeVolumeStatusOutput, error) {
+					if len(input.VolumeIds) != 1 || aws.StringValue(input.VolumeIds[0]) != tc.volumeID {
+						t.Fatalf("unexpected DescribeVolumeStatus volume IDs: %v", aws.StringValueSlice(input.VolumeIds))
+					}
+					return &ec2.DescribeVolumeStatusOutput{VolumeStatuses: tc.statuses}, tc.awsErr
+				})
+
+			health, err := c.GetVolumeHealth(ctx, tc.volumeID)
+			switch {
+			case tc.expErr != nil:
+				if !errors.Is(err, tc.expErr) {
+					t.Fatalf("GetVolumeHealth() failed: expected error %v, got: %v", tc.expErr, err)
+				}
+			case tc.awsErr != nil:
+				if !errors.Is(err, tc.awsErr) {
+					t.Fatalf("GetVolumeHealth() failed: expected error wrapping %v, got: %v", tc.awsErr, err)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("GetVolumeHealth() failed: expected no error, got: %v", err)
+				}
+				if !reflect.DeepEqual(health, tc.expHealth) {
+					t.Fatalf("GetVolumeHealth() failed: expected health %+v, got %+v", tc.expHealth, health)
+				}
+				if health.Impaired() != (tc.expHealth.Status == "impaired") {
+					t.Fatalf("GetVolumeHealth() failed: expected impaired %v, got %v", tc.expHealth.Status == "impaired", health.Impaired())
+				}
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestDetectStuckVolumes(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockEC2 := mocks.NewMockEC2(mockCtrl)
+	c := newCloud(mockEC2)
+
+	now := time.Now()
+	volumes := []*ec2.Volume{
+		{VolumeId: aws.String("vol-000000a6"), State: aws.String("creating"), CreateTime: aws.Time(now.Add(-2 * time.Hour))},
+		{VolumeId: aws.String("vol-000000a5"), State: aws.String("creating"), CreateTime: aws.Time(now.Add(-1 * time.Minute))},
+	}
+
+	ctx := context.Background()
+	mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+		func(_ aws.Context, input *ec2.DescribeVolumesInput, _ ...request.Option) (*ec2.DescribeVolumesOutput, error) {
+			filters := map[string]string{}
+			for _, f := range input.Filters {
+				filters[aws.StringValue(f.Name)] = aws.StringValue(f.Values[0])
+			}
+			if filters["tag-key"] != VolumeNameTagKey || filters["status"] != "creating" {
+				t.Fatalf("unexpected DescribeVolumes filters: %v", filters)
+			}
+			return &ec2.DescribeVolumesOutput{Volumes: volumes}, nil
+		})
+
+	disks, err := c.DetectStuckVolumes(ctx, time.Hour)
+	if err != nil {
+		t.Fatalf("DetectStuckVolumes() failed: expected no error, got: %v", err)
+	}
+	if len(disks) != 1 || disks[0].VolumeID != "vol-000000a6" {
+		t.Fatalf("DetectStuckVolumes() failed: expected only vol-000000a6, got %+v", disks)
+	}
+
+	mockCtrl.Finish()
+}
+
+func TestResolveIOPS(t *testing.T) {
+	testCases := []struct {
+		name        string
+		volumeType  string
+		capacityGiB int64
+		diskOptions *DiskOptions
+		expIOPS     int64
+	}{
+		{
+			name:        "io1",
+			volumeType:  VolumeTypeIO1,
+			capacityGiB: 100,
+			diskOptions: &DiskOptions{IOPSPerGB: 10},
+			expIOPS:     1000,
+		},
+		{
+			name:        "io1 below the minimum",
+			volumeType:  VolumeTypeIO1,
+			capacityGiB: 4,
+			diskOptions: &DiskOptions{IOPSPerGB: 10},
+			expIOPS:     MinTotalIOPS,
+		},
+		{
+			name:        "io2 above the maximum",
+			volumeType:  VolumeTypeIO2,
+			capacityGiB: 1000,
+			diskOptions: &DiskOptions{IOPSPerGB: 500},
+			expIOPS:     MaxTotalIOPS,
+		},
+		{
+			name:        "io2 block express above the maximum",
+			volumeType:  VolumeTypeIO2,
+			capacityGiB: 1000,
+			diskOptions: &DiskOptions{IOPSPerGB: 500, BlockExpress: true},
+			expIOPS:     MaxIO2BlockExpressIOPS,
+		},
+		{
+			name:        "io1 block express ignored",
+			volumeType:  VolumeTypeIO1,
+			capacityGiB: 1000,
+			diskOptions: &DiskOptions{IOPSPerGB: 50, BlockExpress: true},
+			expIOPS:     MaxTotalIOPS,
+		},
+		{
+			name:        "gp2",
+			volumeType:  VolumeTypeGP2,
+			capacityGiB: 100,
+			diskOptions: &DiskOptions{IOPSPerGB: 10},
+		},
+		{
+			name:        "st2",
+			volumeType:  VolumeTypeST2,
+			capacityGiB: 100,
+			diskOptions: &DiskOptions{IOPSPerGB: 10},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if iops := resolveIOPS(tc.volumeType, tc.capacityGiB, tc.diskOptions); iops != tc.expIOPS {
+				t.Fatalf("resolveIOPS() failed: expected %d, got %d", tc.expIOPS, iops)
+			}
+		})
+	}
+}
+
+func TestCheckVolumeConfigDrift(t *testing.T) {
+	testCases := []struct {
+		name     string
+		volume   *ec2.Volume
+		desired  *DiskOptions
+		expDrift ConfigDrift
+	}{
+		{
+			name: "no drift",
+			volume: &ec2.Volume{
+				VolumeType: aws.String(VolumeTypeIO2),
+				Iops:       aws.Int64(500),
+				Size:       aws.Int64(10),
+			},
+			desired: &DiskOptions{VolumeType: VolumeTypeIO2, IOPSPerGB: 50, CapacityBytes: util.GiBToBytes(10)},
+			expDrift: ConfigDrift{
+				ActualVolumeType:  VolumeTypeIO2,
+				DesiredVolumeType: VolumeTypeIO2,
+				ActualIOPS:        500,
+				DesiredIOPS:       500,
+				ActualSizeGiB:     10,
+				DesiredSizeGiB:    10,
+			},
+		},
+		{
+			name: "IOPS drift",
+			volume: &ec2.Volume{
+				VolumeType: aws.String(VolumeTypeIO2),
+				Iops:       aws.Int64(200),
+				Size:       aws.Int64(10),
+			},
+			desired: &DiskOptions{VolumeType: VolumeTypeIO2, IOPSPerGB: 50, CapacityBytes: util.GiBToBytes(10)},
+			expDrift: ConfigDrift{
+				ActualVolumeType:  VolumeTypeIO2,
+				DesiredVolumeType: VolumeTypeIO2,
+				IOPSDrifted:       true,
+				ActualIOPS:        200,
+				DesiredIOPS:       500,
+				ActualSizeGiB:     10,
+				DesiredSizeGiB:    10,
+			},
+		},
+		{
+			name: "type and size drift",
+			volume: &ec2.Volume{
+				VolumeType: aws.String(VolumeTypeGP2),
+				Iops:       aws.Int64(100),
+				Size:       aws.Int64(10),
+			},
+			desired: &DiskOptions{VolumeType: "hdd", CapacityBytes: util.GiBToBytes(20)},
+			expDrift: ConfigDrift{
+				VolumeTypeDrifted: true,
+				ActualVolumeType:  VolumeTypeGP2,
+				DesiredVolumeType: VolumeTypeST2,
+				ActualIOPS:        100,
+				SizeDrifted:       true,
+				ActualSizeGiB:     10,
+				DesiredSizeGiB:    20,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			tc.volume.VolumeId = aws.String("vol-000000a8")
+			ctx := context.Background()
+			mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{tc.volume}}, nil)
+
+			drift, err := c.CheckVolumeConfigDrift(ctx, "vol-000000a8", tc.desired)
+			if err != nil {
+				t.Fatalf("CheckVolumeConfigDrift() failed: expected no error, got: %v", err)
+			}
+			if !reflect.DeepEqual(*drift, tc.expDrift) {
+				t.Fatalf("CheckVolumeConfigDrift() failed: expected drift %+v, got %+v", tc.expDrift, *drift)
+			}
+			if drifted := tc.expDrift.VolumeTypeDrifted || tc.expDrift.IOPSDrifted || tc.expDrift.SizeDrifted; drift.Drifted() != drifted {
+				t.Fatalf("CheckVolumeConfigDrift() failed: expected drifted %t, got %t", drifted, drift.Drifted())
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestFindVolumesAttachedToDeadNodes(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockEC2 := mocks.NewMockEC2(mockCtrl)
+	c := newCloud(mockEC2)
+
+	attachment := func(nodeID string) *ec2.VolumeAttachment {
+		return &ec2.VolumeAttachment{InstanceId: aws.String(nodeID), State: aws.String("attached")}
+	}
+	volumes := []*ec2.Volume{
+		{VolumeId: aws.String("vol-000000a2"), State: aws.String("in-use"), Attachments: []*ec2.VolumeAttachment{attachment("node-live")}},
+		{VolumeId: aws.String("vol-dead"), State: aws.String("in-use"), Attachments: []*ec2.VolumeAttachment{attachment("node-dead")}},
+		{VolumeId: aws.String("vol-000000a3"), State: aws.String("in-use"), Attachments: []*ec2.VolumeAttachment{attachment("node-live"), attachment("node-dead")}},
+	}
+
+	ctx := context.Background()
+	mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+		func(_ aws.Context, input *ec2.DescribeVolumesInput, _ ...request.Option) (*ec2.DescribeVolumesOutput, error) {
+			filters := map[string]string{}
+			for _, f := range input.Filters {
+				filters[aws.StringValue(f.Name)] = aws.StringValue(f.Values[0])
+			}
+			if filters["tag-key"] != VolumeNameTagKey || filters["status"] != "in-use" {
+				t.Fatalf("unexpected DescribeVolumes filters: %v", filters)
+			}
+			return &ec2.DescribeVolumesOutput{Volumes: volumes}, nil
+		})
+
+	disks, err := c.FindVolumesAttachedToDeadNodes(ctx, map[string]bool{"node-live": true})
+	if err != nil {
+		t.Fatalf("FindVolumesAttachedToDeadNodes() failed: expected no error, got: %v", err)
+	}
+	volumeIDs := []string{}
+	for _, disk := range disks {
+		volumeIDs = append(volumeIDs, disk.VolumeID)
+	}
+	if expVolumeIDs := []string{"vol-dead", "vol-000000a3"}; !reflect.DeepEqual(volumeIDs, expVolumeIDs) {
+		t.Fatalf("FindVolumesAttachedToDeadNodes() failed: expected volumes %v, got %v", expVolumeIDs, volumeIDs)
+	}
+
+	mockCtrl.Finish()
+}
+
+func TestListVolumes(t *testing.T) {
+	testCases := []struct {
+		name         string
+		maxResults   int64
+		nextToken    string
+		volumes      []*ec2.Volume
+		respToken    *string
+		describeErr  error
+		expDescribe  bool
+		expVolumeIDs []string
+		expErr       error
+	}{
+		{
+			name: "success: all volumes",
+			volumes: []*ec2.Volume{
+				{VolumeId: aws.String("vol-000000b1")},
+				{VolumeId: aws.String("vol-000000b2")},
+			},
+			expDescribe:  true,
+			expVolumeIDs: []string{"vol-000000b1", "vol-000000b2"},
+		},
+		{
+			name:       "success: first page",
+			maxResults: 5,
+			volumes: []*ec2.Volume{
+				{VolumeId: aws.String("vol-000000b1")},
+			},
+			respToken:    aws.String("token-2"),
+			expDescribe:  true,
+			expVolumeIDs: []string{"vol-000000b1"},
+		},
+		{
+			name:       "success: last page",
+			maxResults: 5,
+			nextToken:  "token-2",
+			volumes: []*ec2.Volume{
+				{VolumeId: aws.String("vol-000000b2")},
+			},
+			expDescribe:  true,
+			expVolumeIDs: []string{"vol-000000b2"},
+		},
+		{
+			name:         "success: no volumes",
+			expDescribe:  true,
+			expVolumeIDs: []string{},
+		},
+		{
+			name:       "fail: invalid max results",
+			maxResults: 4,
+			expErr:     ErrInvalidMaxResults,
+		},
+		{
+			name:        "fail: DescribeVolumes returned generic error",
+			describeErr: fmt.Errorf("DescribeVolumes generic error"),
+			expDescribe: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			ctx := context.Background()
+			if tc.expDescribe {
+				mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+					func(_ aws.Context, input *ec2.DescribeVolumesInput, _ ...request.Option) (*ec2.DescribeVolumesOutput, error) {
+						if len(input.Filters) != 1 || aws.StringValue(input.Filters[0].Name) != "tag-key" || aws.StringValue(input.Filters[0].Values[0]) != VolumeNameTagKey {
+							t.Fatalf("unexpected DescribeVolumes filters: %v", input.Filters)
+						}
+						if tc.maxResults > 0 && aws.Int64Value(input.MaxResults) != tc.maxResults {
+							t.Fatalf("expected DescribeVolumes max results %d, got %d", tc.maxResults, aws.Int64Value(input.MaxResults))
+						}
+						if aws.StringValue(input.NextToken) != tc.nextToken {
+							t.Fatalf("expected DescribeVolumes next token %q, got %q", tc.nextToken, aws.StringValue(input.NextToken))
+						}
+						return &ec2.DescribeVolumesOutput{Volumes: tc.volumes, NextToken: tc.respToken}, tc.describeErr
+					})
+			}
+
+			resp, err := c.ListVolumes(ctx, tc.maxResults, tc.nextToken)
+			switch {
+			case tc.expErr != nil:
+				if err != tc.expErr {
+					t.Fatalf("ListVolumes() failed: expected error %v, got: %v", tc.expErr, err)
+				}
+			case tc.describeErr != nil:
+				if !errors.Is(err, tc.describeErr) {
+					t.Fatalf("ListVolumes() failed: expected error wrapping %v, got: %v", tc.describeErr, err)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("ListVolumes() failed: expected no error, got: %v", err)
+				}
+				volumeIDs := []string{}
+				for _, disk := range resp.Disks {
+					volumeIDs = append(volumeIDs, disk.VolumeID)
+				}
+				if !reflect.DeepEqual(volumeIDs, tc.expVolumeIDs) {
+					t.Fatalf("ListVolumes() failed: expected volumes %v, got %v", tc.expVolumeIDs, volumeIDs)
+				}
+				if resp.NextToken != aws.StringValue(tc.respToken) {
+					t.Fatalf("ListVolumes() failed: expected next token %q, got %q", aws.StringValue(tc.respToken), resp.NextToken)
+				}
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestListDisksByTag(t *testing.T) {
+	testCases := []struct {
+		name       string
+		tagValue   string
+		expFilters map[string]string
+	}{
+		{
+			name:       "tag value",
+			tagValue:   "cluster-a",
+			expFilters: map[string]string{"tag:cluster": "cluster-a"},
+		},
+		{
+			name:       "any tag value",
+			expFilters: map[string]string{"tag-key": "cluster"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			ctx := context.Background()
+			pages := [][]*ec2.Volume{
+				{{VolumeId: aws.String("vol-1")}, {VolumeId: aws.String("vol-2")}},
+				{{VolumeId: aws.String("vol-3")}},
+			}
+			gomock.InOrder(
+				mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+					func(_ aws.Context, input *ec2.DescribeVolumesInput, _ ...request.Option) (*ec2.DescribeVolumesOutput, error) {
+						filters := map[string]string{}
+						for _, f := range input.Filters {
+							filters[aws.StringValue(f.Name)] = aws.StringValue(f.Values[0])
+						}
+						if !reflect.DeepEqual(filters, tc.expFilters) {
+							t.Fatalf("unexpected DescribeVolumes filters: %v", filters)
+						}
+						return &ec2.DescribeVolumesOutput{Volumes: pages[0], NextToken: aws.String("token")}, nil
+					}),
+				mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: pages[1]}, nil),
+			)
+
+			disks, err := c.ListDisksByTag(ctx, "cluster", tc.tagValue)
+			if err != nil {
+				t.Fatalf("ListDisksByTag() failed: expected no error, got: %v", err)
+			}
+			volumeIDs := []string{}
+			for _, disk := range disks {
+				volumeIDs = append(volumeIDs, disk.VolumeID)
+			}
+			if expVolumeIDs := []string{"vol-1", "vol-2", "vol-3"}; !reflect.DeepEqual(volumeIDs, expVolumeIDs) {
+				t.Fatalf("ListDisksByTag() failed: expected volumes %v, got %v", expVolumeIDs, volumeIDs)
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestListOrphanedDisks(t *testing.T) {
+	attachment := func(nodeID string) *ec2.VolumeAttachment {
+		return &ec2.VolumeAttachment{InstanceId: aws.String(nodeID), State: aws.String("attached")}
+	}
+	volumes := []*ec2.Volume{
+		{VolumeId: aws.String("vol-000000a2"), Attachments: []*ec2.VolumeAttachment{attachment("node-live")}},
+		{VolumeId: aws.String("vol-000000a1"), Attachments: []*ec2.VolumeAttachment{attachment("node-gone")}},
+		{VolumeId: aws.String("vol-000000a3"), Attachments: []*ec2.VolumeAttachment{attachment("node-live"), attachment("node-gone")}},
+	}
+
+	testCases := []struct {
+		name         string
+		describeErr  error
+		expVolumeIDs []string
+		expErr       bool
+	}{
+		{
+			name:         "success: volumes attached to missing instances",
+			expVolumeIDs: []string{"vol-000000a1", "vol-000000a3"},
+		},
+		{
+			name:        "fail: instance lookup error",
+			describeErr: errors.New("DescribeInstances generic error"),
+			expErr:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			ctx := context.Background()
+			mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+				func(_ aws.Context, input *ec2.DescribeVolumesInput, _ ...request.Option) (*ec2.DescribeVolumesOutput, error) {
+					filters := map[string]string{}
+					for _, f := range input.Filters {
+						filters[aws.StringValue(f.Name)] = aws.StringValue(f.Values[0])
+					}
+					if filters["tag-key"] != VolumeNameTagKey || filters["tag:cluster"] != "cluster-a" {
+						t.Fatalf("unexpected DescribeVolumes filters: %v", filters)
+					}
+					return &ec2.DescribeVolumesOutput{Volumes: volumes}, nil
+				})
+			// Each instance is described once
+			mockEC2.EXPECT().DescribeInstancesWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+				func(_ aws.Context, input *ec2.DescribeInstancesInput, _ ...request.Option) (*ec2.DescribeInstancesOutput, error) {
+					if tc.describeErr != nil {
+						return nil, tc.describeErr
+					}
+					nodeID := aws.StringValue(input.InstanceIds[0])
+					if nodeID == "node-gone" {
+						return nil, awserr.New("InvalidInstanceID.NotFound", "", nil)
+					}
+					return newDescribeInstancesOutput(nodeID), nil
+				}).MaxTimes(2)
+
+			disks, err := c.ListOrphanedDisks(ctx, "cluster", "cluster-a")
+			if tc.expErr {
+				if err == nil {
+					t.Fatal("ListOrphanedDisks() failed: expected error, got nothing")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListOrphanedDisks() failed: expected no error, got: %v", err)
+			}
+			volumeIDs := []string{}
+			for _, disk := range disks {
+				volumeIDs = append(volumeIDs, disk.VolumeID)
+			}
+			if !reflect.DeepEqual(volumeIDs, tc.expVolumeIDs) {
+				t.Fatalf("ListOrphanedDisks() failed: expected volumes %v, got %v", tc.expVolumeIDs, volumeIDs)
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestGetInstanceType(t *testing.T) {
+	nodeID := "node-1234"
+
+	testCases := []struct {
+		name            string
+		instances       []*ec2.Instance
+		describeErr     error
+		expInstanceType string
+		expErr          error
 	}{
 		{
-			name:     "success: normal",
-			volumeID: "vol-test-1234",
-			expResp:  true,
-			expErr:   nil,
+			name:            "success: normal",
+			instances:       []*ec2.Instance{{InstanceId: aws.String(nodeID), InstanceType: aws.String("m5.large")}},
+			expInstanceType: "m5.large",
 		},
 		{
-			name:     "fail: DeleteVolume returned generic error",
-			volumeID: "vol-test-1234",
-			expResp:  false,
-			expErr:   fmt.Errorf("DeleteVolume generic error"),
+			name:        "fail: instance not found",
+			describeErr: awserr.New("InvalidInstanceID.NotFound", "", nil),
+			expErr:      ErrNotFound,
 		},
 		{
-			name:     "fail: DeleteVolume returned not found error",
-			volumeID: "vol-test-1234",
-			expResp:  false,
-			expErr:   awserr.New("InvalidVolume.NotFound", "", nil),
+			name:   "fail: no instance returned",
+			expErr: ErrNotFound,
+		},
+		{
+			name:        "fail: DescribeInstances returned generic error",
+			describeErr: fmt.Errorf("DescribeInstances generic error"),
+			expErr:      fmt.Errorf("DescribeInstances generic error"),
 		},
 	}
 
@@ -245,19 +3841,24 @@ func TestDeleteDisk(t *testing.T) {
 			c := newCloud(mockEC2)
 
 			ctx := context.Background()
-			mockEC2.EXPECT().DeleteVolumeWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DeleteVolumeOutput{}, tc.expErr)
-
-			ok, err := c.DeleteDisk(ctx, tc.volumeID)
-			if err != nil && tc.expErr == nil {
-				t.Fatalf("DeleteDisk() failed: expected no error, got: %v", err)
-			}
-
-			if err == nil && tc.expErr != nil {
-				t.Fatal("DeleteDisk() failed: expected error, got nothing")
-			}
+			output := &ec2.DescribeInstancesOutput{Reservations: []*ec2.Reservation{{Instances: tc.instances}}}
+			mockEC2.EXPECT().DescribeInstancesWithContext(gomock.Eq(ctx), gomock.Any()).Return(output, tc.describeErr)
 
-			if tc.expResp != ok {
-				t.Fatalf("DeleteDisk() failed: expected return %v, got %v", tc.expResp, ok)
+			instanceType, err := c.GetInstanceType(ctx, nodeID)
+			if err != nil {
+				if tc.expErr == nil {
+					t.Fatalf("GetInstanceType() failed: expected no error, got: %v", err)
+				}
+				if tc.expErr == ErrNotFound && err != ErrNotFound {
+					t.Fatalf("GetInstanceType() failed: expected error %v, got: %v", ErrNotFound, err)
+				}
+			} else {
+				if tc.expErr != nil {
+					t.Fatal("GetInstanceType() failed: expected error, got nothing")
+				}
+				if instanceType != tc.expInstanceType {
+					t.Fatalf("GetInstanceType() failed: expected %q, got %q", tc.expInstanceType, instanceType)
+				}
 			}
 
 			mockCtrl.Finish()
@@ -265,26 +3866,41 @@ func TestDeleteDisk(t *testing.T) {
 	}
 }
 
-func TestAttachDisk(t *testing.T) {
-    t.Skip("Skipping temporarily due to interface inconsistency.")
+func TestGetInstanceClusterTag(t *testing.T) {
+	nodeID := "node-1234"
 
 	testCases := []struct {
-		name     string
-		volumeID string
-		nodeID   string
-		expErr   error
+		name       string
+		tagKey     string
+		tagValue   string
+		instances  []*ec2.Instance
+		expFilters map[string]string
+		expErr     error
 	}{
 		{
-			name:     "success: normal",
-			volumeID: "vol-test-1234",
-			nodeID:   "node-1234",
-			expErr:   nil,
+			name:       "success: no cluster tag",
+			instances:  []*ec2.Instance{{InstanceId: aws.String(nodeID), InstanceType: aws.String("m5.large")}},
+			expFilters: map[string]string{},
 		},
 		{
-			name:     "fail: AttachVolume returned generic error",
-			volumeID: "vol-test-1234",
-			nodeID:   "node-1234",
-			expErr:   fmt.Errorf(""),
+			name:       "success: instance with the cluster tag",
+			tagKey:     "kubernetes.io/cluster/demo",
+			tagValue:   "owned",
+			instances:  []*ec2.Instance{{InstanceId: aws.String(nodeID), InstanceType: aws.String("m5.large")}},
+			expFilters: map[string]string{"tag:kubernetes.io/cluster/demo": "owned"},
+		},
+		{
+			name:       "success: instance with the cluster tag key",
+			tagKey:     "kubernetes.io/cluster/demo",
+			instances:  []*ec2.Instance{{InstanceId: aws.String(nodeID), InstanceType: aws.String("m5.large")}},
+			expFilters: map[string]string{"tag-key": "kubernetes.io/cluster/demo"},
+		},
+		{
+			name:       "fail: instance without the cluster tag",
+			tagKey:     "kubernetes.io/cluster/demo",
+			tagValue:   "owned",
+			expFilters: map[string]string{"tag:kubernetes.io/cluster/demo": "owned"},
+			expErr:     ErrNotFound,
 		},
 	}
 
@@ -293,28 +3909,116 @@ func TestAttachDisk(t *testing.T) {
 			mockCtrl := gomock.NewController(t)
 			mockEC2 := mocks.NewMockEC2(mockCtrl)
 			c := newCloud(mockEC2)
+			c.(*cloud).options.instanceClusterTagKey = tc.tagKey
+			c.(*cloud).options.instanceClusterTagValue = tc.tagValue
 
-			vol := &ec2.Volume{
-				VolumeId:    aws.String(tc.volumeID),
-				Attachments: []*ec2.VolumeAttachment{{State: aws.String("attached")}},
+			ctx := context.Background()
+			mockEC2.EXPECT().DescribeInstancesWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+				func(_ aws.Context, input *ec2.DescribeInstancesInput, _ ...request.Option) (*ec2.DescribeInstancesOutput, error) {
+					filters := map[string]string{}
+					for _, f := range input.Filters {
+						filters[aws.StringValue(f.Name)] = aws.StringValue(f.Values[0])
+					}
+					if !reflect.DeepEqual(filters, tc.expFilters) {
+						t.Fatalf("unexpected DescribeInstances filters: expected %v, got %v", tc.expFilters, filters)
+					}
+					return &ec2.DescribeInstancesOutput{Reservations: []*ec2.Reservation{{Instances: tc.instances}}}, nil
+				})
+
+			_, err := c.GetInstanceType(ctx, nodeID)
+			if err != tc.expErr {
+				t.Fatalf("GetInstanceType() failed: expected error %v, got: %v", tc.expErr, err)
 			}
 
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestGetInstanceTypeInfo(t *testing.T) {
+	testCases := []struct {
+		name         string
+		instanceType string
+		response     *ec2.DescribeInstanceTypesOutput
+		describeErr  error
+		expInfo      *InstanceTypeInfo
+		expErr       error
+	}{
+		{
+			name:         "success: normal",
+			instanceType: "m5.large",
+			response: &ec2.DescribeInstanceTypesOutput{
+				InstanceTypes: []*ec2.InstanceTypeInfo{
+					{
+						InstanceType: aws.String("m5.large"),
+						Hypervisor:   aws.String("nitro"),
+						NetworkInfo:  &ec2.NetworkInfo{MaximumNetworkInterfaces: aws.Int64(3)},
+						EbsInfo: &ec2.EbsInfo{
+							EbsOptimizedSupport: aws.String("default"),
+							NvmeSupport:         aws.String("required"),
+							EbsOptimizedInfo: &ec2.EbsOptimizedInfo{
+								MaximumIops:             aws.Int64(18750),
+								MaximumBandwidthInMbps:  aws.Int64(4750),
+								MaximumThroughputInMBps: aws.Float64(593.75),
+							},
+						},
+					},
+				},
+			},
+			expInfo: &InstanceTypeInfo{
+				InstanceType:         "m5.large",
+				Hypervisor:           "nitro",
+				MaxNetworkInterfaces: 3,
+				EBSOptimizedSupport:  "default",
+				NVMeSupport:          "required",
+				MaxIOPS:              18750,
+				MaxBandwidthInMbps:   4750,
+				MaxThroughputInMBps:  593.75,
+			},
+		},
+		{
+			name:         "fail: invalid instance type",
+			instanceType: "m0.nano",
+			describeErr:  awserr.New("InvalidInstanceType", "", nil),
+			expErr:       ErrNotFound,
+		},
+		{
+			name:         "fail: DescribeInstanceTypes returned generic error",
+			instanceType: "m5.large",
+			describeErr:  fmt.Errorf("DescribeInstanceTypes generic error"),
+			expErr:       fmt.Errorf("DescribeInstanceTypes generic error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
 			ctx := context.Background()
-			mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{vol}}, nil).AnyTimes()
-			mockEC2.EXPECT().DescribeInstancesWithContext(gomock.Eq(ctx), gomock.Any()).Return(newDescribeInstancesOutput(tc.nodeID), nil)
-			mockEC2.EXPECT().AttachVolumeWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.VolumeAttachment{}, tc.expErr)
+			// Successful lookups are cached, so EC2 is only called once
+			mockEC2.EXPECT().DescribeInstanceTypesWithContext(gomock.Eq(ctx), gomock.Any()).Return(tc.response, tc.describeErr).Times(1)
 
-			devicePath, err := c.AttachDisk(ctx, tc.volumeID, tc.nodeID)
+			info, err := c.GetInstanceTypeInfo(ctx, tc.instanceType)
 			if err != nil {
 				if tc.expErr == nil {
-					t.Fatalf("AttachDisk() failed: expected no error, got: %v", err)
+					t.Fatalf("GetInstanceTypeInfo() failed: expected no error, got: %v", err)
+				}
+				if tc.expErr == ErrNotFound && err != ErrNotFound {
+					t.Fatalf("GetInstanceTypeInfo() failed: expected error %v, got: %v", ErrNotFound, err)
 				}
 			} else {
 				if tc.expErr != nil {
-					t.Fatal("AttachDisk() failed: expected error, got nothing")
+					t.Fatal("GetInstanceTypeInfo() failed: expected error, got nothing")
 				}
-				if !strings.HasPrefix(devicePath, "/dev/") {
-					t.Fatal("AttachDisk() failed: expected valid device path, got empty string")
+				if !reflect.DeepEqual(info, tc.expInfo) {
+					t.Fatalf("GetInstanceTypeInfo() failed: expected %+v, got %+v", tc.expInfo, info)
+				}
+
+				cached, err := c.GetInstanceTypeInfo(ctx, tc.instanceType)
+				if err != nil || cached != info {
+					t.Fatalf("GetInstanceTypeInfo() failed: expected cached info, got %+v, %v", cached, err)
 				}
 			}
 
@@ -323,24 +4027,26 @@ func TestAttachDisk(t *testing.T) {
 	}
 }
 
-func TestDetachDisk(t *testing.T) {
+func TestCreateSnapshot(t *testing.T) {
 	testCases := []struct {
-		name     string
-		volumeID string
-		nodeID   string
-		expErr   error
+		name            string
+		snapshotName    string
+		snapshotOptions *SnapshotOptions
+		expSnapshot     *Snapshot
+		expErr          error
 	}{
 		{
-			name:     "success: normal",
-			volumeID: "vol-test-1234",
-			nodeID:   "node-1234",
-			expErr:   nil,
-		},
-		{
-			name:     "fail: DetachVolume returned generic error",
-			volumeID: "vol-test-1234",
-			nodeID:   "node-1234",
-			expErr:   fmt.Errorf("DetachVolume generic error"),
+			name:         "success: normal",
+			snapshotName: "snap-000000ac",
+			snapshotOptions: &SnapshotOptions{
+				Tags: map[string]string{
+					SnapshotNameTagKey: "snap-000000ac",
+				},
+			},
+			expSnapshot: &Snapshot{
+				SourceVolumeID: "snap-000000af",
+			},
+			expErr: nil,
 		},
 	}
 
@@ -350,24 +4056,28 @@ func TestDetachDisk(t *testing.T) {
 			mockEC2 := mocks.NewMockEC2(mockCtrl)
 			c := newCloud(mockEC2)
 
-			vol := &ec2.Volume{
-				VolumeId:    aws.String(tc.volumeID),
-				Attachments: nil,
+			ec2snapshot := &ec2.Snapshot{
+				SnapshotId: aws.String(tc.snapshotOptions.Tags[SnapshotNameTagKey]),
+				VolumeId:   aws.String("snap-000000af"),
+				State:      aws.String("completed"),
 			}
 
 			ctx := context.Background()
-			mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{vol}}, nil).AnyTimes()
-			mockEC2.EXPECT().DescribeInstancesWithContext(gomock.Eq(ctx), gomock.Any()).Return(newDescribeInstancesOutput(tc.nodeID), nil)
-			mockEC2.EXPECT().DetachVolumeWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.VolumeAttachment{}, tc.expErr)
+			mockEC2.EXPECT().CreateSnapshotWithContext(gomock.Eq(ctx), gomock.Any()).Return(ec2snapshot, tc.expErr)
+			mockEC2.EXPECT().DescribeSnapshotsWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeSnapshotsOutput{Snapshots: []*ec2.Snapshot{ec2snapshot}}, nil).AnyTimes()
 
-			err := c.DetachDisk(ctx, tc.volumeID, tc.nodeID)
+			snapshot, err := c.CreateSnapshot(ctx, tc.expSnapshot.SourceVolumeID, tc.snapshotOptions)
 			if err != nil {
 				if tc.expErr == nil {
-					t.Fatalf("DetachDisk() failed: expected no error, got: %v", err)
+					t.Fatalf("CreateSnapshot() failed: expected no error, got: %v", err)
 				}
 			} else {
 				if tc.expErr != nil {
-					t.Fatal("DetachDisk() failed: expected error, got nothing")
+					t.Fatal("CreateSnapshot() failed: expected error, got nothing")
+				} else {
+					if snapshot.SourceVolumeID != tc.expSnapshot.SourceVolumeID {
+						t.Fatalf("CreateSnapshot() failed: expected source volume ID %s, got %v", tc.expSnapshot.SourceVolumeID, snapshot.SourceVolumeID)
+					}
 				}
 			}
 
@@ -376,26 +4086,282 @@ func TestDetachDisk(t *testing.T) {
 	}
 }
 
-func TestGetDiskByName(t *testing.T) {
+func TestCreateSnapshotTags(t *testing.T) {
 	testCases := []struct {
-		name             string
-		volumeName       string
-		volumeCapacity   int64
-		availabilityZone string
-		expErr           error
+		name              string
+		tags              map[string]string
+		createSnapshotErr error
+		expCreateSnapshot bool
+		expErrContains    string
+	}{
+		{
+			name: "fail: oversized tag value",
+			tags: map[string]string{
+				SnapshotNameTagKey: "snap-000000ac",
+				"owner":            strings.Repeat("a", MaxTagValueLength+1),
+			},
+			expErrContains: `"owner"`,
+		},
+		{
+			name: "fail: reserved tag key prefix",
+			tags: map[string]string{
+				SnapshotNameTagKey: "snap-000000ac",
+				"aws:owner":        "team",
+			},
+			expErrContains: `"aws:owner"`,
+		},
+		{
+			name: "fail: tag rejected by EC2",
+			tags: map[string]string{
+				SnapshotNameTagKey: "snap-000000ac",
+				"owner":            "team",
+			},
+			createSnapshotErr: awserr.New("InvalidParameterValue", "Tag key 'owner' is not allowed", nil),
+			expCreateSnapshot: true,
+			expErrContains:    `tag "owner" was rejected`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			ctx := context.Background()
+			if tc.expCreateSnapshot {
+				mockEC2.EXPECT().CreateSnapshotWithContext(gomock.Eq(ctx), gomock.Any()).Return(nil, tc.createSnapshotErr)
+			}
+
+			_, err := c.CreateSnapshot(ctx, "snap-000000af", &SnapshotOptions{Tags: tc.tags})
+			if err == nil {
+				t.Fatal("CreateSnapshot() failed: expected error, got nothing")
+			}
+			if !strings.Contains(err.Error(), tc.expErrContains) {
+				t.Fatalf("CreateSnapshot() failed: expected error containing %s, got: %v", tc.expErrContains, err)
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestCreateSnapshotSourceRegion(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockEC2 := mocks.NewMockEC2(mockCtrl)
+	c := newCloud(mockEC2)
+
+	tags := map[string]string{SnapshotNameTagKey: "snap-000000ac"}
+	ctx := context.Background()
+	mockEC2.EXPECT().CreateSnapshotWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+		func(_ aws.Context, input *ec2.CreateSnapshotInput, _ ...request.Option) (*ec2.Snapshot, error) {
+			return &ec2.Snapshot{
+				SnapshotId: aws.String("snap-000000ac"),
+				VolumeId:   input.VolumeId,
+				State:      aws.String("pending"),
+				Tags:       input.TagSpecifications[0].Tags,
+			}, nil
+		})
+
+	snapshot, err := c.CreateSnapshot(ctx, "snap-000000af", &SnapshotOptions{Tags: tags, SourceRegion: "us-west-2"})
+	if err != nil {
+		t.Fatalf("CreateSnapshot() failed: expected no error, got: %v", err)
+	}
+	if snapshot.SourceRegion != "us-west-2" {
+		t.Fatalf("CreateSnapshot() failed: expected source region %q, got %q", "us-west-2", snapshot.SourceRegion)
+	}
+	if _, ok := tags[SnapshotSourceRegionTagKey]; ok {
+		t.Fatal("CreateSnapshot() failed: the tags of the options were modified")
+	}
+}
+
+func TestCreateSnapshotDistinctTags(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockEC2 := mocks.NewMockEC2(mockCtrl)
+	c := newCloud(mockEC2)
+
+	tags := map[string]string{
+		SnapshotNameTagKey: "snap-000000ac",
+		"owner":            "team",
+		"cost-center":      "storage",
+	}
+	ctx := context.Background()
+	mockEC2.EXPECT().CreateSnapshotWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+		func(_ aws.Context, input *ec2.CreateSnapshotInput, _ ...request.Option) (*ec2.Snapshot, error) {
+			if len(input.TagSpecifications) != 1 {
+				t.Fatalf("CreateSnapshot() failed: expected 1 tag specification, got %d", len(input.TagSpecifications))
+			}
+			sent := map[string]string{}
+			for _, tag := range input.TagSpecifications[0].Tags {
+				sent[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+			}
+			if !reflect.DeepEqual(sent, tags) {
+				t.Fatalf("CreateSnapshot() failed: expected tags %v, got %v", tags, sent)
+			}
+			return &ec2.Snapshot{
+				SnapshotId: aws.String("snap-000000ac"),
+				VolumeId:   input.VolumeId,
+				State:      aws.String("pending"),
+				Tags:       input.TagSpecifications[0].Tags,
+			}, nil
+		})
+
+	if _, err := c.CreateSnapshot(ctx, "vol-000000af", &SnapshotOptions{Tags: tags}); err != nil {
+		t.Fatalf("CreateSnapshot() failed: expected no error, got: %v", err)
+	}
+}
+
+func TestSnapshotProgress(t *testing.T) {
+	testCases := []struct {
+		name        string
+		progress    *string
+		expProgress int
 	}{
 		{
-			name:             "success: normal",
-			volumeName:       "vol-test-1234",
-			volumeCapacity:   util.GiBToBytes(1),
-			availabilityZone: expZone,
-			expErr:           nil,
+			name:        "percentage",
+			progress:    aws.String("43%"),
+			expProgress: 43,
+		},
+		{
+			name:        "completed",
+			progress:    aws.String("100%"),
+			expProgress: 100,
+		},
+		{
+			name: "nil",
+		},
+		{
+			name:     "empty",
+			progress: aws.String(""),
+		},
+		{
+			name:     "not a percentage",
+			progress: aws.String("pending"),
+		},
+		{
+			name:     "no percent sign",
+			progress: aws.String("43"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if progress := snapshotProgress(&ec2.Snapshot{Progress: tc.progress}); progress != tc.expProgress {
+				t.Fatalf("snapshotProgress() failed: expected %d, got %d", tc.expProgress, progress)
+			}
+		})
+	}
+}
+
+func TestSnapshotIsError(t *testing.T) {
+	testCases := []struct {
+		state      string
+		expIsError bool
+		expReady   bool
+	}{
+		{state: "pending"},
+		{state: "completed", expReady: true},
+		{state: "recovering"},
+		{state: "recoverable"},
+		{state: "error", expIsError: true},
+		{state: "recoverable-error", expIsError: true},
+	}
+
+	c := &cloud{}
+	for _, tc := range testCases {
+		t.Run(tc.state, func(t *testing.T) {
+			snapshot := c.ec2SnapshotResponseToStruct(&ec2.Snapshot{State: aws.String(tc.state)})
+			if snapshot.State != tc.state {
+				t.Fatalf("ec2SnapshotResponseToStruct() failed: expected state %q, got %q", tc.state, snapshot.State)
+			}
+			if snapshot.ReadyToUse != tc.expReady {
+				t.Fatalf("ec2SnapshotResponseToStruct() failed: expected ReadyToUse %t, got %t", tc.expReady, snapshot.ReadyToUse)
+			}
+			if isError := snapshot.IsError(); isError != tc.expIsError {
+				t.Fatalf("IsError() failed: expected %t, got %t", tc.expIsError, isError)
+			}
+		})
+	}
+}
+
+func TestSnapshotSourceRegion(t *testing.T) {
+	testCases := []struct {
+		name            string
+		ec2Snapshot     *ec2.Snapshot
+		expSourceRegion string
+	}{
+		{
+			name: "tagged",
+			ec2Snapshot: &ec2.Snapshot{
+				Tags:        []*ec2.Tag{{Key: aws.String(SnapshotSourceRegionTagKey), Value: aws.String("eu-west-1")}},
+				Description: aws.String("[Copied snap-0123456789abcdef0 from us-east-1]"),
+			},
+			expSourceRegion: "eu-west-1",
+		},
+		{
+			name: "copied",
+			ec2Snapshot: &ec2.Snapshot{
+				Description: aws.String("[Copied snap-0123456789abcdef0 from us-east-1]"),
+			},
+			expSourceRegion: "us-east-1",
+		},
+		{
+			name: "unknown",
+			ec2Snapshot: &ec2.Snapshot{
+				Description: aws.String("Created by AWS EBS CSI driver for volume vol-000000a8"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if sourceRegion := snapshotSourceRegion(tc.ec2Snapshot); sourceRegion != tc.expSourceRegion {
+				t.Fatalf("snapshotSourceRegion() failed: expected %q, got %q", tc.expSourceRegion, sourceRegion)
+			}
+		})
+	}
+}
+
+func TestCreateEncryptedSnapshot(t *testing.T) {
+	testCases := []struct {
+		name          string
+		kmsKeyID      string
+		copyErr       error
+		copyState     string
+		deleteErr     error
+		expDeleteCopy bool
+		expErr        bool
+	}{
+		{
+			name:     "success: with KMS key",
+			kmsKeyID: "arn:aws:kms:us-east-1:000000000000:key/test",
+		},
+		{
+			name: "success: default KMS key",
+		},
+		{
+			name:      "success: intermediate snapshot not deleted",
+			deleteErr: errors.New("DeleteSnapshot failed"),
+		},
+		{
+			name:    "fail: CopySnapshot failed",
+			copyErr: errors.New("CopySnapshot failed"),
+			expErr:  true,
+		},
+		{
+			name:          "fail: copy failed",
+			copyState:     "error",
+			expDeleteCopy: true,
+			expErr:        true,
 		},
 		{
-			name:           "fail: DescribeVolumes returned generic error",
-			volumeName:     "vol-test-1234",
-			volumeCapacity: util.GiBToBytes(1),
-			expErr:         fmt.Errorf("DescribeVolumes generic error"),
+			name:          "fail: copy failed and not deleted",
+			copyState:     "error",
+			deleteErr:     errors.New("DeleteSnapshot failed"),
+			expDeleteCopy: true,
+			expErr:        true,
 		},
 	}
 
@@ -405,29 +4371,95 @@ func TestGetDiskByName(t *testing.T) {
 			mockEC2 := mocks.NewMockEC2(mockCtrl)
 			c := newCloud(mockEC2)
 
-			vol := &ec2.Volume{
-				VolumeId:         aws.String(tc.volumeName),
-				Size:             aws.Int64(util.BytesToGiB(tc.volumeCapacity)),
-				AvailabilityZone: aws.String(tc.availabilityZone),
-			}
+			volumeID := "vol-000000a8"
+			sourceID := "snap-000000a8"
+			copyID := "snap-000000a1"
 
-			ctx := context.Background()
-			mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{vol}}, tc.expErr)
+			tags := map[string]string{SnapshotNameTagKey: "snapshot-1234", "owner": "team"}
 
-			disk, err := c.GetDiskByName(ctx, tc.volumeName, tc.volumeCapacity)
-			if err != nil {
-				if tc.expErr == nil {
-					t.Fatalf("GetDiskByName() failed: expected no error, got: %v", err)
+			// The intermediate snapshot isn't named like the copy
+			mockEC2.EXPECT().CreateSnapshotWithContext(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, input *ec2.CreateSnapshotInput, _ ...request.Option) (*ec2.Snapshot, error) {
+					for _, tag := range input.TagSpecifications[0].Tags {
+						if aws.StringValue(tag.Key) == SnapshotNameTagKey {
+							t.Fatal("CreateSnapshot() failed: expected the intermediate snapshot without the name tag")
+						}
+					}
+					return &ec2.Snapshot{
+						SnapshotId: aws.String(sourceID),
+						VolumeId:   aws.String(volumeID),
+						State:      aws.String("pending"),
+					}, nil
+				})
+			mockEC2.EXPECT().DescribeSnapshotsWithContext(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, input *ec2.DescribeSnapshotsInput, _ ...request.Option) (*ec2.DescribeSnapshotsOutput, error) {
+					state := "completed"
+					if aws.StringValue(input.SnapshotIds[0]) == copyID && tc.copyState != "" {
+						state = tc.copyState
+					}
+					return &ec2.DescribeSnapshotsOutput{Snapshots: []*ec2.Snapshot{
+						{
+							SnapshotId: input.SnapshotIds[0],
+							VolumeId:   aws.String("vol-ffffffff"),
+							State:      aws.String(state),
+							Encrypted:  aws.Bool(aws.StringValue(input.SnapshotIds[0]) == copyID),
+						},
+					}}, nil
+				}).AnyTimes()
+			mockEC2.EXPECT().CopySnapshotWithContext(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, input *ec2.CopySnapshotInput, _ ...request.Option) (*ec2.CopySnapshotOutput, error) {
+					if aws.StringValue(input.SourceSnapshotId) != sourceID {
+						t.Fatalf("CopySnapshot() failed: expected source snapshot %q, got %q", sourceID, aws.StringValue(input.SourceSnapshotId))
+					}
+					if !aws.BoolValue(input.Encrypted) {
+						t.Fatal("CopySnapshot() failed: expected an encrypted copy")
+					}
+					if kmsKeyID := aws.StringValue(input.KmsKeyId); kmsKeyID != tc.kmsKeyID {
+						t.Fatalf("CopySnapshot() failed: expected KMS key %q, got %q", tc.kmsKeyID, kmsKeyID)
+					}
+					named := false
+					for _, tag := range input.TagSpecifications[0].Tags {
+						if aws.StringValue(tag.Key) == SnapshotNameTagKey && aws.StringValue(tag.Value) == tags[SnapshotNameTagKey] {
+							named = true
+						}
+					}
+					if !named {
+						t.Fatal("CopySnapshot() failed: expected the copy with the name tag")
+					}
+					if tc.copyErr != nil {
+						return nil, tc.copyErr
+					}
+					return &ec2.CopySnapshotOutput{SnapshotId: aws.String(copyID)}, nil
+				})
+			mockEC2.EXPECT().DeleteSnapshotWithContext(gomock.Any(), gomock.Eq(&ec2.DeleteSnapshotInput{
+				SnapshotId: aws.String(sourceID),
+				DryRun:     aws.Bool(false),
+			})).Return(&ec2.DeleteSnapshotOutput{}, tc.deleteErr)
+			if tc.expDeleteCopy {
+				mockEC2.EXPECT().DeleteSnapshotWithContext(gomock.Any(), gomock.Eq(&ec2.DeleteSnapshotInput{
+					SnapshotId: aws.String(copyID),
+					DryRun:     aws.Bool(false),
+				})).Return(&ec2.DeleteSnapshotOutput{}, tc.deleteErr)
+			}
+
+			snapshot, err := c.CreateEncryptedSnapshot(context.Background(), volumeID, tc.kmsKeyID, &SnapshotOptions{Tags: tags})
+			if tc.expErr {
+				if err == nil {
+					t.Fatal("CreateEncryptedSnapshot() failed: expected error, got nothing")
+				}
+				// The copy left behind is reported
+				if tc.expDeleteCopy && tc.deleteErr != nil && !strings.Contains(err.Error(), copyID) {
+					t.Fatalf("CreateEncryptedSnapshot() failed: expected error naming the copy %q, got: %v", copyID, err)
 				}
 			} else {
-				if tc.expErr != nil {
-					t.Fatal("GetDiskByName() failed: expected error, got nothing")
+				if err != nil {
+					t.Fatalf("CreateEncryptedSnapshot() failed: expected no error, got: %v", err)
 				}
-				if disk.CapacityGiB != util.BytesToGiB(tc.volumeCapacity) {
-					t.Fatalf("GetDiskByName() failed: expected capacity %d, got %d", util.BytesToGiB(tc.volumeCapacity), disk.CapacityGiB)
+				if snapshot.SnapshotID != copyID {
+					t.Fatalf("CreateEncryptedSnapshot() failed: expected snapshot %q, got %q", copyID, snapshot.SnapshotID)
 				}
-				if tc.availabilityZone != disk.AvailabilityZone {
-					t.Fatalf("GetDiskByName() failed: expected availabilityZone %q, got %q", tc.availabilityZone, disk.AvailabilityZone)
+				if snapshot.SourceVolumeID != volumeID {
+					t.Fatalf("CreateEncryptedSnapshot() failed: expected source volume %q, got %q", volumeID, snapshot.SourceVolumeID)
 				}
 			}
 
@@ -436,87 +4468,77 @@ func TestGetDiskByName(t *testing.T) {
 	}
 }
 
-func TestGetDiskByID(t *testing.T) {
+func TestValidateSnapshotID(t *testing.T) {
 	testCases := []struct {
-		name             string
-		volumeID         string
-		availabilityZone string
-		expErr           error
+		snapshotID string
+		expErr     bool
 	}{
-		{
-			name:             "success: normal",
-			volumeID:         "vol-test-1234",
-			availabilityZone: expZone,
-			expErr:           nil,
-		},
-		{
-			name:     "fail: DescribeVolumes returned generic error",
-			volumeID: "vol-test-1234",
-			expErr:   fmt.Errorf("DescribeVolumes generic error"),
-		},
+		{snapshotID: "snap-0123456789abcdef0"},
+		{snapshotID: "snap-12345678"},
+		{snapshotID: "snap-4C58F2B6"},
+		{snapshotID: "", expErr: true},
+		{snapshotID: "snap-", expErr: true},
+		{snapshotID: "0123456789abcdef0", expErr: true},
+		{snapshotID: "vol-0123456789abcdef0", expErr: true},
+		{snapshotID: "snap-test", expErr: true},
 	}
 
 	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			mockCtrl := gomock.NewController(t)
-			mockEC2 := mocks.NewMockEC2(mockCtrl)
-			c := newCloud(mockEC2)
-
-			ctx := context.Background()
-			mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(
-				&ec2.DescribeVolumesOutput{
-					Volumes: []*ec2.Volume{
-						{
-							VolumeId:         aws.String(tc.volumeID),
-							AvailabilityZone: aws.String(tc.availabilityZone),
-						},
-					},
-				},
-				tc.expErr,
-			)
-
-			disk, err := c.GetDiskByID(ctx, tc.volumeID)
-			if err != nil {
-				if tc.expErr == nil {
-					t.Fatalf("GetDisk() failed: expected no error, got: %v", err)
-				}
-			} else {
-				if tc.expErr != nil {
-					t.Fatal("GetDisk() failed: expected error, got nothing")
-				}
-				if disk.VolumeID != tc.volumeID {
-					t.Fatalf("GetDisk() failed: expected ID %q, got %q", tc.volumeID, disk.VolumeID)
-				}
-				if tc.availabilityZone != disk.AvailabilityZone {
-					t.Fatalf("GetDiskByName() failed: expected availabilityZone %q, got %q", tc.availabilityZone, disk.AvailabilityZone)
-				}
+		t.Run(tc.snapshotID, func(t *testing.T) {
+			err := validateSnapshotID(tc.snapshotID)
+			if tc.expErr && err == nil {
+				t.Fatal("validateSnapshotID() failed: expected error, got nothing")
+			}
+			if !tc.expErr && err != nil {
+				t.Fatalf("validateSnapshotID() failed: expected no error, got: %v", err)
 			}
-
-			mockCtrl.Finish()
 		})
 	}
 }
 
-func TestCreateSnapshot(t *testing.T) {
+func TestMalformedSnapshotID(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	// No EC2 call is expected
+	c := newCloud(mocks.NewMockEC2(mockCtrl))
+
+	ctx := context.Background()
+	snapshotID := "test-snapshot"
+	if _, err := c.DeleteSnapshot(ctx, snapshotID); err == nil {
+		t.Fatal("DeleteSnapshot() failed: expected error, got nothing")
+	}
+	if _, err := c.GetSnapshotByID(ctx, snapshotID); err == nil {
+		t.Fatal("GetSnapshotByID() failed: expected error, got nothing")
+	}
+}
+
+func TestDeleteSnapshot(t *testing.T) {
 	testCases := []struct {
-		name            string
-		snapshotName    string
-		snapshotOptions *SnapshotOptions
-		expSnapshot     *Snapshot
-		expErr          error
+		name         string
+		snapshotName string
+		deleteErr    error
+		expResp      bool
+		expErr       error
 	}{
 		{
 			name:         "success: normal",
-			snapshotName: "snap-test-name",
-			snapshotOptions: &SnapshotOptions{
-				Tags: map[string]string{
-					SnapshotNameTagKey: "snap-test-name",
-				},
-			},
-			expSnapshot: &Snapshot{
-				SourceVolumeID: "snap-test-volume",
-			},
-			expErr: nil,
+			snapshotName: "snap-000000ac",
+			expResp:      true,
+			expErr:       nil,
+		},
+		{
+			name:         "success: delete snapshot return not found error",
+			snapshotName: "snap-000000ac",
+			deleteErr:    awserr.New("InvalidSnapshot.NotFound", "", nil),
+			expResp:      true,
+			expErr:       nil,
+		},
+		{
+			name:         "fail: delete snapshot return generic error",
+			snapshotName: "snap-000000ac",
+			deleteErr:    fmt.Errorf("DeleteSnapshot generic error"),
+			expResp:      false,
+			expErr:       fmt.Errorf("DeleteSnapshot generic error"),
 		},
 	}
 
@@ -526,56 +4548,156 @@ func TestCreateSnapshot(t *testing.T) {
 			mockEC2 := mocks.NewMockEC2(mockCtrl)
 			c := newCloud(mockEC2)
 
-			ec2snapshot := &ec2.Snapshot{
-				SnapshotId: aws.String(tc.snapshotOptions.Tags[SnapshotNameTagKey]),
-				VolumeId:   aws.String("snap-test-volume"),
-				State:      aws.String("completed"),
-			}
-
 			ctx := context.Background()
-			mockEC2.EXPECT().CreateSnapshotWithContext(gomock.Eq(ctx), gomock.Any()).Return(ec2snapshot, tc.expErr)
-			mockEC2.EXPECT().DescribeSnapshotsWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeSnapshotsOutput{Snapshots: []*ec2.Snapshot{ec2snapshot}}, nil).AnyTimes()
+			mockEC2.EXPECT().DeleteSnapshotWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DeleteSnapshotOutput{}, tc.deleteErr)
 
-			snapshot, err := c.CreateSnapshot(ctx, tc.expSnapshot.SourceVolumeID, tc.snapshotOptions)
+			ok, err := c.DeleteSnapshot(ctx, tc.snapshotName)
 			if err != nil {
 				if tc.expErr == nil {
-					t.Fatalf("CreateSnapshot() failed: expected no error, got: %v", err)
+					t.Fatalf("DeleteSnapshot() failed: expected no error, got: %v", err)
 				}
 			} else {
 				if tc.expErr != nil {
-					t.Fatal("CreateSnapshot() failed: expected error, got nothing")
-				} else {
-					if snapshot.SourceVolumeID != tc.expSnapshot.SourceVolumeID {
-						t.Fatalf("CreateSnapshot() failed: expected source volume ID %s, got %v", tc.expSnapshot.SourceVolumeID, snapshot.SourceVolumeID)
-					}
+					t.Fatal("DeleteSnapshot() failed: expected error, got nothing")
 				}
 			}
+			if ok != tc.expResp {
+				t.Fatalf("DeleteSnapshot() failed: expected return %t, got %t", tc.expResp, ok)
+			}
 
 			mockCtrl.Finish()
 		})
 	}
 }
 
-func TestDeleteSnapshot(t *testing.T) {
+func TestDeleteSnapshots(t *testing.T) {
+	const concurrency = 2
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockEC2 := mocks.NewMockEC2(mockCtrl)
+	c := newCloud(mockEC2)
+
+	deleteErrs := map[string]error{
+		"snap-1": nil,
+		"snap-2": awserr.New("InvalidSnapshot.NotFound", "", nil),
+		"snap-3": fmt.Errorf("DeleteSnapshot generic error"),
+		"snap-4": nil,
+		"snap-5": nil,
+	}
+
+	var (
+		mux            sync.Mutex
+		inFlight       int
+		maxInFlight    int
+		releaseDeletes = make(chan struct{})
+	)
+	ctx := context.Background()
+	mockEC2.EXPECT().DeleteSnapshotWithContext(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ aws.Context, input *ec2.DeleteSnapshotInput, _ ...request.Option) (*ec2.DeleteSnapshotOutput, error) {
+			mux.Lock()
+			inFlight++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			mux.Unlock()
+
+			<-releaseDeletes
+
+			mux.Lock()
+			inFlight--
+			mux.Unlock()
+			return &ec2.DeleteSnapshotOutput{}, deleteErrs[aws.StringValue(input.SnapshotId)]
+		}).Times(len(deleteErrs))
+	go func() {
+		for range deleteErrs {
+			releaseDeletes <- struct{}{}
+		}
+	}()
+
+	snapshotIDs := []string{"snap-1", "snap-2", "snap-3", "snap-4", "snap-5"}
+	errs, err := c.DeleteSnapshots(ctx, snapshotIDs, concurrency)
+	if err == nil {
+		t.Fatal("DeleteSnapshots() failed: expected error, got nothing")
+	}
+	if len(errs) != len(snapshotIDs) {
+		t.Fatalf("DeleteSnapshots() failed: expected %d errors, got %d", len(snapshotIDs), len(errs))
+	}
+	for _, snapshotID := range snapshotIDs {
+		if expFailed := snapshotID == "snap-3"; (errs[snapshotID] != nil) != expFailed {
+			t.Fatalf("DeleteSnapshots() failed: expected failure of %s %t, got error: %v", snapshotID, expFailed, errs[snapshotID])
+		}
+	}
+	if maxInFlight > concurrency {
+		t.Fatalf("DeleteSnapshots() failed: expected at most %d deletions at once, got %d", concurrency, maxInFlight)
+	}
+}
+
+func TestDeleteSnapshotInUse(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockEC2 := mocks.NewMockEC2(mockCtrl)
+	c := newCloud(mockEC2)
+
+	ctx := context.Background()
+	inUseErr := awserr.New("InvalidSnapshot.InUse", "The snapshot snap-000000aa is currently in use by ami-1234", nil)
+	mockEC2.EXPECT().DeleteSnapshotWithContext(gomock.Eq(ctx), gomock.Any()).Return(nil, inUseErr)
+
+	success, err := c.DeleteSnapshot(ctx, "snap-000000aa")
+	if err != ErrSnapshotInUse {
+		t.Fatalf("DeleteSnapshot() failed: expected error %v, got: %v", ErrSnapshotInUse, err)
+	}
+	if success {
+		t.Fatal("DeleteSnapshot() failed: expected no success")
+	}
+
+	mockCtrl.Finish()
+}
+
+func TestModifySnapshotTags(t *testing.T) {
+	snapshotID := "snap-000000aa"
+
 	testCases := []struct {
-		name         string
-		snapshotName string
-		expErr       error
+		name          string
+		addOrUpdate   map[string]string
+		remove        []string
+		expCreateTags bool
+		expDeleteTags bool
+		createTagsErr error
+		deleteTagsErr error
+		expErr        bool
+		expErrIs      error
 	}{
 		{
-			name:         "success: normal",
-			snapshotName: "snap-test-name",
-			expErr:       nil,
+			name:          "success: add and remove",
+			addOrUpdate:   map[string]string{"team": "storage"},
+			remove:        []string{"owner"},
+			expCreateTags: true,
+			expDeleteTags: true,
 		},
 		{
-			name:         "fail: delete snapshot return generic error",
-			snapshotName: "snap-test-name",
-			expErr:       fmt.Errorf("DeleteSnapshot generic error"),
+			name:        "fail: tag key too long",
+			addOrUpdate: map[string]string{strings.Repeat("k", MaxTagKeyLength+1): "storage"},
+			expErr:      true,
+		},
+		{
+			name:   "fail: reserved prefix removed",
+			remove: []string{"aws:team"},
+			expErr: true,
 		},
 		{
-			name:         "fail: delete snapshot return not found error",
-			snapshotName: "snap-test-name",
-			expErr:       awserr.New("InvalidSnapshot.NotFound", "", nil),
+			name:          "fail: snapshot not found",
+			addOrUpdate:   map[string]string{"team": "storage"},
+			expCreateTags: true,
+			createTagsErr: awserr.New("InvalidSnapshot.NotFound", "", nil),
+			expErr:        true,
+			expErrIs:      ErrNotFound,
+		},
+		{
+			name:          "fail: DeleteTags returned generic error",
+			remove:        []string{"owner"},
+			expDeleteTags: true,
+			deleteTagsErr: fmt.Errorf("DeleteTags generic error"),
+			expErr:        true,
 		},
 	}
 
@@ -586,17 +4708,23 @@ func TestDeleteSnapshot(t *testing.T) {
 			c := newCloud(mockEC2)
 
 			ctx := context.Background()
-			mockEC2.EXPECT().DeleteSnapshotWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DeleteSnapshotOutput{}, tc.expErr)
+			if tc.expCreateTags {
+				expRequest := &ec2.CreateTagsInput{Resources: []*string{aws.String(snapshotID)}, Tags: buildTags(tc.addOrUpdate)}
+				mockEC2.EXPECT().CreateTagsWithContext(gomock.Eq(ctx), gomock.Eq(expRequest)).Return(&ec2.CreateTagsOutput{}, tc.createTagsErr)
+			}
+			if tc.expDeleteTags {
+				mockEC2.EXPECT().DeleteTagsWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DeleteTagsOutput{}, tc.deleteTagsErr)
+			}
 
-			_, err := c.DeleteSnapshot(ctx, tc.snapshotName)
-			if err != nil {
-				if tc.expErr == nil {
-					t.Fatalf("DeleteSnapshot() failed: expected no error, got: %v", err)
-				}
-			} else {
-				if tc.expErr != nil {
-					t.Fatal("DeleteSnapshot() failed: expected error, got nothing")
-				}
+			err := c.ModifySnapshotTags(ctx, snapshotID, tc.addOrUpdate, tc.remove)
+			if tc.expErr && err == nil {
+				t.Fatal("ModifySnapshotTags() failed: expected error, got nothing")
+			}
+			if !tc.expErr && err != nil {
+				t.Fatalf("ModifySnapshotTags() failed: expected no error, got: %v", err)
+			}
+			if tc.expErrIs != nil && err != tc.expErrIs {
+				t.Fatalf("ModifySnapshotTags() failed: expected error %v, got: %v", tc.expErrIs, err)
 			}
 
 			mockCtrl.Finish()
@@ -618,15 +4746,15 @@ func TestResizeDisk(t *testing.T) {
 	}{
 		{
 			name:     "success: normal",
-			volumeID: "vol-test",
+			volumeID: "vol-000000a8",
 			existingVolume: &ec2.Volume{
-				VolumeId:         aws.String("vol-test"),
+				VolumeId:         aws.String("vol-000000a8"),
 				Size:             aws.Int64(1),
 				AvailabilityZone: aws.String(defaultZone),
 			},
 			modifiedVolume: &ec2.ModifyVolumeOutput{
 				VolumeModification: &ec2.VolumeModification{
-					VolumeId:          aws.String("vol-test"),
+					VolumeId:          aws.String("vol-000000a8"),
 					TargetSize:        aws.Int64(2),
 					ModificationState: aws.String(ec2.VolumeModificationStateOptimizing),
 				},
@@ -636,15 +4764,15 @@ func TestResizeDisk(t *testing.T) {
 		},
 		{
 			name:     "success: normal modifying state",
-			volumeID: "vol-test",
+			volumeID: "vol-000000a8",
 			existingVolume: &ec2.Volume{
-				VolumeId:         aws.String("vol-test"),
+				VolumeId:         aws.String("vol-000000a8"),
 				Size:             aws.Int64(1),
 				AvailabilityZone: aws.String(defaultZone),
 			},
 			modifiedVolume: &ec2.ModifyVolumeOutput{
 				VolumeModification: &ec2.VolumeModification{
-					VolumeId:          aws.String("vol-test"),
+					VolumeId:          aws.String("vol-000000a8"),
 					TargetSize:        aws.Int64(2),
 					ModificationState: aws.String(ec2.VolumeModificationStateModifying),
 				},
@@ -652,7 +4780,7 @@ func TestResizeDisk(t *testing.T) {
 			descModVolume: &ec2.DescribeVolumesModificationsOutput{
 				VolumesModifications: []*ec2.VolumeModification{
 					{
-						VolumeId:          aws.String("vol-test"),
+						VolumeId:          aws.String("vol-000000a8"),
 						TargetSize:        aws.Int64(2),
 						ModificationState: aws.String(ec2.VolumeModificationStateCompleted),
 					},
@@ -663,16 +4791,16 @@ func TestResizeDisk(t *testing.T) {
 		},
 		{
 			name:                "fail: volume doesn't exist",
-			volumeID:            "vol-test",
+			volumeID:            "vol-000000a8",
 			existingVolumeError: awserr.New("InvalidVolume.NotFound", "", nil),
 			reqSizeGiB:          2,
 			expErr:              fmt.Errorf("ResizeDisk generic error"),
 		},
 		{
 			name:     "sucess: there is a resizing in progress",
-			volumeID: "vol-test",
+			volumeID: "vol-000000a8",
 			existingVolume: &ec2.Volume{
-				VolumeId:         aws.String("vol-test"),
+				VolumeId:         aws.String("vol-000000a8"),
 				Size:             aws.Int64(1),
 				AvailabilityZone: aws.String(defaultZone),
 			},
@@ -680,7 +4808,7 @@ func TestResizeDisk(t *testing.T) {
 			descModVolume: &ec2.DescribeVolumesModificationsOutput{
 				VolumesModifications: []*ec2.VolumeModification{
 					{
-						VolumeId:          aws.String("vol-test"),
+						VolumeId:          aws.String("vol-000000a8"),
 						TargetSize:        aws.Int64(2),
 						ModificationState: aws.String(ec2.VolumeModificationStateCompleted),
 					},
@@ -689,6 +4817,65 @@ func TestResizeDisk(t *testing.T) {
 			reqSizeGiB: 2,
 			expErr:     nil,
 		},
+		{
+			name:     "fail: volume modified too recently",
+			volumeID: "vol-000000a8",
+			existingVolume: &ec2.Volume{
+				VolumeId:         aws.String("vol-000000a8"),
+				Size:             aws.Int64(1),
+				AvailabilityZone: aws.String(defaultZone),
+			},
+			modifiedVolumeError: awserr.New("VolumeModificationRateExceeded", "You've reached the maximum modification rate per volume limit. Wait at least 6 hours between modifications per EBS volume.", nil),
+			reqSizeGiB:          2,
+			expErr:              ErrModificationRateLimited,
+		},
+		{
+			name:     "success: size unchanged",
+			volumeID: "vol-000000a8",
+			existingVolume: &ec2.Volume{
+				VolumeId:         aws.String("vol-000000a8"),
+				Size:             aws.Int64(2),
+				AvailabilityZone: aws.String(defaultZone),
+			},
+			reqSizeGiB: 2,
+			expErr:     nil,
+		},
+		{
+			name:     "fail: volume shrunk",
+			volumeID: "vol-000000a8",
+			existingVolume: &ec2.Volume{
+				VolumeId:         aws.String("vol-000000a8"),
+				Size:             aws.Int64(2),
+				AvailabilityZone: aws.String(defaultZone),
+			},
+			reqSizeGiB: 1,
+			expErr:     ErrCannotShrinkVolume,
+		},
+		{
+			name:     "fail: volume deleted while modified",
+			volumeID: "vol-000000a8",
+			existingVolume: &ec2.Volume{
+				VolumeId:         aws.String("vol-000000a8"),
+				Size:             aws.Int64(1),
+				AvailabilityZone: aws.String(defaultZone),
+			},
+			modifiedVolumeError: awserr.New("InvalidVolume.NotFound", "", nil),
+			reqSizeGiB:          2,
+			expErr:              ErrNotFound,
+		},
+		{
+			name:     "fail: volume in incorrect state",
+			volumeID: "vol-000000a8",
+			existingVolume: &ec2.Volume{
+				VolumeId:         aws.String("vol-000000a8"),
+				Size:             aws.Int64(1),
+				State:            aws.String("deleting"),
+				AvailabilityZone: aws.String(defaultZone),
+			},
+			modifiedVolumeError: awserr.New("IncorrectState", "", nil),
+			reqSizeGiB:          2,
+			expErr:              ErrIncorrectVolumeState,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -711,18 +4898,108 @@ func TestResizeDisk(t *testing.T) {
 				mockEC2.EXPECT().DescribeVolumesModificationsWithContext(gomock.Eq(ctx), gomock.Any()).Return(tc.descModVolume, nil).AnyTimes()
 			}
 
-			newSize, err := c.ResizeDisk(ctx, tc.volumeID, util.GiBToBytes(tc.reqSizeGiB))
-			if err != nil {
-				if tc.expErr == nil {
-					t.Fatalf("ResizeDisk() failed: expected no error, got: %v", err)
+			newSize, err := c.ResizeDisk(ctx, tc.volumeID, util.GiBToBytes(tc.reqSizeGiB))
+			if err != nil {
+				if tc.expErr == nil {
+					t.Fatalf("ResizeDisk() failed: expected no error, got: %v", err)
+				}
+				if (tc.expErr == ErrModificationRateLimited || tc.expErr == ErrCannotShrinkVolume || tc.expErr == ErrNotFound || tc.expErr == ErrIncorrectVolumeState) && err != tc.expErr {
+					t.Fatalf("ResizeDisk() failed: expected error %v, got: %v", tc.expErr, err)
+				}
+			} else {
+				if tc.expErr != nil {
+					t.Fatal("ResizeDisk() failed: expected error, got nothing")
+				} else {
+					if tc.reqSizeGiB != newSize {
+						t.Fatalf("ResizeDisk() failed: expected capacity %d, got %d", tc.reqSizeGiB, newSize)
+					}
+				}
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestModifyDisk(t *testing.T) {
+	testCases := []struct {
+		name       string
+		volumeType string
+		options    *ModifyOptions
+		expRequest *ec2.ModifyVolumeInput
+		expSizeGiB int64
+		expErr     bool
+	}{
+		{
+			name:       "success: size and IOPS in a single modification",
+			volumeType: VolumeTypeIO2,
+			options:    &ModifyOptions{SizeBytes: util.GiBToBytes(20), IOPS: 2000},
+			expRequest: &ec2.ModifyVolumeInput{VolumeId: aws.String("vol-000000a8"), Size: aws.Int64(20), Iops: aws.Int64(2000)},
+			expSizeGiB: 20,
+		},
+		{
+			name:       "success: IOPS only",
+			volumeType: VolumeTypeIO1,
+			options:    &ModifyOptions{IOPS: 2000},
+			expRequest: &ec2.ModifyVolumeInput{VolumeId: aws.String("vol-000000a8"), Iops: aws.Int64(2000)},
+			expSizeGiB: 10,
+		},
+		{
+			name:       "success: size changed, IOPS unchanged",
+			volumeType: VolumeTypeIO1,
+			options:    &ModifyOptions{SizeBytes: util.GiBToBytes(20), IOPS: 500},
+			expRequest: &ec2.ModifyVolumeInput{VolumeId: aws.String("vol-000000a8"), Size: aws.Int64(20)},
+			expSizeGiB: 20,
+		},
+		{
+			name:       "success: nothing changed",
+			volumeType: VolumeTypeIO1,
+			options:    &ModifyOptions{SizeBytes: util.GiBToBytes(10), IOPS: 500},
+			expSizeGiB: 10,
+		},
+		{
+			name:       "fail: IOPS of a gp2 volume",
+			volumeType: VolumeTypeGP2,
+			options:    &ModifyOptions{IOPS: 2000},
+			expErr:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			ctx := context.Background()
+			volume := &ec2.Volume{
+				VolumeId:   aws.String("vol-000000a8"),
+				VolumeType: aws.String(tc.volumeType),
+				Size:       aws.Int64(10),
+				Iops:       aws.Int64(500),
+			}
+			mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{volume}}, nil)
+			if tc.expRequest != nil {
+				mockEC2.EXPECT().ModifyVolumeWithContext(gomock.Eq(ctx), gomock.Eq(tc.expRequest)).Return(&ec2.ModifyVolumeOutput{
+					VolumeModification: &ec2.VolumeModification{
+						VolumeId:          aws.String("vol-000000a8"),
+						TargetSize:        aws.Int64(tc.expSizeGiB),
+						ModificationState: aws.String(ec2.VolumeModificationStateOptimizing),
+					},
+				}, nil)
+			}
+
+			sizeGiB, err := c.ModifyDisk(ctx, "vol-000000a8", tc.options)
+			if tc.expErr {
+				if err == nil {
+					t.Fatal("ModifyDisk() failed: expected error, got nothing")
 				}
 			} else {
-				if tc.expErr != nil {
-					t.Fatal("ResizeDisk() failed: expected error, got nothing")
-				} else {
-					if tc.reqSizeGiB != newSize {
-						t.Fatalf("ResizeDisk() failed: expected capacity %d, got %d", tc.reqSizeGiB, newSize)
-					}
+				if err != nil {
+					t.Fatalf("ModifyDisk() failed: expected no error, got: %v", err)
+				}
+				if sizeGiB != tc.expSizeGiB {
+					t.Fatalf("ModifyDisk() failed: expected size %d, got %d", tc.expSizeGiB, sizeGiB)
 				}
 			}
 
@@ -741,14 +5018,14 @@ func TestGetSnapshotByName(t *testing.T) {
 	}{
 		{
 			name:         "success: normal",
-			snapshotName: "snap-test-name",
+			snapshotName: "snap-000000ac",
 			snapshotOptions: &SnapshotOptions{
 				Tags: map[string]string{
-					SnapshotNameTagKey: "snap-test-name",
+					SnapshotNameTagKey: "snap-000000ac",
 				},
 			},
 			expSnapshot: &Snapshot{
-				SourceVolumeID: "snap-test-volume",
+				SourceVolumeID: "snap-000000af",
 			},
 			expErr: nil,
 		},
@@ -762,7 +5039,7 @@ func TestGetSnapshotByName(t *testing.T) {
 
 			ec2snapshot := &ec2.Snapshot{
 				SnapshotId: aws.String(tc.snapshotOptions.Tags[SnapshotNameTagKey]),
-				VolumeId:   aws.String("snap-test-volume"),
+				VolumeId:   aws.String("snap-000000af"),
 				State:      aws.String("completed"),
 			}
 
@@ -785,6 +5062,80 @@ func TestGetSnapshotByName(t *testing.T) {
 	}
 }
 
+func TestGetNewestSnapshotByName(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name          string
+		snapshotName  string
+		ec2snapshots  []*ec2.Snapshot
+		expSnapshotID string
+		expErr        error
+	}{
+		{
+			name:         "success: single match",
+			snapshotName: "snap-000000ac",
+			ec2snapshots: []*ec2.Snapshot{
+				{SnapshotId: aws.String("snap-1"), VolumeId: aws.String("vol-000000a8"), State: aws.String("pending"), StartTime: aws.Time(now)},
+			},
+			expSnapshotID: "snap-1",
+		},
+		{
+			name:         "success: newest completed of multiple matches",
+			snapshotName: "snap-000000ac",
+			ec2snapshots: []*ec2.Snapshot{
+				{SnapshotId: aws.String("snap-000000a4"), VolumeId: aws.String("vol-000000a8"), State: aws.String("completed"), StartTime: aws.Time(now.Add(-2 * time.Hour))},
+				{SnapshotId: aws.String("snap-000000a2"), VolumeId: aws.String("vol-000000a8"), State: aws.String("completed"), StartTime: aws.Time(now.Add(-1 * time.Hour))},
+				{SnapshotId: aws.String("snap-000000a7"), VolumeId: aws.String("vol-000000a8"), State: aws.String("pending"), StartTime: aws.Time(now)},
+			},
+			expSnapshotID: "snap-000000a2",
+		},
+		{
+			name:         "fail: multiple matches, none completed",
+			snapshotName: "snap-000000ac",
+			ec2snapshots: []*ec2.Snapshot{
+				{SnapshotId: aws.String("snap-1"), VolumeId: aws.String("vol-000000a8"), State: aws.String("pending"), StartTime: aws.Time(now)},
+				{SnapshotId: aws.String("snap-2"), VolumeId: aws.String("vol-000000a8"), State: aws.String("error"), StartTime: aws.Time(now)},
+			},
+			expErr: ErrMultiSnapshots,
+		},
+		{
+			name:         "fail: no matches",
+			snapshotName: "snap-000000ac",
+			expErr:       ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			ctx := context.Background()
+			mockEC2.EXPECT().DescribeSnapshotsWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeSnapshotsOutput{Snapshots: tc.ec2snapshots}, nil)
+
+			snapshot, err := c.GetNewestSnapshotByName(ctx, tc.snapshotName)
+			if err != nil {
+				if tc.expErr == nil {
+					t.Fatalf("GetNewestSnapshotByName() failed: expected no error, got: %v", err)
+				}
+				if err != tc.expErr {
+					t.Fatalf("GetNewestSnapshotByName() failed: expected error %v, got: %v", tc.expErr, err)
+				}
+			} else {
+				if tc.expErr != nil {
+					t.Fatal("GetNewestSnapshotByName() failed: expected error, got nothing")
+				}
+				if snapshot.SnapshotID != tc.expSnapshotID {
+					t.Fatalf("GetNewestSnapshotByName() failed: expected snapshot %q, got %q", tc.expSnapshotID, snapshot.SnapshotID)
+				}
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
 func TestGetSnapshotByID(t *testing.T) {
 	testCases := []struct {
 		name            string
@@ -795,14 +5146,14 @@ func TestGetSnapshotByID(t *testing.T) {
 	}{
 		{
 			name:         "success: normal",
-			snapshotName: "snap-test-name",
+			snapshotName: "snap-000000ac",
 			snapshotOptions: &SnapshotOptions{
 				Tags: map[string]string{
-					SnapshotNameTagKey: "snap-test-name",
+					SnapshotNameTagKey: "snap-000000ac",
 				},
 			},
 			expSnapshot: &Snapshot{
-				SourceVolumeID: "snap-test-volume",
+				SourceVolumeID: "snap-000000af",
 			},
 			expErr: nil,
 		},
@@ -816,7 +5167,7 @@ func TestGetSnapshotByID(t *testing.T) {
 
 			ec2snapshot := &ec2.Snapshot{
 				SnapshotId: aws.String(tc.snapshotOptions.Tags[SnapshotNameTagKey]),
-				VolumeId:   aws.String("snap-test-volume"),
+				VolumeId:   aws.String("snap-000000af"),
 				State:      aws.String("completed"),
 			}
 
@@ -838,6 +5189,206 @@ func TestGetSnapshotByID(t *testing.T) {
 		})
 	}
 }
+func TestWaitForSnapshotCompleted(t *testing.T) {
+	snapshotID := "snap-000000ab"
+	snapshot := func(state string) *ec2.DescribeSnapshotsOutput {
+		return &ec2.DescribeSnapshotsOutput{
+			Snapshots: []*ec2.Snapshot{
+				{
+					SnapshotId: aws.String(snapshotID),
+					VolumeId:   aws.String("vol-000000a8"),
+					State:      aws.String(state),
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name      string
+		states    []string
+		canceled  bool
+		expErr    error
+		expCalled int
+	}{
+		{
+			name:      "success: already completed",
+			states:    []string{"completed"},
+			expCalled: 1,
+		},
+		{
+			name:      "success: pending then completed",
+			states:    []string{"pending", "completed"},
+			expCalled: 2,
+		},
+		{
+			name:      "fail: error state",
+			states:    []string{"pending", "error"},
+			expErr:    ErrSnapshotFailed,
+			expCalled: 2,
+		},
+		{
+			name:      "fail: context canceled",
+			canceled:  true,
+			expErr:    context.Canceled,
+			expCalled: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.canceled {
+				cancel()
+			}
+
+			called := 0
+			mockEC2.EXPECT().DescribeSnapshotsWithContext(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ *ec2.DescribeSnapshotsInput, _ ...request.Option) (*ec2.DescribeSnapshotsOutput, error) {
+					state := tc.states[called]
+					called++
+					return snapshot(state), nil
+				}).Times(tc.expCalled)
+
+			snap, err := c.WaitForSnapshotCompleted(ctx, snapshotID)
+			if tc.expErr != nil {
+				if !errors.Is(err, tc.expErr) {
+					t.Fatalf("WaitForSnapshotCompleted() failed: expected error %v, got: %v", tc.expErr, err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("WaitForSnapshotCompleted() failed: expected no error, got: %v", err)
+				}
+				if snap.SnapshotID != snapshotID {
+					t.Fatalf("WaitForSnapshotCompleted() failed: expected snapshot %q, got %q", snapshotID, snap.SnapshotID)
+				}
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestPollWithCappedBackoff(t *testing.T) {
+	// The polling goes on past any fixed number of steps, at the capped interval
+	called := 0
+	start := time.Now()
+	err := pollWithCappedBackoff(context.Background(), time.Millisecond, 2*time.Millisecond, func() (bool, error) {
+		called++
+		return called == 50, nil
+	})
+	if err != nil {
+		t.Fatalf("pollWithCappedBackoff() failed: expected no error, got: %v", err)
+	}
+	if called != 50 {
+		t.Fatalf("pollWithCappedBackoff() failed: expected 50 checks, got %d", called)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("pollWithCappedBackoff() failed: expected the interval to be capped, took %v", elapsed)
+	}
+
+	// It stops when the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = pollWithCappedBackoff(ctx, time.Millisecond, 2*time.Millisecond, func() (bool, error) {
+		return false, nil
+	})
+	if err != context.DeadlineExceeded {
+		t.Fatalf("pollWithCappedBackoff() failed: expected error %v, got: %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestGetSnapshotActualSize(t *testing.T) {
+	testCases := []struct {
+		name       string
+		snapshotID string
+		pages      []*ebs.ListSnapshotBlocksOutput
+		listErr    error
+		expSize    int64
+		expErr     error
+	}{
+		{
+			name:       "success: blocks over several pages",
+			snapshotID: "snap-000000a9",
+			pages: []*ebs.ListSnapshotBlocksOutput{
+				{
+					Blocks:    []*ebs.Block{{BlockIndex: aws.Int64(0)}, {BlockIndex: aws.Int64(1)}},
+					BlockSize: aws.Int64(512 * 1024),
+					NextToken: aws.String("token"),
+				},
+				{
+					Blocks:    []*ebs.Block{{BlockIndex: aws.Int64(7)}},
+					BlockSize: aws.Int64(512 * 1024),
+				},
+			},
+			expSize: 3 * 512 * 1024,
+		},
+		{
+			name:       "success: no blocks",
+			snapshotID: "snap-000000a9",
+			pages: []*ebs.ListSnapshotBlocksOutput{
+				{BlockSize: aws.Int64(512 * 1024)},
+			},
+			expSize: 0,
+		},
+		{
+			name:       "fail: snapshot not found",
+			snapshotID: "snap-000000a9",
+			listErr:    awserr.New(ebs.ErrCodeResourceNotFoundException, "", nil),
+			expErr:     ErrNotFound,
+		},
+		{
+			name:       "fail: ListSnapshotBlocks returned generic error",
+			snapshotID: "snap-000000a9",
+			listErr:    fmt.Errorf("ListSnapshotBlocks generic error"),
+			expErr:     fmt.Errorf("ListSnapshotBlocks generic error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEBS := mocks.NewMockEBS(mockCtrl)
+			c := &cloud{
+				region: "test-region",
+				dm:     dm.NewDeviceManager(),
+				ebs:    mockEBS,
+			}
+
+			ctx := context.Background()
+			if tc.listErr != nil {
+				mockEBS.EXPECT().ListSnapshotBlocksWithContext(gomock.Eq(ctx), gomock.Any()).Return(nil, tc.listErr)
+			}
+			for _, page := range tc.pages {
+				mockEBS.EXPECT().ListSnapshotBlocksWithContext(gomock.Eq(ctx), gomock.Any()).Return(page, nil)
+			}
+
+			size, err := c.GetSnapshotActualSize(ctx, tc.snapshotID)
+			if err != nil {
+				if tc.expErr == nil {
+					t.Fatalf("GetSnapshotActualSize() failed: expected no error, got: %v", err)
+				}
+				if tc.expErr == ErrNotFound && err != ErrNotFound {
+					t.Fatalf("GetSnapshotActualSize() failed: expected error %v, got: %v", ErrNotFound, err)
+				}
+			} else {
+				if tc.expErr != nil {
+					t.Fatal("GetSnapshotActualSize() failed: expected error, got nothing")
+				}
+				if size != tc.expSize {
+					t.Fatalf("GetSnapshotActualSize() failed: expected size %d, got %d", tc.expSize, size)
+				}
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
+
 func TestListSnapshots(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -848,23 +5399,23 @@ func TestListSnapshots(t *testing.T) {
 			testFunc: func(t *testing.T) {
 				expSnapshots := []*Snapshot{
 					{
-						SourceVolumeID: "snap-test-volume1",
-						SnapshotID:     "snap-test-name1",
+						SourceVolumeID: "snap-000000b0",
+						SnapshotID:     "snap-000000ad",
 					},
 					{
-						SourceVolumeID: "snap-test-volume2",
-						SnapshotID:     "snap-test-name2",
+						SourceVolumeID: "snap-000000b1",
+						SnapshotID:     "snap-000000ae",
 					},
 				}
 				ec2Snapshots := []*ec2.Snapshot{
 					{
 						SnapshotId: aws.String(expSnapshots[0].SnapshotID),
-						VolumeId:   aws.String("snap-test-volume1"),
+						VolumeId:   aws.String("snap-000000b0"),
 						State:      aws.String("completed"),
 					},
 					{
 						SnapshotId: aws.String(expSnapshots[1].SnapshotID),
-						VolumeId:   aws.String("snap-test-volume2"),
+						VolumeId:   aws.String("snap-000000b1"),
 						State:      aws.String("completed"),
 					},
 				}
@@ -887,15 +5438,15 @@ func TestListSnapshots(t *testing.T) {
 		{
 			name: "success: with volume ID",
 			testFunc: func(t *testing.T) {
-				sourceVolumeID := "snap-test-volume"
+				sourceVolumeID := "snap-000000af"
 				expSnapshots := []*Snapshot{
 					{
 						SourceVolumeID: sourceVolumeID,
-						SnapshotID:     "snap-test-name1",
+						SnapshotID:     "snap-000000ad",
 					},
 					{
 						SourceVolumeID: sourceVolumeID,
-						SnapshotID:     "snap-test-name2",
+						SnapshotID:     "snap-000000ae",
 					},
 				}
 				ec2Snapshots := []*ec2.Snapshot{
@@ -944,8 +5495,8 @@ func TestListSnapshots(t *testing.T) {
 				var expSnapshots []*Snapshot
 				for i := 0; i < maxResults*2; i++ {
 					expSnapshots = append(expSnapshots, &Snapshot{
-						SourceVolumeID: "snap-test-volume1",
-						SnapshotID:     fmt.Sprintf("snap-test-name%d", i),
+						SourceVolumeID: "snap-000000b0",
+						SnapshotID:     fmt.Sprintf("snap-000000ac%d", i),
 					})
 				}
 
@@ -953,7 +5504,7 @@ func TestListSnapshots(t *testing.T) {
 				for i := 0; i < maxResults*2; i++ {
 					ec2Snapshots = append(ec2Snapshots, &ec2.Snapshot{
 						SnapshotId: aws.String(expSnapshots[i].SnapshotID),
-						VolumeId:   aws.String(fmt.Sprintf("snap-test-volume%d", i)),
+						VolumeId:   aws.String(fmt.Sprintf("snap-000000af%d", i)),
 						State:      aws.String("completed"),
 					})
 				}
@@ -1049,12 +5600,379 @@ func TestListSnapshots(t *testing.T) {
 	}
 }
 
+func TestListSnapshotsWithOptions(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	mockEC2 := mocks.NewMockEC2(mockCtl)
+	c := newCloud(mockEC2)
+
+	ec2Snapshot := &ec2.Snapshot{
+		SnapshotId: aws.String("snap-000000ad"),
+		VolumeId:   aws.String("snap-000000b0"),
+		State:      aws.String("completed"),
+	}
+
+	ctx := context.Background()
+	mockEC2.EXPECT().DescribeSnapshotsWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+		func(_ aws.Context, input *ec2.DescribeSnapshotsInput, _ ...request.Option) (*ec2.DescribeSnapshotsOutput, error) {
+			filters := map[string]string{}
+			for _, f := range input.Filters {
+				filters[aws.StringValue(f.Name)] = aws.StringValue(f.Values[0])
+			}
+			expFilters := map[string]string{
+				"volume-id":          "snap-000000b0",
+				"tag:backup-policy":  "daily",
+				"tag:backup-project": "test",
+			}
+			if !reflect.DeepEqual(filters, expFilters) {
+				t.Fatalf("DescribeSnapshots() called with filters %v, expected %v", filters, expFilters)
+			}
+			if aws.StringValue(input.NextToken) != "token" {
+				t.Fatalf("DescribeSnapshots() called with next token %q, expected %q", aws.StringValue(input.NextToken), "token")
+			}
+			return &ec2.DescribeSnapshotsOutput{Snapshots: []*ec2.Snapshot{ec2Snapshot}, NextToken: aws.String("next-token")}, nil
+		})
+
+	resp, err := c.ListSnapshotsWithOptions(ctx, &ListSnapshotsOptions{
+		VolumeID:   "snap-000000b0",
+		Tags:       map[string]string{"backup-policy": "daily", "backup-project": "test"},
+		MaxResults: 5,
+		NextToken:  "token",
+	})
+	if err != nil {
+		t.Fatalf("ListSnapshotsWithOptions() failed: expected no error, got: %v", err)
+	}
+	if len(resp.Snapshots) != 1 || resp.Snapshots[0].SnapshotID != "snap-000000ad" {
+		t.Fatalf("ListSnapshotsWithOptions() failed: expected snapshot %q, got %+v", "snap-000000ad", resp.Snapshots)
+	}
+	if resp.NextToken != "next-token" {
+		t.Fatalf("ListSnapshotsWithOptions() failed: expected next token %q, got %q", "next-token", resp.NextToken)
+	}
+}
+
+func TestSnapshotOwners(t *testing.T) {
+	testCases := []struct {
+		name      string
+		shared    bool
+		expOwners []string
+	}{
+		{
+			name:      "owned by the account",
+			expOwners: []string{"self"},
+		},
+		{
+			name:      "shared snapshots included",
+			shared:    true,
+			expOwners: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtl := gomock.NewController(t)
+			defer mockCtl.Finish()
+			mockEC2 := mocks.NewMockEC2(mockCtl)
+			c := newCloud(mockEC2).(*cloud)
+			c.options.includeSharedSnapshots = tc.shared
+
+			ec2Snapshot := &ec2.Snapshot{
+				SnapshotId: aws.String("snap-000000ac"),
+				VolumeId:   aws.String("snap-000000af"),
+				State:      aws.String("completed"),
+			}
+
+			ctx := context.Background()
+			gomock.InOrder(
+				mockEC2.EXPECT().DescribeSnapshotsWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+					func(_ aws.Context, input *ec2.DescribeSnapshotsInput, _ ...request.Option) (*ec2.DescribeSnapshotsOutput, error) {
+						if input.OwnerIds != nil {
+							t.Fatalf("DescribeSnapshots() called with owners %v for a lookup by ID, expected none", aws.StringValueSlice(input.OwnerIds))
+						}
+						return &ec2.DescribeSnapshotsOutput{Snapshots: []*ec2.Snapshot{ec2Snapshot}}, nil
+					}),
+				mockEC2.EXPECT().DescribeSnapshotsWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+					func(_ aws.Context, input *ec2.DescribeSnapshotsInput, _ ...request.Option) (*ec2.DescribeSnapshotsOutput, error) {
+						if owners := aws.StringValueSlice(input.OwnerIds); !reflect.DeepEqual(owners, tc.expOwners) {
+							t.Fatalf("DescribeSnapshots() called with owners %v, expected %v", owners, tc.expOwners)
+						}
+						return &ec2.DescribeSnapshotsOutput{Snapshots: []*ec2.Snapshot{ec2Snapshot}}, nil
+					}),
+			)
+
+			if _, err := c.GetSnapshotByID(ctx, "snap-000000ac"); err != nil {
+				t.Fatalf("GetSnapshotByID() failed: expected no error, got: %v", err)
+			}
+			if _, err := c.ListSnapshots(ctx, "", 0, ""); err != nil {
+				t.Fatalf("ListSnapshots() failed: expected no error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestListSnapshotsOlderThan(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	mockEC2 := mocks.NewMockEC2(mockCtl)
+	c := newCloud(mockEC2)
+
+	cutoff := time.Now().Add(-24 * time.Hour)
+	pages := []*ec2.DescribeSnapshotsOutput{
+		{
+			Snapshots: []*ec2.Snapshot{
+				{SnapshotId: aws.String("snap-000000a5"), StartTime: aws.Time(cutoff.Add(-time.Hour)), State: aws.String("completed")},
+				{SnapshotId: aws.String("snap-000000a3"), StartTime: aws.Time(cutoff.Add(time.Hour)), State: aws.String("completed")},
+			},
+			NextToken: aws.String("token"),
+		},
+		{
+			Snapshots: []*ec2.Snapshot{
+				{SnapshotId: aws.String("snap-000000a6"), StartTime: aws.Time(cutoff.Add(-48 * time.Hour)), State: aws.String("completed")},
+			},
+		},
+	}
+
+	ctx := context.Background()
+	call := 0
+	mockEC2.EXPECT().DescribeSnapshotsWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+		func(_ aws.Context, input *ec2.DescribeSnapshotsInput, _ ...request.Option) (*ec2.DescribeSnapshotsOutput, error) {
+			if len(input.OwnerIds) != 1 || aws.StringValue(input.OwnerIds[0]) != "self" {
+				t.Fatalf("DescribeSnapshots() called with owners %v, expected [self]", aws.StringValueSlice(input.OwnerIds))
+			}
+			if len(input.Filters) != 1 || aws.StringValue(input.Filters[0].Name) != "tag:backup-policy" || aws.StringValue(input.Filters[0].Values[0]) != "daily" {
+				t.Fatalf("DescribeSnapshots() called with unexpected filters %v", input.Filters)
+			}
+			page := pages[call]
+			call++
+			return page, nil
+		}).Times(len(pages))
+
+	snapshots, err := c.ListSnapshotsOlderThan(ctx, cutoff, map[string]string{"backup-policy": "daily"})
+	if err != nil {
+		t.Fatalf("ListSnapshotsOlderThan() failed: expected no error, got: %v", err)
+	}
+	snapshotIDs := []string{}
+	for _, snapshot := range snapshots {
+		snapshotIDs = append(snapshotIDs, snapshot.SnapshotID)
+	}
+	if expSnapshotIDs := []string{"snap-000000a5", "snap-000000a6"}; !reflect.DeepEqual(snapshotIDs, expSnapshotIDs) {
+		t.Fatalf("ListSnapshotsOlderThan() failed: expected snapshots %v, got %v", expSnapshotIDs, snapshotIDs)
+	}
+}
+
+func TestDescribeCancelledContext(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	// No EC2 call is expected
+	mockEC2 := mocks.NewMockEC2(mockCtrl)
+	c := newCloud(mockEC2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := c.GetDiskByID(ctx, "vol-000000a8"); err != context.Canceled {
+		t.Fatalf("GetDiskByID() failed: expected %v, got: %v", context.Canceled, err)
+	}
+	if _, err := c.GetSnapshotByName(ctx, "snap-000000a9"); err != context.Canceled {
+		t.Fatalf("GetSnapshotByName() failed: expected %v, got: %v", context.Canceled, err)
+	}
+	if _, err := c.AttachDisk(ctx, "vol-000000a8", "node-test"); err != context.Canceled {
+		t.Fatalf("AttachDisk() failed: expected %v, got: %v", context.Canceled, err)
+	}
+
+	mockCtrl.Finish()
+}
+
+func TestBuildTagSpecifications(t *testing.T) {
+	testCases := []struct {
+		name    string
+		tags    map[string]string
+		expTags []*ec2.Tag
+	}{
+		{
+			name: "empty tags",
+			tags: map[string]string{},
+		},
+		{
+			name: "single tag",
+			tags: map[string]string{VolumeNameTagKey: "vol-000000a4"},
+			expTags: []*ec2.Tag{
+				{Key: aws.String(VolumeNameTagKey), Value: aws.String("vol-000000a4")},
+			},
+		},
+		{
+			name: "many tags",
+			tags: map[string]string{
+				"key-c":          "value-c",
+				"key-a":          "value-a",
+				VolumeNameTagKey: "vol-000000a4",
+				"key-b":          "value-b",
+			},
+			expTags: []*ec2.Tag{
+				{Key: aws.String(VolumeNameTagKey), Value: aws.String("vol-000000a4")},
+				{Key: aws.String("key-a"), Value: aws.String("value-a")},
+				{Key: aws.String("key-b"), Value: aws.String("value-b")},
+				{Key: aws.String("key-c"), Value: aws.String("value-c")},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tagSpecs := buildTagSpecifications(ec2.ResourceTypeVolume, tc.tags)
+			if len(tc.expTags) == 0 {
+				if tagSpecs != nil {
+					t.Fatalf("buildTagSpecifications() failed: expected nil, got %v", tagSpecs)
+				}
+				return
+			}
+
+			if len(tagSpecs) != 1 {
+				t.Fatalf("buildTagSpecifications() failed: expected 1 tag specification, got %d", len(tagSpecs))
+			}
+			if resourceType := aws.StringValue(tagSpecs[0].ResourceType); resourceType != ec2.ResourceTypeVolume {
+				t.Fatalf("buildTagSpecifications() failed: expected resource type %q, got %q", ec2.ResourceTypeVolume, resourceType)
+			}
+			if !reflect.DeepEqual(tagSpecs[0].Tags, tc.expTags) {
+				t.Fatalf("buildTagSpecifications() failed: expected tags %v, got %v", tc.expTags, tagSpecs[0].Tags)
+			}
+		})
+	}
+}
+
 func newCloud(mockEC2 EC2) Cloud {
-	return &cloud{
-		region: "test-region",
-		dm:     dm.NewDeviceManager(),
-		ec2:    mockEC2,
+	return NewCloudWithEC2("test-region", mockEC2, dm.NewDeviceManager())
+}
+
+func TestNewCloudWithEC2(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockEC2 := mocks.NewMockEC2(mockCtrl)
+	c := NewCloudWithEC2("test-region", mockEC2, dm.NewDeviceManager())
+	defer c.Close()
+
+	// It has the same defaults as NewCloud
+	if retries := c.(*cloud).options.attachNotFoundRetries; retries != DefaultAttachNotFoundRetries {
+		t.Fatalf("NewCloudWithEC2() failed: expected %d attach not found retries, got %d", DefaultAttachNotFoundRetries, retries)
+	}
+
+	volume := &ec2.Volume{VolumeId: aws.String("vol-000000a8"), Size: aws.Int64(1), AvailabilityZone: aws.String(defaultZone)}
+	ctx := context.Background()
+	mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{volume}}, nil)
+
+	disk, err := c.GetDiskByID(ctx, "vol-000000a8")
+	if err != nil {
+		t.Fatalf("GetDiskByID() failed: expected no error, got: %v", err)
+	}
+	if disk.VolumeID != "vol-000000a8" {
+		t.Fatalf("GetDiskByID() failed: expected volume %q, got %q", "vol-000000a8", disk.VolumeID)
+	}
+
+	// There is no EBS client to call
+	if _, err := c.GetSnapshotActualSize(ctx, "snap-000000a9"); err == nil {
+		t.Fatal("GetSnapshotActualSize() failed: expected error, got nothing")
+	}
+
+	mockCtrl.Finish()
+}
+
+// TestConcurrentUse runs the disk operations of many goroutines at once on a
+// single cloud, for the race detector to catch unguarded shared state.
+func TestConcurrentUse(t *testing.T) {
+	const (
+		goroutines = 10
+		nodeID     = "node-1234"
+	)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockEC2 := mocks.NewMockEC2(mockCtrl)
+	c := newCloud(mockEC2).(*cloud)
+	c.volumeCache = newVolumeCache(time.Minute)
+
+	// The EC2 state shared by the goroutines: the created volumes and the
+	// attached ones
+	var mux sync.Mutex
+	created := map[string]bool{}
+	attached := map[string]bool{}
+	describe := func(volumeID string) *ec2.Volume {
+		volume := &ec2.Volume{
+			VolumeId:         aws.String(volumeID),
+			Size:             aws.Int64(1),
+			State:            aws.String("available"),
+			AvailabilityZone: aws.String(expZone),
+		}
+		if attached[volumeID] {
+			volume.State = aws.String("in-use")
+			volume.Attachments = []*ec2.VolumeAttachment{{
+				InstanceId: aws.String(nodeID),
+				Device:     aws.String("/dev/disk/by-id/virtio-" + volumeID),
+				State:      aws.String("attached"),
+			}}
+		}
+		return volume
+	}
+
+	mockEC2.EXPECT().CreateVolumeWithContext(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ aws.Context, input *ec2.CreateVolumeInput, _ ...request.Option) (*ec2.Volume, error) {
+			mux.Lock()
+			defer mux.Unlock()
+			var volumeID string
+			for _, tag := range input.TagSpecifications[0].Tags {
+				if aws.StringValue(tag.Key) == VolumeNameTagKey {
+					volumeID = aws.StringValue(tag.Value)
+				}
+			}
+			created[volumeID] = true
+			return describe(volumeID), nil
+		}).AnyTimes()
+	mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ aws.Context, input *ec2.DescribeVolumesInput, _ ...request.Option) (*ec2.DescribeVolumesOutput, error) {
+			mux.Lock()
+			defer mux.Unlock()
+			output := &ec2.DescribeVolumesOutput{}
+			for _, volumeID := range aws.StringValueSlice(input.VolumeIds) {
+				if created[volumeID] {
+					output.Volumes = append(output.Volumes, describe(volumeID))
+				}
+			}
+			return output, nil
+		}).AnyTimes()
+	mockEC2.EXPECT().DescribeInstancesWithContext(gomock.Any(), gomock.Any()).Return(newDescribeInstancesOutput(nodeID), nil).AnyTimes()
+	mockEC2.EXPECT().AttachVolumeWithContext(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ aws.Context, input *ec2.AttachVolumeInput, _ ...request.Option) (*ec2.VolumeAttachment, error) {
+			mux.Lock()
+			defer mux.Unlock()
+			attached[aws.StringValue(input.VolumeId)] = true
+			return &ec2.VolumeAttachment{}, nil
+		}).AnyTimes()
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(volumeID string) {
+			defer wg.Done()
+
+			diskOptions := &DiskOptions{
+				CapacityBytes:    util.GiBToBytes(1),
+				Tags:             map[string]string{VolumeNameTagKey: volumeID},
+				AvailabilityZone: expZone,
+			}
+			if _, err := c.CreateDisk(ctx, volumeID, diskOptions); err != nil {
+				t.Errorf("CreateDisk(%s) failed: expected no error, got: %v", volumeID, err)
+				return
+			}
+			// The second lookup is served from the volume cache
+			for j := 0; j < 2; j++ {
+				if _, err := c.GetDiskByID(ctx, volumeID); err != nil {
+					t.Errorf("GetDiskByID(%s) failed: expected no error, got: %v", volumeID, err)
+					return
+				}
+			}
+			if _, err := c.AttachDisk(ctx, volumeID, nodeID); err != nil {
+				t.Errorf("AttachDisk(%s) failed: expected no error, got: %v", volumeID, err)
+			}
+		}(fmt.Sprintf("vol-%08x", i))
 	}
+	wg.Wait()
 }
 
 func newDescribeInstancesOutput(nodeID string) *ec2.DescribeInstancesOutput {
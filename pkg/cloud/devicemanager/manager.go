@@ -123,10 +123,13 @@ func (d *deviceManager) NewDevice(instance *ec2.Instance, volumeID string) (*Dev
 		return nil, err
 	}
 
-	// Add the chosen device and volume to the "attachments in progress" map
+	// Add the chosen device and volume to the "attachments in progress" map.
+	// The device path is derived from the volume ID, so it can't be taken by
+	// the root device or an instance-store volume, whose names are like
+	// /dev/sda1 or /dev/xvdb.
 	d.inFlight.Add(nodeID, volumeID, volumeID)
 
-	return d.newBlockDevice(instance, volumeID, devPreffix+volumeID, false), nil
+	return d.newBlockDevice(instance, volumeID, DevicePath(volumeID), false), nil
 }
 
 func (d *deviceManager) GetDevice(instance *ec2.Instance, volumeID string) (*Device, error) {
@@ -142,6 +145,12 @@ func (d *deviceManager) GetDevice(instance *ec2.Instance, volumeID string) (*Dev
 	return d.newBlockDevice(instance, volumeID, "", false), nil
 }
 
+// DevicePath returns the path under which the volume is requested to be
+// attached to an instance.
+func DevicePath(volumeID string) string {
+	return devPreffix + volumeID
+}
+
 func (d *deviceManager) newBlockDevice(instance *ec2.Instance, volumeID string, path string, isAlreadyAssigned bool) *Device {
 	device := &Device{
 		Instance:          instance,
@@ -192,7 +201,7 @@ func (d *deviceManager) getVolumeIdsInUse(instance *ec2.Instance) []string {
 	nodeID := aws.StringValue(instance.InstanceId)
 	var inUse []string
 	for _, blockDevice := range instance.BlockDeviceMappings {
-		if blockDevice.Ebs == nil {
+		if blockDevice.Ebs == nil || blockDevice.Ebs.VolumeId == nil {
 			continue
 		}
 
@@ -217,7 +226,7 @@ func (d *deviceManager) getVolumeIdsInUse(instance *ec2.Instance) []string {
 func (d *deviceManager) getPath(inUse []string, volumeID string) string {
 	for _, volID := range inUse {
 		if volumeID == volID {
-			return devPreffix + volumeID
+			return DevicePath(volumeID)
 		}
 	}
 	return ""
@@ -92,6 +92,32 @@ func TestNewDevice(t *testing.T) {
 	}
 }
 
+func TestNewDeviceWithNonEBSMappings(t *testing.T) {
+	dm := NewDeviceManager()
+	fakeInstance := newFakeInstance("instance-1", "vol-root", "/dev/sda1")
+	fakeInstance.RootDeviceName = aws.String("/dev/sda1")
+	fakeInstance.BlockDeviceMappings = append(fakeInstance.BlockDeviceMappings,
+		&ec2.InstanceBlockDeviceMapping{DeviceName: aws.String("/dev/xvdb")},
+		&ec2.InstanceBlockDeviceMapping{DeviceName: aws.String("/dev/xvdc"), Ebs: &ec2.EbsInstanceBlockDevice{}},
+	)
+
+	// Should not panic on mappings without an EBS volume, nor take them
+	// for volumes in use
+	dev, err := dm.NewDevice(fakeInstance, "vol-2")
+	assertDevice(t, dev, false /*IsAlreadyAssigned*/, err)
+	if dev.Path != DevicePath("vol-2") {
+		t.Fatalf("Expected path %q, got %q", DevicePath("vol-2"), dev.Path)
+	}
+	dev.Release(false)
+
+	// Should still find the root EBS volume among the volumes in use
+	dev, err = dm.NewDevice(fakeInstance, "vol-root")
+	assertDevice(t, dev, true /*IsAlreadyAssigned*/, err)
+	if dev.Path != DevicePath("vol-root") {
+		t.Fatalf("Expected path %q, got %q", DevicePath("vol-root"), dev.Path)
+	}
+}
+
 func TestGetDevice(t *testing.T) {
 	testCases := []struct {
 		name               string
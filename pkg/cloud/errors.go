@@ -0,0 +1,76 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+// awsErrorSentinels maps the AWS error codes to the sentinel errors they
+// match with errors.Is.
+var awsErrorSentinels = map[string]error{
+	"InvalidVolume.NotFound":         ErrNotFound,
+	"InvalidInstanceID.NotFound":     ErrNotFound,
+	"InvalidSnapshot.NotFound":       ErrNotFound,
+	"InvalidSnapshot.InUse":          ErrSnapshotInUse,
+	"VolumeModificationRateExceeded": ErrModificationRateLimited,
+	"IdempotentParameterMismatch":    ErrIdempotentParameterMismatch,
+	"IncorrectState":                 ErrIncorrectVolumeState,
+}
+
+// CloudError is an error returned by the AWS API, along with the operation
+// that failed. It matches the sentinel errors of its AWS error code with
+// errors.Is, e.g. ErrNotFound for InvalidVolume.NotFound, and unwraps to the
+// awserr.Error.
+type CloudError struct {
+	// Message describes the operation that failed
+	Message string
+	// Err is the error returned by the AWS API
+	Err awserr.Error
+}
+
+// Error returns the message followed by the AWS error.
+func (e *CloudError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Message, e.Err)
+}
+
+// Code returns the AWS error code, e.g. InvalidVolume.NotFound.
+func (e *CloudError) Code() string {
+	return e.Err.Code()
+}
+
+// Unwrap returns the AWS error.
+func (e *CloudError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the AWS error code matches the target sentinel error.
+func (e *CloudError) Is(target error) bool {
+	sentinel, ok := awsErrorSentinels[e.Code()]
+	return ok && sentinel == target
+}
+
+// newCloudError wraps the error of the failed operation described by message,
+// in a *CloudError if it is an AWS error.
+func newCloudError(err error, message string) error {
+	if awsErr, ok := err.(awserr.Error); ok {
+		return &CloudError{Message: message, Err: awsErr}
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
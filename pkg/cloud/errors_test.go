@@ -0,0 +1,227 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/c2devel/aws-ebs-csi-driver/pkg/cloud/mocks"
+	"github.com/c2devel/aws-ebs-csi-driver/pkg/util"
+	"github.com/golang/mock/gomock"
+)
+
+func TestCloudError(t *testing.T) {
+	testCases := []struct {
+		name        string
+		err         error
+		expSentinel error
+	}{
+		{
+			name:        "volume not found",
+			err:         awserr.New("InvalidVolume.NotFound", "", nil),
+			expSentinel: ErrNotFound,
+		},
+		{
+			name:        "instance not found",
+			err:         awserr.New("InvalidInstanceID.NotFound", "", nil),
+			expSentinel: ErrNotFound,
+		},
+		{
+			name:        "snapshot in use",
+			err:         awserr.New("InvalidSnapshot.InUse", "", nil),
+			expSentinel: ErrSnapshotInUse,
+		},
+		{
+			name:        "modification rate exceeded",
+			err:         awserr.New("VolumeModificationRateExceeded", "", nil),
+			expSentinel: ErrModificationRateLimited,
+		},
+		{
+			name: "unmapped code",
+			err:  awserr.New("InvalidParameterValue", "", nil),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := newCloudError(tc.err, "could not do it")
+
+			var cloudErr *CloudError
+			if !errors.As(err, &cloudErr) {
+				t.Fatalf("expected a *CloudError, got: %T", err)
+			}
+			if cloudErr.Code() != tc.err.(awserr.Error).Code() {
+				t.Fatalf("expected code %q, got %q", tc.err.(awserr.Error).Code(), cloudErr.Code())
+			}
+			if !errors.Is(err, tc.err) {
+				t.Fatal("expected the error to unwrap to the AWS error")
+			}
+			for _, sentinel := range []error{ErrNotFound, ErrSnapshotInUse, ErrModificationRateLimited} {
+				if is := errors.Is(err, sentinel); is != (sentinel == tc.expSentinel) {
+					t.Fatalf("expected errors.Is(%v) to be %t", sentinel, !is)
+				}
+			}
+		})
+	}
+
+	// Other errors are wrapped as is
+	cause := errors.New("generic error")
+	if err := newCloudError(cause, "could not do it"); !errors.Is(err, cause) || err.Error() != "could not do it: generic error" {
+		t.Fatalf("expected wrapped generic error, got: %v", err)
+	}
+}
+
+func TestAttachDiskCloudError(t *testing.T) {
+	volumeID := "vol-000000aa"
+	nodeID := "node-1234"
+
+	mockCtrl := gomock.NewController(t)
+	mockEC2 := mocks.NewMockEC2(mockCtrl)
+	c := newCloud(mockEC2)
+
+	ctx := context.Background()
+	mockEC2.EXPECT().DescribeInstancesWithContext(gomock.Eq(ctx), gomock.Any()).Return(newDescribeInstancesOutput(nodeID), nil)
+	mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{{VolumeId: aws.String(volumeID)}}}, nil)
+	mockEC2.EXPECT().AttachVolumeWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).Return(nil, awserr.New("InvalidInstanceID.NotFound", "", nil))
+
+	_, err := c.AttachDisk(ctx, volumeID, nodeID)
+	var cloudErr *CloudError
+	if !errors.As(err, &cloudErr) || cloudErr.Code() != "InvalidInstanceID.NotFound" {
+		t.Fatalf("AttachDisk() failed: expected a *CloudError with code InvalidInstanceID.NotFound, got: %v", err)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("AttachDisk() failed: expected error matching %v, got: %v", ErrNotFound, err)
+	}
+
+	mockCtrl.Finish()
+}
+
+func TestWrappedErrors(t *testing.T) {
+	volumeID := "vol-000000aa"
+	nodeID := "node-1234"
+	volumeNotFound := awserr.New("InvalidVolume.NotFound", "", nil)
+
+	testCases := []struct {
+		name        string
+		mock        func(mockEC2 *mocks.MockEC2)
+		call        func(c Cloud) error
+		expSentinel error
+		expCode     string
+	}{
+		{
+			name: "DeleteDisk",
+			mock: func(mockEC2 *mocks.MockEC2) {
+				mockEC2.EXPECT().DeleteVolumeWithContext(gomock.Any(), gomock.Any()).Return(nil, awserr.New("VolumeInUse", "", nil))
+			},
+			call: func(c Cloud) error {
+				_, err := c.DeleteDisk(context.Background(), volumeID)
+				return err
+			},
+			expCode: "VolumeInUse",
+		},
+		{
+			name: "DetachDisk",
+			mock: func(mockEC2 *mocks.MockEC2) {
+				mockEC2.EXPECT().DescribeInstancesWithContext(gomock.Any(), gomock.Any()).Return(newDescribeInstancesOutput(nodeID), nil)
+				mockEC2.EXPECT().DetachVolumeWithContext(gomock.Any(), gomock.Any()).Return(nil, awserr.New("UnauthorizedOperation", "", nil))
+			},
+			call: func(c Cloud) error {
+				return c.DetachDisk(context.Background(), volumeID, nodeID)
+			},
+			expCode: "UnauthorizedOperation",
+		},
+		{
+			name: "ResizeDisk",
+			mock: func(mockEC2 *mocks.MockEC2) {
+				volume := &ec2.Volume{VolumeId: aws.String(volumeID), Size: aws.Int64(1)}
+				mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Any(), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{volume}}, nil)
+				mockEC2.EXPECT().ModifyVolumeWithContext(gomock.Any(), gomock.Any()).Return(nil, volumeNotFound)
+			},
+			call: func(c Cloud) error {
+				_, err := c.ResizeDisk(context.Background(), volumeID, 2*util.GiB)
+				return err
+			},
+			expSentinel: ErrNotFound,
+			expCode:     "InvalidVolume.NotFound",
+		},
+		{
+			name: "ResizeDisk without modifications",
+			mock: func(mockEC2 *mocks.MockEC2) {
+				volume := &ec2.Volume{VolumeId: aws.String(volumeID), Size: aws.Int64(1)}
+				mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Any(), gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{volume}}, nil)
+				mockEC2.EXPECT().ModifyVolumeWithContext(gomock.Any(), gomock.Any()).Return(nil, awserr.New("IncorrectModificationState", "", nil))
+				mockEC2.EXPECT().DescribeVolumesModificationsWithContext(gomock.Any(), gomock.Any()).Return(&ec2.DescribeVolumesModificationsOutput{}, nil)
+			},
+			call: func(c Cloud) error {
+				_, err := c.ResizeDisk(context.Background(), volumeID, 2*util.GiB)
+				return err
+			},
+			expSentinel: ErrNotFound,
+		},
+		{
+			name: "CreateSnapshot",
+			mock: func(mockEC2 *mocks.MockEC2) {
+				mockEC2.EXPECT().CreateSnapshotWithContext(gomock.Any(), gomock.Any()).Return(nil, volumeNotFound)
+			},
+			call: func(c Cloud) error {
+				_, err := c.CreateSnapshot(context.Background(), volumeID, &SnapshotOptions{})
+				return err
+			},
+			expSentinel: ErrNotFound,
+			expCode:     "InvalidVolume.NotFound",
+		},
+		{
+			name: "CreateDisk",
+			mock: func(mockEC2 *mocks.MockEC2) {
+				mockEC2.EXPECT().CreateVolumeWithContext(gomock.Any(), gomock.Any()).Return(nil, awserr.New("VolumeLimitExceeded", "", nil))
+			},
+			call: func(c Cloud) error {
+				_, err := c.CreateDisk(context.Background(), "vol-000000ae", &DiskOptions{CapacityBytes: util.GiB, AvailabilityZone: expZone})
+				return err
+			},
+			expCode: "VolumeLimitExceeded",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2)
+			tc.mock(mockEC2)
+
+			err := tc.call(c)
+			if err == nil {
+				t.Fatalf("%s() failed: expected error, got nothing", tc.name)
+			}
+			if tc.expSentinel != nil && !errors.Is(err, tc.expSentinel) {
+				t.Fatalf("%s() failed: expected error matching %v, got: %v", tc.name, tc.expSentinel, err)
+			}
+			var cloudErr *CloudError
+			if tc.expCode != "" && (!errors.As(err, &cloudErr) || cloudErr.Code() != tc.expCode) {
+				t.Fatalf("%s() failed: expected a *CloudError with code %s, got: %v", tc.name, tc.expCode, err)
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
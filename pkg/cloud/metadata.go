@@ -17,14 +17,22 @@ limitations under the License.
 package cloud
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"k8s.io/klog"
 )
 
+// metadataTimeout bounds each call to the instance metadata service, so that
+// the region detection fails fast when not running on EC2
+const metadataTimeout = 2 * time.Second
+
 type EC2Metadata interface {
 	Available() bool
 	GetInstanceIdentityDocument() (ec2metadata.EC2InstanceIdentityDocument, error)
@@ -111,3 +119,30 @@ func NewMetadataService(svc EC2Metadata) (MetadataService, error) {
 		AvailabilityZone: doc.AvailabilityZone,
 	}, nil
 }
+
+// NewCloudFromMetadata returns a new instance of AWS cloud in the region of the
+// instance, as reported by the instance metadata service. It falls back to the
+// AWS_REGION environment variable when the metadata service is not reachable.
+func NewCloudFromMetadata(ctx context.Context, options ...func(*CloudOptions)) (Cloud, error) {
+	sess := session.Must(session.NewSession(&aws.Config{
+		HTTPClient: &http.Client{Timeout: metadataTimeout},
+		MaxRetries: aws.Int(1),
+	}))
+	// The client uses IMDSv2 session tokens, falling back to IMDSv1 if
+	// they're not supported
+	svc := ec2metadata.New(sess)
+	return newCloudFromMetadata(ctx, svc, options...)
+}
+
+func newCloudFromMetadata(ctx context.Context, svc *ec2metadata.EC2Metadata, options ...func(*CloudOptions)) (Cloud, error) {
+	region, err := svc.RegionWithContext(ctx)
+	if err != nil || len(region) == 0 {
+		region = os.Getenv("AWS_REGION")
+		if len(region) == 0 {
+			return nil, fmt.Errorf("could not get region from EC2 instance metadata or AWS_REGION: %v", err)
+		}
+		klog.Warningf("Could not get region from EC2 instance metadata, using AWS_REGION %q: %v", region, err)
+	}
+
+	return NewCloud(region, options...)
+}
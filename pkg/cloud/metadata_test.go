@@ -17,11 +17,16 @@ limitations under the License.
 package cloud
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/golang/mock/gomock"
 	"github.com/c2devel/aws-ebs-csi-driver/pkg/cloud/mocks"
 )
@@ -183,3 +188,78 @@ func TestNewMetadataService(t *testing.T) {
 		})
 	}
 }
+
+func TestNewCloudFromMetadata(t *testing.T) {
+	testCases := []struct {
+		name             string
+		metadataRegion   string
+		envRegion        string
+		expRegion        string
+		expErr           bool
+		expTokenRequests bool
+	}{
+		{
+			name:             "success: region from metadata",
+			metadataRegion:   "us-east-2",
+			envRegion:        "us-west-2",
+			expRegion:        "us-east-2",
+			expTokenRequests: true,
+		},
+		{
+			name:      "success: fall back to AWS_REGION",
+			envRegion: "us-west-2",
+			expRegion: "us-west-2",
+		},
+		{
+			name:   "fail: no region",
+			expErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tokenRequests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPut && r.URL.Path == "/latest/api/token":
+					tokenRequests++
+					w.Header().Set("X-Aws-Ec2-Metadata-Token-Ttl-Seconds", "21600")
+					fmt.Fprint(w, "token")
+				case r.URL.Path == "/latest/dynamic/instance-identity/document" && len(tc.metadataRegion) > 0:
+					if r.Header.Get("X-Aws-Ec2-Metadata-Token") != "token" {
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+					fmt.Fprintf(w, `{"region": %q}`, tc.metadataRegion)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			os.Setenv("AWS_REGION", tc.envRegion)
+			defer os.Unsetenv("AWS_REGION")
+
+			sess := session.Must(session.NewSession(&aws.Config{MaxRetries: aws.Int(0)}))
+			svc := ec2metadata.New(sess, &aws.Config{Endpoint: aws.String(server.URL)})
+
+			c, err := newCloudFromMetadata(context.Background(), svc)
+			if err != nil {
+				if !tc.expErr {
+					t.Fatalf("newCloudFromMetadata() failed: expected no error, got: %v", err)
+				}
+				return
+			}
+			if tc.expErr {
+				t.Fatal("newCloudFromMetadata() failed: expected error, got nothing")
+			}
+
+			if region := c.(*cloud).region; region != tc.expRegion {
+				t.Fatalf("expected region %q, got %q", tc.expRegion, region)
+			}
+			if tc.expTokenRequests && tokenRequests == 0 {
+				t.Fatal("expected an IMDSv2 session token to be requested")
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// metricsNamespace is the prefix of all the metrics exposed by the cloud provider
+	metricsNamespace = "ebs_csi_aws"
+	// successCode is the code label value of requests that didn't fail
+	successCode = "Success"
+)
+
+// apiMetrics records the count, latency and result code of the AWS API
+// requests, labeled by operation name (e.g. CreateVolume, DescribeVolumes).
+type apiMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+var _ prometheus.Collector = &apiMetrics{}
+
+// metrics is shared by all the clouds created in this process, so that
+// the collector can be registered once.
+var metrics = newAPIMetrics()
+
+func newAPIMetrics() *apiMetrics {
+	return &apiMetrics{
+		requests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: metricsNamespace,
+				Name:      "api_requests_total",
+				Help:      "Number of AWS API requests, by operation and result code.",
+			},
+			[]string{"request", "code"},
+		),
+		duration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: metricsNamespace,
+				Name:      "api_request_duration_seconds",
+				Help:      "Latency of AWS API requests in seconds, including retries, by operation.",
+				Buckets:   prometheus.ExponentialBuckets(0.05, 2, 10),
+			},
+			[]string{"request"},
+		),
+	}
+}
+
+// MetricsCollector returns the collector of the AWS API request metrics,
+// to be registered by the driver.
+func MetricsCollector() prometheus.Collector {
+	return metrics
+}
+
+func (m *apiMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.requests.Describe(ch)
+	m.duration.Describe(ch)
+}
+
+func (m *apiMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.requests.Collect(ch)
+	m.duration.Collect(ch)
+}
+
+// handler returns a request handler recording the metrics of every
+// completed request. It is meant for the Complete handler list of the
+// session, which runs once per request after all retries.
+func (m *apiMetrics) handler() request.NamedHandler {
+	return request.NamedHandler{
+		Name: "ebscsi.metrics",
+		Fn:   m.observe,
+	}
+}
+
+func (m *apiMetrics) observe(r *request.Request) {
+	operation := r.Operation.Name
+	m.requests.WithLabelValues(operation, errorCode(r.Error)).Inc()
+	m.duration.WithLabelValues(operation).Observe(time.Since(r.Time).Seconds())
+}
+
+// errorCode returns the AWS error code of err, or successCode if err is nil.
+func errorCode(err error) string {
+	if err == nil {
+		return successCode
+	}
+	if awsErr, ok := err.(awserr.Error); ok {
+		return awsErr.Code()
+	}
+	return "Unknown"
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func newTestRequest(operation string, err error) *request.Request {
+	r := request.New(aws.Config{}, metadata.ClientInfo{}, request.Handlers{}, nil, &request.Operation{Name: operation}, nil, nil)
+	r.Error = err
+	return r
+}
+
+func TestAPIMetrics(t *testing.T) {
+	m := newAPIMetrics()
+
+	requests := []*request.Request{
+		newTestRequest("DescribeVolumes", nil),
+		newTestRequest("DescribeVolumes", nil),
+		newTestRequest("DescribeVolumes", awserr.New("RequestLimitExceeded", "", nil)),
+		newTestRequest("AttachVolume", fmt.Errorf("generic error")),
+	}
+	for _, r := range requests {
+		m.observe(r)
+	}
+
+	testCases := []struct {
+		operation string
+		code      string
+		expCount  float64
+	}{
+		{operation: "DescribeVolumes", code: successCode, expCount: 2},
+		{operation: "DescribeVolumes", code: "RequestLimitExceeded", expCount: 1},
+		{operation: "AttachVolume", code: "Unknown", expCount: 1},
+		{operation: "CreateVolume", code: successCode, expCount: 0},
+	}
+	for _, tc := range testCases {
+		if count := testutil.ToFloat64(m.requests.WithLabelValues(tc.operation, tc.code)); count != tc.expCount {
+			t.Fatalf("expected %v %s requests with code %s, got %v", tc.expCount, tc.operation, tc.code, count)
+		}
+	}
+
+	registry := prometheus.NewPedanticRegistry()
+	if err := registry.Register(m); err != nil {
+		t.Fatalf("failed to register the metrics collector: %v", err)
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	if len(families) != 2 {
+		t.Fatalf("expected 2 metric families, got %d", len(families))
+	}
+}
@@ -0,0 +1,409 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+)
+
+// Page size limits accepted by the EC2 describe APIs
+const (
+	// MinDescribeMaxResults is the smallest page size accepted by the describe APIs
+	MinDescribeMaxResults = 5
+	// MaxDescribeVolumesMaxResults is the largest page size accepted by DescribeVolumes
+	MaxDescribeVolumesMaxResults = 500
+	// MaxDescribeSnapshotsMaxResults is the largest page size accepted by DescribeSnapshots
+	MaxDescribeSnapshotsMaxResults = 1000
+	// MaxDescribeInstancesMaxResults is the largest page size accepted by DescribeInstances
+	MaxDescribeInstancesMaxResults = 1000
+)
+
+// Default retries of the AttachVolume calls reporting the volume as not found
+const (
+	// DefaultAttachNotFoundRetries is the number of retries of NewCloud
+	DefaultAttachNotFoundRetries = 3
+	// DefaultAttachNotFoundRetryInterval is the interval between the retries
+	DefaultAttachNotFoundRetryInterval = 2 * time.Second
+)
+
+// MultiDiskStrategy is how a lookup resolves several volumes sharing the same name
+type MultiDiskStrategy string
+
+const (
+	// MultiDiskStrategyError fails the lookup with ErrMultiDisks
+	MultiDiskStrategyError MultiDiskStrategy = "error"
+	// MultiDiskStrategyOldest picks the volume created first
+	MultiDiskStrategyOldest MultiDiskStrategy = "oldest"
+	// MultiDiskStrategyNewest picks the volume created last
+	MultiDiskStrategyNewest MultiDiskStrategy = "newest"
+	// MultiDiskStrategyMatchingSize picks the volume created first among the
+	// ones of the requested size, for the lookups by name with a size. The
+	// other lookups pick the volume created first.
+	MultiDiskStrategyMatchingSize MultiDiskStrategy = "matching-size"
+)
+
+// CloudOptions holds the optional settings of the cloud provider.
+// The zero value keeps the AWS defaults.
+type CloudOptions struct {
+	// Page sizes of the paginated describes. Zero means unset, letting AWS
+	// choose. They are only sent when the request doesn't list explicit IDs,
+	// since AWS rejects MaxResults combined with IDs.
+	volumesMaxResults   int64
+	snapshotsMaxResults int64
+	instancesMaxResults int64
+
+	// Whether described volumes are cached, and for how long
+	volumeCacheEnabled bool
+	volumeCacheTTL     time.Duration
+
+	// Role to assume for the EC2 API calls, on top of the default credential
+	// chain. Default to the AWS_ASSUME_ROLE_ARN and AWS_ASSUME_ROLE_EXTERNAL_ID
+	// environment variables.
+	assumeRoleARN        string
+	assumeRoleExternalID string
+
+	// endpointResolver resolves the endpoints of the AWS services, instead of
+	// the default AWS resolver. The endpoints set with the AWS_EC2_ENDPOINT and
+	// AWS_STS_ENDPOINT environment variables take precedence over it.
+	endpointResolver endpoints.Resolver
+
+	// useFIPSEndpoint makes the resolvers pick the FIPS 140-2 validated
+	// endpoints of the region. It doesn't apply to the services with a custom
+	// endpoint, e.g. with AWS_EC2_ENDPOINT set, the EC2 calls go to that
+	// endpoint as is, which must then be a FIPS endpoint itself if required.
+	// Default to the AWS_USE_FIPS_ENDPOINT environment variable.
+	useFIPSEndpoint bool
+
+	// multiDiskStrategy resolves name collisions left behind by idempotency
+	// races. Default to MultiDiskStrategyError.
+	multiDiskStrategy MultiDiskStrategy
+
+	// volumeTypeAliases maps friendly volume type names to volume types, on
+	// top of DefaultVolumeTypeAliases.
+	volumeTypeAliases map[string]string
+
+	// autoVolumeTypeThreshold is the size in bytes from which the volumes of
+	// type auto are created as st2 volumes rather than gp2 volumes. Default to
+	// DefaultAutoVolumeTypeThreshold.
+	autoVolumeTypeThreshold int64
+
+	// operationTimeout bounds the calls to the Cloud methods taking a
+	// context, i.e. all of them but IsThrottled, ThrottleRate and Close,
+	// when the context has no deadline. It covers the whole call, including
+	// the waits for volumes to be created, attached, detached or resized.
+	// Zero disables it.
+	operationTimeout time.Duration
+
+	// skipSnapshotPreChecks makes CreateDisk send the creation of a volume
+	// from a snapshot without checking the snapshot first, saving a
+	// DescribeSnapshots call. EC2 then rejects the invalid requests itself,
+	// e.g. an undersized volume, with a less specific error.
+	skipSnapshotPreChecks bool
+
+	// includeSharedSnapshots makes ListSnapshots return the public snapshots
+	// and the ones shared with the account too, not only the snapshots owned
+	// by the account.
+	includeSharedSnapshots bool
+
+	// zoneBalancingEnabled makes CreateDisk create the volumes without an
+	// availability zone in the zone with the fewest driver volumes, rather
+	// than in any zone of the region. The counts per zone are kept for
+	// zoneBalancingTTL, default to DefaultZoneVolumeCountsTTL.
+	zoneBalancingEnabled bool
+	zoneBalancingTTL     time.Duration
+
+	// requireExplicitAZ makes CreateDisk fail when no availability zone is
+	// given, instead of picking one of the region, which may not be reachable
+	// by the workload using the volume.
+	requireExplicitAZ bool
+
+	// circuitBreakerThreshold is the number of consecutive failures of an
+	// AWS API operation after which its calls fail fast with ErrCircuitOpen
+	// for circuitBreakerCooldown, default to DefaultCircuitBreakerCooldown.
+	// Zero disables the circuit breakers.
+	circuitBreakerThreshold int
+	circuitBreakerCooldown  time.Duration
+
+	// attachNotFoundRetries is how many times AttachDisk sends AttachVolume
+	// again when EC2 reports the volume as not found, as a volume just
+	// created may not be visible yet, every attachNotFoundRetryInterval,
+	// default to DefaultAttachNotFoundRetryInterval. Default to
+	// DefaultAttachNotFoundRetries, zero disables the retries.
+	attachNotFoundRetries       int
+	attachNotFoundRetryInterval time.Duration
+
+	// instanceRunningTimeout is how long AttachDisk waits for a pending
+	// instance to be running before attaching. Zero disables the wait, the
+	// attach is then sent to the pending instance as is.
+	instanceRunningTimeout time.Duration
+
+	// extraTags are added to the tags of all the volumes and snapshots
+	// created, the tags given to CreateDisk and CreateSnapshot winning on key
+	// collisions.
+	extraTags map[string]string
+
+	// instanceClusterTagKey restricts the instances the volumes are attached
+	// to, detached from or looked up on to the ones with this tag, with the
+	// value instanceClusterTagValue if not empty. The other instances are
+	// reported as not found, so that a shared account can't get volumes
+	// attached to the instances of another cluster.
+	instanceClusterTagKey   string
+	instanceClusterTagValue string
+}
+
+// defaultCloudOptions returns the options the constructors start from, before
+// the ones read from the environment and the ones given.
+func defaultCloudOptions() CloudOptions {
+	return CloudOptions{
+		attachNotFoundRetries: DefaultAttachNotFoundRetries,
+	}
+}
+
+func ValidateCloudOptions(options *CloudOptions) error {
+	if err := validateMaxResults(options.volumesMaxResults, MaxDescribeVolumesMaxResults); err != nil {
+		return fmt.Errorf("Invalid DescribeVolumes page size: %v", err)
+	}
+
+	if err := validateMaxResults(options.snapshotsMaxResults, MaxDescribeSnapshotsMaxResults); err != nil {
+		return fmt.Errorf("Invalid DescribeSnapshots page size: %v", err)
+	}
+
+	if err := validateMaxResults(options.instancesMaxResults, MaxDescribeInstancesMaxResults); err != nil {
+		return fmt.Errorf("Invalid DescribeInstances page size: %v", err)
+	}
+
+	if options.volumeCacheTTL < 0 {
+		return fmt.Errorf("Invalid volume cache TTL: must not be negative (actual: %v)", options.volumeCacheTTL)
+	}
+
+	if err := validateVolumeTypeAliases(options.volumeTypeAliases); err != nil {
+		return fmt.Errorf("Invalid volume type aliases: %v", err)
+	}
+
+	if options.zoneBalancingTTL < 0 {
+		return fmt.Errorf("Invalid zone balancing TTL: must not be negative (actual: %v)", options.zoneBalancingTTL)
+	}
+
+	if options.autoVolumeTypeThreshold < 0 {
+		return fmt.Errorf("Invalid auto volume type threshold: must not be negative (actual: %d)", options.autoVolumeTypeThreshold)
+	}
+
+	if options.operationTimeout < 0 {
+		return fmt.Errorf("Invalid operation timeout: must not be negative (actual: %v)", options.operationTimeout)
+	}
+
+	if options.circuitBreakerThreshold < 0 {
+		return fmt.Errorf("Invalid circuit breaker threshold: must not be negative (actual: %d)", options.circuitBreakerThreshold)
+	}
+
+	if options.circuitBreakerCooldown < 0 {
+		return fmt.Errorf("Invalid circuit breaker cooldown: must not be negative (actual: %v)", options.circuitBreakerCooldown)
+	}
+
+	if options.attachNotFoundRetries < 0 {
+		return fmt.Errorf("Invalid attach not found retries: must not be negative (actual: %d)", options.attachNotFoundRetries)
+	}
+
+	if options.attachNotFoundRetryInterval < 0 {
+		return fmt.Errorf("Invalid attach not found retry interval: must not be negative (actual: %v)", options.attachNotFoundRetryInterval)
+	}
+
+	if options.instanceRunningTimeout < 0 {
+		return fmt.Errorf("Invalid instance running timeout: must not be negative (actual: %v)", options.instanceRunningTimeout)
+	}
+
+	if err := validateTags(options.extraTags); err != nil {
+		return fmt.Errorf("Invalid extra tags: %v", err)
+	}
+
+	if err := validateMultiDiskStrategy(options.multiDiskStrategy); err != nil {
+		return fmt.Errorf("Invalid multi disk strategy: %v", err)
+	}
+
+	if options.instanceClusterTagValue != "" && options.instanceClusterTagKey == "" {
+		return fmt.Errorf("Invalid instance cluster tag: value %q set without a key", options.instanceClusterTagValue)
+	}
+
+	if options.assumeRoleExternalID != "" && options.assumeRoleARN == "" {
+		return fmt.Errorf("Invalid assume role options: external ID %q set without a role ARN", options.assumeRoleExternalID)
+	}
+
+	return nil
+}
+
+func validateMaxResults(maxResults, limit int64) error {
+	if maxResults == 0 {
+		return nil
+	}
+	if maxResults < MinDescribeMaxResults || maxResults > limit {
+		return fmt.Errorf("Page size out of range (actual: %d, min: %d, max: %d)", maxResults, MinDescribeMaxResults, limit)
+	}
+
+	return nil
+}
+
+func validateMultiDiskStrategy(strategy MultiDiskStrategy) error {
+	switch strategy {
+	case "", MultiDiskStrategyError, MultiDiskStrategyOldest, MultiDiskStrategyNewest, MultiDiskStrategyMatchingSize:
+		return nil
+	}
+	return fmt.Errorf("Strategy is not supported (actual: %s, supported: %v)", strategy, []MultiDiskStrategy{MultiDiskStrategyError, MultiDiskStrategyOldest, MultiDiskStrategyNewest, MultiDiskStrategyMatchingSize})
+}
+
+func validateVolumeTypeAliases(aliases map[string]string) error {
+	for alias, volumeType := range aliases {
+		if !isValidVolumeType(volumeType) {
+			return fmt.Errorf("Alias %q of unsupported volume type (actual: %s, supported: %v)", alias, volumeType, ValidVolumeTypes)
+		}
+	}
+
+	return nil
+}
+
+func WithVolumesMaxResults(maxResults int64) func(*CloudOptions) {
+	return func(o *CloudOptions) {
+		o.volumesMaxResults = maxResults
+	}
+}
+
+func WithSnapshotsMaxResults(maxResults int64) func(*CloudOptions) {
+	return func(o *CloudOptions) {
+		o.snapshotsMaxResults = maxResults
+	}
+}
+
+func WithInstancesMaxResults(maxResults int64) func(*CloudOptions) {
+	return func(o *CloudOptions) {
+		o.instancesMaxResults = maxResults
+	}
+}
+
+func WithVolumeCache(enabled bool) func(*CloudOptions) {
+	return func(o *CloudOptions) {
+		o.volumeCacheEnabled = enabled
+	}
+}
+
+func WithVolumeCacheTTL(ttl time.Duration) func(*CloudOptions) {
+	return func(o *CloudOptions) {
+		o.volumeCacheTTL = ttl
+	}
+}
+
+func WithAssumeRole(roleARN, externalID string) func(*CloudOptions) {
+	return func(o *CloudOptions) {
+		o.assumeRoleARN = roleARN
+		o.assumeRoleExternalID = externalID
+	}
+}
+
+func WithEndpointResolver(resolver endpoints.Resolver) func(*CloudOptions) {
+	return func(o *CloudOptions) {
+		o.endpointResolver = resolver
+	}
+}
+
+func WithFIPSEndpoint(enabled bool) func(*CloudOptions) {
+	return func(o *CloudOptions) {
+		o.useFIPSEndpoint = enabled
+	}
+}
+
+func WithMultiDiskStrategy(strategy MultiDiskStrategy) func(*CloudOptions) {
+	return func(o *CloudOptions) {
+		o.multiDiskStrategy = strategy
+	}
+}
+
+func WithInstanceRunningWait(timeout time.Duration) func(*CloudOptions) {
+	return func(o *CloudOptions) {
+		o.instanceRunningTimeout = timeout
+	}
+}
+
+func WithAttachNotFoundRetries(retries int, interval time.Duration) func(*CloudOptions) {
+	return func(o *CloudOptions) {
+		o.attachNotFoundRetries = retries
+		o.attachNotFoundRetryInterval = interval
+	}
+}
+
+func WithVolumeTypeAliases(aliases map[string]string) func(*CloudOptions) {
+	return func(o *CloudOptions) {
+		o.volumeTypeAliases = aliases
+	}
+}
+
+func WithOperationTimeout(timeout time.Duration) func(*CloudOptions) {
+	return func(o *CloudOptions) {
+		o.operationTimeout = timeout
+	}
+}
+
+func WithCircuitBreaker(threshold int, cooldown time.Duration) func(*CloudOptions) {
+	return func(o *CloudOptions) {
+		o.circuitBreakerThreshold = threshold
+		o.circuitBreakerCooldown = cooldown
+	}
+}
+
+func WithSkipSnapshotPreChecks(skip bool) func(*CloudOptions) {
+	return func(o *CloudOptions) {
+		o.skipSnapshotPreChecks = skip
+	}
+}
+
+func WithSharedSnapshots(enabled bool) func(*CloudOptions) {
+	return func(o *CloudOptions) {
+		o.includeSharedSnapshots = enabled
+	}
+}
+
+func WithRequireExplicitAZ(require bool) func(*CloudOptions) {
+	return func(o *CloudOptions) {
+		o.requireExplicitAZ = require
+	}
+}
+
+func WithZoneBalancing(enabled bool, ttl time.Duration) func(*CloudOptions) {
+	return func(o *CloudOptions) {
+		o.zoneBalancingEnabled = enabled
+		o.zoneBalancingTTL = ttl
+	}
+}
+
+func WithAutoVolumeTypeThreshold(thresholdBytes int64) func(*CloudOptions) {
+	return func(o *CloudOptions) {
+		o.autoVolumeTypeThreshold = thresholdBytes
+	}
+}
+
+func WithExtraTags(tags map[string]string) func(*CloudOptions) {
+	return func(o *CloudOptions) {
+		o.extraTags = tags
+	}
+}
+
+func WithInstanceClusterTag(key, value string) func(*CloudOptions) {
+	return func(o *CloudOptions) {
+		o.instanceClusterTagKey = key
+		o.instanceClusterTagValue = value
+	}
+}
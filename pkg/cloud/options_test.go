@@ -0,0 +1,379 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/c2devel/aws-ebs-csi-driver/pkg/util"
+)
+
+func TestValidateCloudOptions(t *testing.T) {
+	testCases := []struct {
+		name    string
+		options []func(*CloudOptions)
+		expErr  bool
+	}{
+		{
+			name: "success: defaults",
+		},
+		{
+			name: "success: page sizes within limits",
+			options: []func(*CloudOptions){
+				WithVolumesMaxResults(MaxDescribeVolumesMaxResults),
+				WithSnapshotsMaxResults(MinDescribeMaxResults),
+				WithInstancesMaxResults(MaxDescribeInstancesMaxResults),
+			},
+		},
+		{
+			name:    "fail: volumes page size too large",
+			options: []func(*CloudOptions){WithVolumesMaxResults(MaxDescribeVolumesMaxResults + 1)},
+			expErr:  true,
+		},
+		{
+			name:    "fail: snapshots page size too large",
+			options: []func(*CloudOptions){WithSnapshotsMaxResults(MaxDescribeSnapshotsMaxResults + 1)},
+			expErr:  true,
+		},
+		{
+			name:    "success: assume role with external ID",
+			options: []func(*CloudOptions){WithAssumeRole("arn:aws:iam::012345678910:role/ebs-csi", "external-id")},
+		},
+		{
+			name:    "success: multi disk strategy",
+			options: []func(*CloudOptions){WithMultiDiskStrategy(MultiDiskStrategyOldest)},
+		},
+		{
+			name:    "success: matching size multi disk strategy",
+			options: []func(*CloudOptions){WithMultiDiskStrategy(MultiDiskStrategyMatchingSize)},
+		},
+		{
+			name:    "fail: unknown multi disk strategy",
+			options: []func(*CloudOptions){WithMultiDiskStrategy("largest")},
+			expErr:  true,
+		},
+		{
+			name:    "success: instance running wait",
+			options: []func(*CloudOptions){WithInstanceRunningWait(time.Minute)},
+		},
+		{
+			name:    "fail: negative instance running timeout",
+			options: []func(*CloudOptions){WithInstanceRunningWait(-time.Second)},
+			expErr:  true,
+		},
+		{
+			name:    "success: attach not found retries",
+			options: []func(*CloudOptions){WithAttachNotFoundRetries(5, time.Second)},
+		},
+		{
+			name:    "fail: negative attach not found retries",
+			options: []func(*CloudOptions){WithAttachNotFoundRetries(-1, time.Second)},
+			expErr:  true,
+		},
+		{
+			name:    "fail: negative attach not found retry interval",
+			options: []func(*CloudOptions){WithAttachNotFoundRetries(1, -time.Second)},
+			expErr:  true,
+		},
+		{
+			name:    "success: volume type aliases",
+			options: []func(*CloudOptions){WithVolumeTypeAliases(map[string]string{"fast": VolumeTypeIO2})},
+		},
+		{
+			name:    "fail: alias of unsupported volume type",
+			options: []func(*CloudOptions){WithVolumeTypeAliases(map[string]string{"fast": "gp9"})},
+			expErr:  true,
+		},
+		{
+			name:    "success: extra tags",
+			options: []func(*CloudOptions){WithExtraTags(map[string]string{"cost-center": "storage"})},
+		},
+		{
+			name:    "fail: extra tag with reserved prefix",
+			options: []func(*CloudOptions){WithExtraTags(map[string]string{"aws:cost-center": "storage"})},
+			expErr:  true,
+		},
+		{
+			name:    "success: instance cluster tag",
+			options: []func(*CloudOptions){WithInstanceClusterTag("kubernetes.io/cluster/demo", "owned")},
+		},
+		{
+			name:    "fail: instance cluster tag value without a key",
+			options: []func(*CloudOptions){WithInstanceClusterTag("", "owned")},
+			expErr:  true,
+		},
+		{
+			name:    "success: operation timeout",
+			options: []func(*CloudOptions){WithOperationTimeout(time.Minute)},
+		},
+		{
+			name:    "fail: negative operation timeout",
+			options: []func(*CloudOptions){WithOperationTimeout(-time.Second)},
+			expErr:  true,
+		},
+		{
+			name:    "success: circuit breaker",
+			options: []func(*CloudOptions){WithCircuitBreaker(5, time.Minute)},
+		},
+		{
+			name:    "fail: negative circuit breaker threshold",
+			options: []func(*CloudOptions){WithCircuitBreaker(-1, time.Minute)},
+			expErr:  true,
+		},
+		{
+			name:    "fail: negative circuit breaker cooldown",
+			options: []func(*CloudOptions){WithCircuitBreaker(5, -time.Second)},
+			expErr:  true,
+		},
+		{
+			name:    "success: auto volume type threshold",
+			options: []func(*CloudOptions){WithAutoVolumeTypeThreshold(500 * util.GiB)},
+		},
+		{
+			name:    "fail: negative auto volume type threshold",
+			options: []func(*CloudOptions){WithAutoVolumeTypeThreshold(-1)},
+			expErr:  true,
+		},
+		{
+			name:    "success: zone balancing",
+			options: []func(*CloudOptions){WithZoneBalancing(true, time.Minute)},
+		},
+		{
+			name:    "fail: negative zone balancing TTL",
+			options: []func(*CloudOptions){WithZoneBalancing(true, -time.Second)},
+			expErr:  true,
+		},
+		{
+			name:    "fail: external ID without role",
+			options: []func(*CloudOptions){WithAssumeRole("", "external-id")},
+			expErr:  true,
+		},
+		{
+			name:    "fail: instances page size too small",
+			options: []func(*CloudOptions){WithInstancesMaxResults(MinDescribeMaxResults - 1)},
+			expErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			options := CloudOptions{}
+			for _, option := range tc.options {
+				option(&options)
+			}
+
+			err := ValidateCloudOptions(&options)
+			if tc.expErr && err == nil {
+				t.Fatal("ValidateCloudOptions() failed: expected error, got nothing")
+			}
+			if !tc.expErr && err != nil {
+				t.Fatalf("ValidateCloudOptions() failed: expected no error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestNewCloudAssumeRole(t *testing.T) {
+	const (
+		region   = "test-region"
+		endpoint = "https://ec2.example.com"
+		roleARN  = "arn:aws:iam::012345678910:role/ebs-csi"
+	)
+
+	os.Setenv("AWS_EC2_ENDPOINT", endpoint)
+	defer os.Unsetenv("AWS_EC2_ENDPOINT")
+	os.Setenv("AWS_ASSUME_ROLE_ARN", roleARN)
+	defer os.Unsetenv("AWS_ASSUME_ROLE_ARN")
+
+	c, err := NewCloud(region)
+	if err != nil {
+		t.Fatalf("NewCloud() failed: expected no error, got: %v", err)
+	}
+
+	ec2Client, ok := c.(*cloud).ec2.(*ec2.EC2)
+	if !ok {
+		t.Fatalf("expected EC2 client, got %T", c.(*cloud).ec2)
+	}
+	if got := aws.StringValue(ec2Client.Config.Region); got != region {
+		t.Fatalf("expected region %q, got %q", region, got)
+	}
+	if got := ec2Client.Endpoint; got != endpoint {
+		t.Fatalf("expected endpoint %q, got %q", endpoint, got)
+	}
+	if c.(*cloud).options.assumeRoleARN != roleARN {
+		t.Fatalf("expected role %q, got %q", roleARN, c.(*cloud).options.assumeRoleARN)
+	}
+
+	// The external ID is useless without a role to assume
+	os.Unsetenv("AWS_ASSUME_ROLE_ARN")
+	if _, err := NewCloud(region, WithAssumeRole("", "external-id")); err == nil {
+		t.Fatal("NewCloud() failed: expected error, got nothing")
+	}
+}
+
+func TestNewEndpointResolver(t *testing.T) {
+	fallback := endpoints.ResolverFunc(func(service, region string, opts ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
+		return endpoints.ResolvedEndpoint{URL: "https://" + service + ".fallback.example.com"}, nil
+	})
+
+	testCases := []struct {
+		name        string
+		custom      map[string]string
+		fallback    endpoints.Resolver
+		service     string
+		expEndpoint string
+	}{
+		{
+			name:        "custom endpoint",
+			custom:      map[string]string{"ec2": "https://ec2.example.com", "sts": "https://sts.example.com"},
+			service:     "sts",
+			expEndpoint: "https://sts.example.com",
+		},
+		{
+			name:        "custom endpoint takes precedence over the fallback resolver",
+			custom:      map[string]string{"ec2": "https://ec2.example.com"},
+			fallback:    fallback,
+			service:     "ec2",
+			expEndpoint: "https://ec2.example.com",
+		},
+		{
+			name:        "fallback resolver",
+			custom:      map[string]string{"ec2": "https://ec2.example.com"},
+			fallback:    fallback,
+			service:     "sts",
+			expEndpoint: "https://sts.fallback.example.com",
+		},
+		{
+			name:        "default resolver",
+			custom:      map[string]string{},
+			service:     "ec2",
+			expEndpoint: "https://ec2.us-west-2.amazonaws.com",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resolved, err := newEndpointResolver(tc.custom, tc.fallback).EndpointFor(tc.service, "us-west-2")
+			if err != nil {
+				t.Fatalf("EndpointFor() failed: expected no error, got: %v", err)
+			}
+			if resolved.URL != tc.expEndpoint {
+				t.Fatalf("EndpointFor() failed: expected endpoint %q, got %q", tc.expEndpoint, resolved.URL)
+			}
+		})
+	}
+}
+
+func TestNewCloudEndpointResolver(t *testing.T) {
+	const endpoint = "https://ec2.resolver.example.com"
+
+	resolver := endpoints.ResolverFunc(func(service, region string, opts ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
+		return endpoints.ResolvedEndpoint{URL: endpoint, SigningRegion: region}, nil
+	})
+
+	c, err := NewCloud("test-region", WithEndpointResolver(resolver))
+	if err != nil {
+		t.Fatalf("NewCloud() failed: expected no error, got: %v", err)
+	}
+
+	if got := c.(*cloud).ec2.(*ec2.EC2).Endpoint; got != endpoint {
+		t.Fatalf("expected endpoint %q, got %q", endpoint, got)
+	}
+}
+
+func TestNewCloudFIPSEndpoint(t *testing.T) {
+	testCases := []struct {
+		name        string
+		region      string
+		envEndpoint string
+		envFIPS     string
+		options     []func(*CloudOptions)
+		expEndpoint string
+		expErr      bool
+	}{
+		{
+			name:        "FIPS disabled",
+			region:      "us-west-2",
+			expEndpoint: "https://ec2.us-west-2.amazonaws.com",
+		},
+		{
+			name:        "FIPS enabled by option",
+			region:      "us-west-2",
+			options:     []func(*CloudOptions){WithFIPSEndpoint(true)},
+			expEndpoint: "https://ec2-fips.us-west-2.amazonaws.com",
+		},
+		{
+			name:        "FIPS enabled by environment",
+			region:      "us-gov-west-1",
+			envFIPS:     "true",
+			expEndpoint: "https://ec2.us-gov-west-1.amazonaws.com",
+		},
+		{
+			name:        "option takes precedence over the environment",
+			region:      "us-west-2",
+			envFIPS:     "true",
+			options:     []func(*CloudOptions){WithFIPSEndpoint(false)},
+			expEndpoint: "https://ec2.us-west-2.amazonaws.com",
+		},
+		{
+			name:        "custom endpoint takes precedence over FIPS",
+			region:      "us-west-2",
+			envEndpoint: "https://ec2.example.com",
+			options:     []func(*CloudOptions){WithFIPSEndpoint(true)},
+			expEndpoint: "https://ec2.example.com",
+		},
+		{
+			name:    "invalid environment",
+			region:  "us-west-2",
+			envFIPS: "maybe",
+			expErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			os.Setenv("AWS_EC2_ENDPOINT", tc.envEndpoint)
+			defer os.Unsetenv("AWS_EC2_ENDPOINT")
+			os.Setenv("AWS_USE_FIPS_ENDPOINT", tc.envFIPS)
+			defer os.Unsetenv("AWS_USE_FIPS_ENDPOINT")
+
+			c, err := NewCloud(tc.region, tc.options...)
+			if err != nil {
+				if !tc.expErr {
+					t.Fatalf("NewCloud() failed: expected no error, got: %v", err)
+				}
+				return
+			}
+			if tc.expErr {
+				t.Fatal("NewCloud() failed: expected error, got nothing")
+			}
+
+			ec2Client := c.(*cloud).ec2.(*ec2.EC2)
+			if got := aws.StringValue(ec2Client.Config.Region); got != tc.region {
+				t.Fatalf("expected region %q, got %q", tc.region, got)
+			}
+			if got := ec2Client.Endpoint; got != tc.expEndpoint {
+				t.Fatalf("expected endpoint %q, got %q", tc.expEndpoint, got)
+			}
+		})
+	}
+}
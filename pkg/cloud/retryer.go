@@ -0,0 +1,88 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// retryAfterRetryer is the default AWS retryer, except that the retries of
+// throttled requests wait for the delay hinted by the Retry-After header of
+// the response, when there is one, instead of the exponential backoff.
+// The default retryer only honors the header for the 429 and 503 status
+// codes, while EC2 throttles with 400 RequestLimitExceeded errors.
+type retryAfterRetryer struct {
+	client.DefaultRetryer
+
+	// now is overridden in unit tests
+	now func() time.Time
+}
+
+func newRetryAfterRetryer() retryAfterRetryer {
+	return retryAfterRetryer{
+		DefaultRetryer: client.DefaultRetryer{
+			NumMaxRetries:    client.DefaultRetryerMaxNumRetries,
+			MinThrottleDelay: client.DefaultRetryerMinThrottleDelay,
+			MaxThrottleDelay: client.DefaultRetryerMaxThrottleDelay,
+		},
+		now: time.Now,
+	}
+}
+
+// RetryRules returns the delay before the next retry of the request.
+func (r retryAfterRetryer) RetryRules(req *request.Request) time.Duration {
+	if delay, ok := r.retryAfterDelay(req); ok {
+		return delay
+	}
+	return r.DefaultRetryer.RetryRules(req)
+}
+
+// retryAfterDelay returns the delay hinted by the Retry-After header of a
+// throttled request, either in seconds or as an HTTP date, capped to the
+// maximum throttle delay.
+func (r retryAfterRetryer) retryAfterDelay(req *request.Request) (time.Duration, bool) {
+	if !request.IsErrorThrottle(req.Error) || req.HTTPResponse == nil {
+		return 0, false
+	}
+
+	hint := req.HTTPResponse.Header.Get("Retry-After")
+	if hint == "" {
+		return 0, false
+	}
+
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(hint); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(hint); err == nil {
+		delay = date.Sub(r.now())
+	} else {
+		return 0, false
+	}
+
+	if delay < 0 {
+		delay = 0
+	}
+	if delay > r.MaxThrottleDelay {
+		delay = r.MaxThrottleDelay
+	}
+	return delay, true
+}
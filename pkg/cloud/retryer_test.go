@@ -0,0 +1,108 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+)
+
+func TestRetryAfterRetryer(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	throttleErr := awserr.NewRequestFailure(awserr.New("RequestLimitExceeded", "", nil), 400, "")
+
+	testCases := []struct {
+		name       string
+		err        error
+		retryAfter string
+		expDelay   time.Duration
+		expBackoff bool
+	}{
+		{
+			name:       "hint in seconds",
+			err:        throttleErr,
+			retryAfter: "7",
+			expDelay:   7 * time.Second,
+		},
+		{
+			name:       "hint as a date",
+			err:        throttleErr,
+			retryAfter: now.Add(12 * time.Second).Format(http.TimeFormat),
+			expDelay:   12 * time.Second,
+		},
+		{
+			name:       "hint in the past",
+			err:        throttleErr,
+			retryAfter: now.Add(-time.Minute).Format(http.TimeFormat),
+			expDelay:   0,
+		},
+		{
+			name:       "hint capped to the maximum throttle delay",
+			err:        throttleErr,
+			retryAfter: "3600",
+			expDelay:   client.DefaultRetryerMaxThrottleDelay,
+		},
+		{
+			name:       "no hint",
+			err:        throttleErr,
+			expBackoff: true,
+		},
+		{
+			name:       "invalid hint",
+			err:        throttleErr,
+			retryAfter: "soon",
+			expBackoff: true,
+		},
+		{
+			name:       "hint of a request not throttled",
+			err:        fmt.Errorf("connection reset"),
+			retryAfter: "7",
+			expBackoff: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			retryer := newRetryAfterRetryer()
+			retryer.now = func() time.Time { return now }
+
+			r := newTestRequest("DescribeVolumes", tc.err)
+			r.HTTPResponse = &http.Response{StatusCode: 400, Header: http.Header{}}
+			if tc.retryAfter != "" {
+				r.HTTPResponse.Header.Set("Retry-After", tc.retryAfter)
+			}
+
+			delay := retryer.RetryRules(r)
+			if tc.expBackoff {
+				// The exponential backoff of the first retry is below twice
+				// the minimum throttle delay
+				if delay > 2*client.DefaultRetryerMinThrottleDelay {
+					t.Fatalf("expected exponential backoff delay, got %v", delay)
+				}
+				return
+			}
+			if delay != tc.expDelay {
+				t.Fatalf("expected delay %v, got %v", tc.expDelay, delay)
+			}
+		})
+	}
+}
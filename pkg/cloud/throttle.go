@@ -0,0 +1,107 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+const (
+	// throttleWindow is the period over which the throttle rate is computed
+	throttleWindow = 1 * time.Minute
+	// throttledRateThreshold is the throttle rate above which the cloud
+	// reports itself as throttled
+	throttledRateThreshold = 0.1
+)
+
+// throttleTracker keeps the outcome of the AWS API request attempts made
+// over a sliding window, to compute the share of them that were throttled.
+type throttleTracker struct {
+	mux      sync.Mutex
+	window   time.Duration
+	attempts []throttleAttempt
+
+	// now is overridden in unit tests
+	now func() time.Time
+}
+
+type throttleAttempt struct {
+	time      time.Time
+	throttled bool
+}
+
+func newThrottleTracker(window time.Duration) *throttleTracker {
+	return &throttleTracker{
+		window: window,
+		now:    time.Now,
+	}
+}
+
+// handler returns a request handler recording every attempt of a request,
+// so that throttled attempts are counted even if a retry later succeeds.
+// It is meant for the CompleteAttempt handler list of the session.
+func (t *throttleTracker) handler() request.NamedHandler {
+	return request.NamedHandler{
+		Name: "ebscsi.throttle",
+		Fn: func(r *request.Request) {
+			t.record(request.IsErrorThrottle(r.Error))
+		},
+	}
+}
+
+func (t *throttleTracker) record(throttled bool) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	now := t.now()
+	t.prune(now)
+	t.attempts = append(t.attempts, throttleAttempt{time: now, throttled: throttled})
+}
+
+// rate returns the share of the attempts within the window that were
+// throttled, between 0 and 1.
+func (t *throttleTracker) rate() float64 {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	t.prune(t.now())
+	if len(t.attempts) == 0 {
+		return 0
+	}
+
+	throttled := 0
+	for _, a := range t.attempts {
+		if a.throttled {
+			throttled++
+		}
+	}
+	return float64(throttled) / float64(len(t.attempts))
+}
+
+// prune drops the attempts that fell out of the window. Must be called with
+// the lock held.
+func (t *throttleTracker) prune(now time.Time) {
+	cutoff := now.Add(-t.window)
+	i := 0
+	for i < len(t.attempts) && !t.attempts[i].time.After(cutoff) {
+		i++
+	}
+	t.attempts = t.attempts[i:]
+}
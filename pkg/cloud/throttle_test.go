@@ -0,0 +1,71 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+func TestThrottleRate(t *testing.T) {
+	now := time.Now()
+	tracker := newThrottleTracker(time.Minute)
+	tracker.now = func() time.Time { return now }
+	c := &cloud{throttle: tracker}
+
+	if rate := c.ThrottleRate(); rate != 0 {
+		t.Fatalf("expected throttle rate 0 without requests, got %v", rate)
+	}
+
+	handler := tracker.handler()
+	for _, err := range []error{
+		awserr.New("RequestLimitExceeded", "", nil),
+		awserr.New("Throttling", "", nil),
+		fmt.Errorf("generic error"),
+		nil,
+	} {
+		handler.Fn(newTestRequest("DescribeVolumes", err))
+	}
+
+	if rate := c.ThrottleRate(); rate != 0.5 {
+		t.Fatalf("expected throttle rate 0.5, got %v", rate)
+	}
+	if !c.IsThrottled() {
+		t.Fatal("expected cloud to be throttled")
+	}
+
+	// Half a window later, successful requests dilute the throttled ones
+	now = now.Add(30 * time.Second)
+	for i := 0; i < 4; i++ {
+		handler.Fn(newTestRequest("DescribeVolumes", nil))
+	}
+	if rate := c.ThrottleRate(); rate != 0.25 {
+		t.Fatalf("expected throttle rate 0.25, got %v", rate)
+	}
+
+	// Once the throttled requests fall out of the window, only the successful ones remain
+	now = now.Add(45 * time.Second)
+	if rate := c.ThrottleRate(); rate != 0 {
+		t.Fatalf("expected throttle rate 0, got %v", rate)
+	}
+	if c.IsThrottled() {
+		t.Fatal("expected cloud not to be throttled")
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// DefaultZoneVolumeCountsTTL is how long the volume counts per zone are
+// kept when no TTL is configured
+const DefaultZoneVolumeCountsTTL = 30 * time.Second
+
+// zoneVolumeCounts keeps the number of driver volumes per availability zone,
+// to save a DescribeVolumes listing on each volume creation.
+// A nil *zoneVolumeCounts is valid and caches nothing, which is the default.
+type zoneVolumeCounts struct {
+	mux     sync.Mutex
+	ttl     time.Duration
+	counts  map[string]int
+	expires time.Time
+
+	// now is overridden in unit tests
+	now func() time.Time
+}
+
+func newZoneVolumeCounts(ttl time.Duration) *zoneVolumeCounts {
+	return &zoneVolumeCounts{
+		ttl: ttl,
+		now: time.Now,
+	}
+}
+
+// get returns a copy of the cached counts, if present and not expired.
+func (zc *zoneVolumeCounts) get() (map[string]int, bool) {
+	if zc == nil {
+		return nil, false
+	}
+
+	zc.mux.Lock()
+	defer zc.mux.Unlock()
+
+	if zc.counts == nil || !zc.now().Before(zc.expires) {
+		zc.counts = nil
+		return nil, false
+	}
+	counts := make(map[string]int, len(zc.counts))
+	for zone, count := range zc.counts {
+		counts[zone] = count
+	}
+	return counts, true
+}
+
+func (zc *zoneVolumeCounts) set(counts map[string]int) {
+	if zc == nil {
+		return
+	}
+
+	zc.mux.Lock()
+	defer zc.mux.Unlock()
+
+	zc.counts = counts
+	zc.expires = zc.now().Add(zc.ttl)
+}
+
+// increment counts a volume created in the zone, so that the volumes created
+// before the counts expire are spread too.
+func (zc *zoneVolumeCounts) increment(zone string) {
+	if zc == nil {
+		return
+	}
+
+	zc.mux.Lock()
+	defer zc.mux.Unlock()
+
+	if zc.counts != nil {
+		zc.counts[zone]++
+	}
+}
+
+// leastUsedAvailabilityZone returns the zone of the region with the fewest
+// driver volumes, the first one by name on a tie.
+func (c *cloud) leastUsedAvailabilityZone(ctx context.Context) (string, error) {
+	counts, ok := c.zoneCounts.get()
+	if !ok {
+		var err error
+		counts, err = c.countVolumesByZone(ctx)
+		if err != nil {
+			return "", err
+		}
+		c.zoneCounts.set(counts)
+	}
+
+	zones := make([]string, 0, len(counts))
+	for zone := range counts {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+
+	var leastUsed string
+	for _, zone := range zones {
+		if leastUsed == "" || counts[zone] < counts[leastUsed] {
+			leastUsed = zone
+		}
+	}
+	return leastUsed, nil
+}
+
+// countVolumesByZone returns the number of driver volumes in each zone of the
+// region, including the zones without any.
+func (c *cloud) countVolumesByZone(ctx context.Context) (map[string]int, error) {
+	response, err := c.ec2.DescribeAvailabilityZonesWithContext(ctx, &ec2.DescribeAvailabilityZonesInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(response.AvailabilityZones))
+	for _, zone := range response.AvailabilityZones {
+		counts[aws.StringValue(zone.ZoneName)] = 0
+	}
+
+	request := &ec2.DescribeVolumesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("tag-key"),
+				Values: []*string{aws.String(VolumeNameTagKey)},
+			},
+		},
+	}
+	volumes, err := c.getVolumes(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	for _, volume := range volumes {
+		zone := aws.StringValue(volume.AvailabilityZone)
+		if _, ok := counts[zone]; ok {
+			counts[zone]++
+		}
+	}
+	return counts, nil
+}
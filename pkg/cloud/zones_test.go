@@ -0,0 +1,148 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/c2devel/aws-ebs-csi-driver/pkg/cloud/mocks"
+	"github.com/c2devel/aws-ebs-csi-driver/pkg/util"
+	"github.com/golang/mock/gomock"
+)
+
+func TestZoneVolumeCounts(t *testing.T) {
+	now := time.Now()
+	zc := newZoneVolumeCounts(30 * time.Second)
+	zc.now = func() time.Time { return now }
+
+	if _, ok := zc.get(); ok {
+		t.Fatal("expected no counts before they are set")
+	}
+
+	zc.set(map[string]int{"us-east-1a": 1, "us-east-1b": 2})
+	zc.increment("us-east-1a")
+	counts, ok := zc.get()
+	if !ok || counts["us-east-1a"] != 2 || counts["us-east-1b"] != 2 {
+		t.Fatalf("expected incremented counts, got %v", counts)
+	}
+
+	// The returned counts are a copy
+	counts["us-east-1a"] = 10
+	if counts, _ := zc.get(); counts["us-east-1a"] != 2 {
+		t.Fatalf("expected counts not to be modified by the caller, got %v", counts)
+	}
+
+	now = now.Add(30 * time.Second)
+	if _, ok := zc.get(); ok {
+		t.Fatal("expected expired counts not to be returned")
+	}
+
+	// A nil cache is disabled and caches nothing
+	var disabled *zoneVolumeCounts
+	disabled.set(map[string]int{"us-east-1a": 1})
+	disabled.increment("us-east-1a")
+	if _, ok := disabled.get(); ok {
+		t.Fatal("expected disabled cache not to return anything")
+	}
+}
+
+func TestCreateDiskZoneBalancing(t *testing.T) {
+	zones := &ec2.DescribeAvailabilityZonesOutput{
+		AvailabilityZones: []*ec2.AvailabilityZone{
+			{ZoneName: aws.String("us-east-1a")},
+			{ZoneName: aws.String("us-east-1b")},
+			{ZoneName: aws.String("us-east-1c")},
+		},
+	}
+	driverVolumes := []*ec2.Volume{
+		{VolumeId: aws.String("vol-1"), AvailabilityZone: aws.String("us-east-1a")},
+		{VolumeId: aws.String("vol-2"), AvailabilityZone: aws.String("us-east-1a")},
+		{VolumeId: aws.String("vol-3"), AvailabilityZone: aws.String("us-east-1b")},
+	}
+
+	testCases := []struct {
+		name     string
+		countErr error
+		expZones []string
+	}{
+		{
+			name:     "success: least used zones first",
+			expZones: []string{"us-east-1c", "us-east-1b", "us-east-1c"},
+		},
+		{
+			name:     "success: random zone when the count fails",
+			countErr: errors.New("DescribeVolumes generic error"),
+			expZones: []string{"us-east-1a"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := mocks.NewMockEC2(mockCtrl)
+			c := newCloud(mockEC2).(*cloud)
+			c.zoneCounts = newZoneVolumeCounts(time.Minute)
+
+			ctx := context.Background()
+			mockEC2.EXPECT().DescribeAvailabilityZonesWithContext(gomock.Eq(ctx), gomock.Any()).Return(zones, nil).AnyTimes()
+			var created *ec2.Volume
+			mockEC2.EXPECT().DescribeVolumesWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+				func(_ aws.Context, input *ec2.DescribeVolumesInput, _ ...request.Option) (*ec2.DescribeVolumesOutput, error) {
+					// The counts are listed once, then cached
+					if len(input.Filters) > 0 {
+						if tc.countErr != nil {
+							return nil, tc.countErr
+						}
+						return &ec2.DescribeVolumesOutput{Volumes: driverVolumes}, nil
+					}
+					return &ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{created}}, nil
+				}).MinTimes(len(tc.expZones))
+			mockEC2.EXPECT().CreateVolumeWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+				func(_ aws.Context, input *ec2.CreateVolumeInput, _ ...request.Option) (*ec2.Volume, error) {
+					created = &ec2.Volume{
+						VolumeId:         aws.String("vol-000000a8"),
+						Size:             input.Size,
+						State:            aws.String("available"),
+						AvailabilityZone: input.AvailabilityZone,
+					}
+					return created, nil
+				}).Times(len(tc.expZones))
+
+			for i, expZone := range tc.expZones {
+				diskOptions := &DiskOptions{
+					CapacityBytes: util.GiB,
+					Tags:          map[string]string{VolumeNameTagKey: "vol-000000a8"},
+				}
+				disk, err := c.CreateDisk(ctx, "vol-000000ae", diskOptions)
+				if err != nil {
+					t.Fatalf("CreateDisk() failed: expected no error, got: %v", err)
+				}
+				if disk.AvailabilityZone != expZone {
+					t.Fatalf("CreateDisk() failed: expected zone %q for volume %d, got %q", expZone, i, disk.AvailabilityZone)
+				}
+			}
+
+			mockCtrl.Finish()
+		})
+	}
+}
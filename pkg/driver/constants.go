@@ -36,6 +36,9 @@ const (
 
 	// KmsKeyId represents key for KMS encryption key
 	KmsKeyIDKey = "kmskeyid"
+
+	// BlockExpressKey represents key for whether an io2 volume is io2 Block Express
+	BlockExpressKey = "blockexpress"
 )
 
 // constants for default command line flag values
@@ -18,6 +18,7 @@ package driver
 
 import (
 	"context"
+	"errors"
 	"os"
 	"strconv"
 	"strings"
@@ -112,11 +113,11 @@ func (d *controllerService) CreateVolume(ctx context.Context, req *csi.CreateVol
 
 	disk, err := d.cloud.GetDiskByName(ctx, volName, volSizeBytes)
 	if err != nil {
-		switch err {
-		case cloud.ErrNotFound:
-		case cloud.ErrMultiDisks:
+		switch {
+		case errors.Is(err, cloud.ErrNotFound):
+		case errors.Is(err, cloud.ErrMultiDisks):
 			return nil, status.Error(codes.Internal, err.Error())
-		case cloud.ErrDiskExistsDiffSize:
+		case errors.Is(err, cloud.ErrDiskExistsDiffSize):
 			return nil, status.Error(codes.AlreadyExists, err.Error())
 		default:
 			return nil, status.Error(codes.Internal, err.Error())
@@ -124,10 +125,11 @@ func (d *controllerService) CreateVolume(ctx context.Context, req *csi.CreateVol
 	}
 
 	var (
-		volumeType  string
-		iopsPerGB   int
-		isEncrypted bool
-		kmsKeyID    string
+		volumeType     string
+		iopsPerGB      int
+		isEncrypted    bool
+		kmsKeyID       string
+		isBlockExpress bool
 	)
 
 	for key, value := range req.GetParameters() {
@@ -149,6 +151,8 @@ func (d *controllerService) CreateVolume(ctx context.Context, req *csi.CreateVol
 			}
 		case KmsKeyIDKey:
 			kmsKeyID = value
+		case BlockExpressKey:
+			isBlockExpress = value == "true"
 		default:
 			return nil, status.Errorf(codes.InvalidArgument, "Invalid parameter key %s for CreateVolume", key)
 		}
@@ -194,13 +198,19 @@ func (d *controllerService) CreateVolume(ctx context.Context, req *csi.CreateVol
 		Encrypted:        isEncrypted,
 		KmsKeyID:         kmsKeyID,
 		SnapshotID:       snapshotID,
+		BlockExpress:     isBlockExpress,
 	}
 
 	disk, err = d.cloud.CreateDisk(ctx, volName, opts)
 	if err != nil {
 		errCode := codes.Internal
-		if err == cloud.ErrNotFound {
+		switch {
+		case errors.Is(err, cloud.ErrNotFound):
 			errCode = codes.NotFound
+		case errors.Is(err, cloud.ErrDiskSmallerThanSnapshot):
+			errCode = codes.InvalidArgument
+		case errors.Is(err, cloud.ErrIdempotentParameterMismatch):
+			errCode = codes.AlreadyExists
 		}
 		return nil, status.Errorf(errCode, "Could not create volume %q: %v", volName, err)
 	}
@@ -215,7 +225,7 @@ func (d *controllerService) DeleteVolume(ctx context.Context, req *csi.DeleteVol
 	}
 
 	if _, err := d.cloud.DeleteDisk(ctx, volumeID); err != nil {
-		if err == cloud.ErrNotFound {
+		if errors.Is(err, cloud.ErrNotFound) {
 			klog.V(4).Info("DeleteVolume: volume not found, returning with success")
 			return &csi.DeleteVolumeResponse{}, nil
 		}
@@ -252,7 +262,7 @@ func (d *controllerService) ControllerPublishVolume(ctx context.Context, req *cs
 	}
 
 	if _, err := d.cloud.GetDiskByID(ctx, volumeID); err != nil {
-		if err == cloud.ErrNotFound {
+		if errors.Is(err, cloud.ErrNotFound) {
 			return nil, status.Error(codes.NotFound, "Volume not found")
 		}
 		return nil, status.Errorf(codes.Internal, "Could not get volume with ID %q: %v", volumeID, err)
@@ -260,7 +270,7 @@ func (d *controllerService) ControllerPublishVolume(ctx context.Context, req *cs
 
 	devicePath, err := d.cloud.AttachDisk(ctx, volumeID, nodeID)
 	if err != nil {
-		if err == cloud.ErrAlreadyExists {
+		if errors.Is(err, cloud.ErrAlreadyExists) {
 			return nil, status.Error(codes.AlreadyExists, err.Error())
 		}
 		return nil, status.Errorf(codes.Internal, "Could not attach volume %q to node %q: %v", volumeID, nodeID, err)
@@ -284,7 +294,7 @@ func (d *controllerService) ControllerUnpublishVolume(ctx context.Context, req *
 	}
 
 	if err := d.cloud.DetachDisk(ctx, volumeID, nodeID); err != nil {
-		if err == cloud.ErrNotFound {
+		if errors.Is(err, cloud.ErrNotFound) {
 			return &csi.ControllerUnpublishVolumeResponse{}, nil
 		}
 		return nil, status.Errorf(codes.Internal, "Could not detach volume %q from node %q: %v", volumeID, nodeID, err)
@@ -333,7 +343,7 @@ func (d *controllerService) ValidateVolumeCapabilities(ctx context.Context, req
 	}
 
 	if _, err := d.cloud.GetDiskByID(ctx, volumeID); err != nil {
-		if err == cloud.ErrNotFound {
+		if errors.Is(err, cloud.ErrNotFound) {
 			return nil, status.Error(codes.NotFound, "Volume not found")
 		}
 		return nil, status.Errorf(codes.Internal, "Could not get volume with ID %q: %v", volumeID, err)
@@ -368,6 +378,18 @@ func (d *controllerService) ControllerExpandVolume(ctx context.Context, req *csi
 
 	actualSizeGiB, err := d.cloud.ResizeDisk(ctx, volumeID, newSize)
 	if err != nil {
+		if errors.Is(err, cloud.ErrModificationRateLimited) {
+			return nil, status.Errorf(codes.ResourceExhausted, "Could not resize volume %q: %v", volumeID, err)
+		}
+		if errors.Is(err, cloud.ErrCannotShrinkVolume) {
+			return nil, status.Errorf(codes.OutOfRange, "Could not resize volume %q: %v", volumeID, err)
+		}
+		if errors.Is(err, cloud.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "Could not resize volume %q: %v", volumeID, err)
+		}
+		if errors.Is(err, cloud.ErrIncorrectVolumeState) {
+			return nil, status.Errorf(codes.FailedPrecondition, "Could not resize volume %q: %v", volumeID, err)
+		}
 		return nil, status.Errorf(codes.Internal, "Could not resize volume %q: %v", volumeID, err)
 	}
 
@@ -408,7 +430,7 @@ func (d *controllerService) CreateSnapshot(ctx context.Context, req *csi.CreateS
 		return nil, status.Error(codes.InvalidArgument, "Snapshot volume source ID not provided")
 	}
 	snapshot, err := d.cloud.GetSnapshotByName(ctx, snapshotName)
-	if err != nil && err != cloud.ErrNotFound {
+	if err != nil && !errors.Is(err, cloud.ErrNotFound) {
 		klog.Errorf("Error looking for the snapshot %s: %v", snapshotName, err)
 		return nil, err
 	}
@@ -439,10 +461,13 @@ func (d *controllerService) DeleteSnapshot(ctx context.Context, req *csi.DeleteS
 	}
 
 	if _, err := d.cloud.DeleteSnapshot(ctx, snapshotID); err != nil {
-		if err == cloud.ErrNotFound {
+		if errors.Is(err, cloud.ErrNotFound) {
 			klog.V(4).Info("DeleteSnapshot: snapshot not found, returning with success")
 			return &csi.DeleteSnapshotResponse{}, nil
 		}
+		if errors.Is(err, cloud.ErrSnapshotInUse) {
+			return nil, status.Errorf(codes.FailedPrecondition, "Could not delete snapshot ID %q: %v", snapshotID, err)
+		}
 		return nil, status.Errorf(codes.Internal, "Could not delete snapshot ID %q: %v", snapshotID, err)
 	}
 
@@ -457,7 +482,7 @@ func (d *controllerService) ListSnapshots(ctx context.Context, req *csi.ListSnap
 	if len(snapshotID) != 0 {
 		snapshot, err := d.cloud.GetSnapshotByID(ctx, snapshotID)
 		if err != nil {
-			if err == cloud.ErrNotFound {
+			if errors.Is(err, cloud.ErrNotFound) {
 				klog.V(4).Info("ListSnapshots: snapshot not found, returning with success")
 				return &csi.ListSnapshotsResponse{}, nil
 			}
@@ -479,11 +504,11 @@ func (d *controllerService) ListSnapshots(ctx context.Context, req *csi.ListSnap
 
 	cloudSnapshots, err := d.cloud.ListSnapshots(ctx, volumeID, maxEntries, nextToken)
 	if err != nil {
-		if err == cloud.ErrNotFound {
+		if errors.Is(err, cloud.ErrNotFound) {
 			klog.V(4).Info("ListSnapshots: snapshot not found, returning with success")
 			return &csi.ListSnapshotsResponse{}, nil
 		}
-		if err == cloud.ErrInvalidMaxResults {
+		if errors.Is(err, cloud.ErrInvalidMaxResults) {
 			return nil, status.Errorf(codes.InvalidArgument, "Error mapping MaxEntries to AWS MaxResults: %v", err)
 		}
 		return nil, status.Errorf(codes.Internal, "Could not list snapshots: %v", err)
@@ -47,8 +47,8 @@ func TestNewControllerService(t *testing.T) {
 		testErr    = errors.New("test error")
 		testRegion = "test-region"
 
-		getNewCloudFunc = func(expectedRegion string) func(region string) (cloud.Cloud, error) {
-			return func(region string) (cloud.Cloud, error) {
+		getNewCloudFunc = func(expectedRegion string) func(region string, options ...func(*cloud.CloudOptions)) (cloud.Cloud, error) {
+			return func(region string, options ...func(*cloud.CloudOptions)) (cloud.Cloud, error) {
 				if region != expectedRegion {
 					t.Fatalf("expected region %q but got %q", expectedRegion, region)
 				}
@@ -60,7 +60,7 @@ func TestNewControllerService(t *testing.T) {
 	testCases := []struct {
 		name                  string
 		region                string
-		newCloudFunc          func(string) (cloud.Cloud, error)
+		newCloudFunc          func(string, ...func(*cloud.CloudOptions)) (cloud.Cloud, error)
 		newMetadataFuncErrors bool
 		expectPanic           bool
 	}{
@@ -72,7 +72,7 @@ func TestNewControllerService(t *testing.T) {
 		{
 			name:   "AWS_REGION variable set, newCloud errors",
 			region: "foo",
-			newCloudFunc: func(region string) (cloud.Cloud, error) {
+			newCloudFunc: func(region string, options ...func(*cloud.CloudOptions)) (cloud.Cloud, error) {
 				return nil, testErr
 			},
 			expectPanic: true,
@@ -1484,6 +1484,31 @@ func TestDeleteSnapshot(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "fail snapshot in use",
+			testFunc: func(t *testing.T) {
+				ctx := context.Background()
+
+				mockCtl := gomock.NewController(t)
+				defer mockCtl.Finish()
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				awsDriver := controllerService{
+					cloud:         mockCloud,
+					driverOptions: &DriverOptions{},
+				}
+
+				req := &csi.DeleteSnapshotRequest{
+					SnapshotId: "xxx",
+				}
+
+				mockCloud.EXPECT().DeleteSnapshot(gomock.Eq(ctx), gomock.Eq("xxx")).Return(false, cloud.ErrSnapshotInUse)
+				_, err := awsDriver.DeleteSnapshot(ctx, req)
+				if srvErr, ok := status.FromError(err); !ok || srvErr.Code() != codes.FailedPrecondition {
+					t.Fatalf("Expected error code %v, got: %v", codes.FailedPrecondition, err)
+				}
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -2136,11 +2161,13 @@ func TestControllerUnpublishVolume(t *testing.T) {
 
 func TestControllerExpandVolume(t *testing.T) {
 	testCases := []struct {
-		name     string
-		req      *csi.ControllerExpandVolumeRequest
-		newSize  int64
-		expResp  *csi.ControllerExpandVolumeResponse
-		expError bool
+		name      string
+		req       *csi.ControllerExpandVolumeRequest
+		newSize   int64
+		resizeErr error
+		expResp   *csi.ControllerExpandVolumeResponse
+		expError  bool
+		expCode   codes.Code
 	}{
 		{
 			name: "success normal",
@@ -2170,6 +2197,54 @@ func TestControllerExpandVolume(t *testing.T) {
 			},
 			expError: true,
 		},
+		{
+			name: "fail volume modified too recently",
+			req: &csi.ControllerExpandVolumeRequest{
+				VolumeId: "vol-test",
+				CapacityRange: &csi.CapacityRange{
+					RequiredBytes: 5 * util.GiB,
+				},
+			},
+			resizeErr: cloud.ErrModificationRateLimited,
+			expError:  true,
+			expCode:   codes.ResourceExhausted,
+		},
+		{
+			name: "fail volume shrunk",
+			req: &csi.ControllerExpandVolumeRequest{
+				VolumeId: "vol-test",
+				CapacityRange: &csi.CapacityRange{
+					RequiredBytes: 5 * util.GiB,
+				},
+			},
+			resizeErr: cloud.ErrCannotShrinkVolume,
+			expError:  true,
+			expCode:   codes.OutOfRange,
+		},
+		{
+			name: "fail volume not found",
+			req: &csi.ControllerExpandVolumeRequest{
+				VolumeId: "vol-test",
+				CapacityRange: &csi.CapacityRange{
+					RequiredBytes: 5 * util.GiB,
+				},
+			},
+			resizeErr: cloud.ErrNotFound,
+			expError:  true,
+			expCode:   codes.NotFound,
+		},
+		{
+			name: "fail volume in incorrect state",
+			req: &csi.ControllerExpandVolumeRequest{
+				VolumeId: "vol-test",
+				CapacityRange: &csi.CapacityRange{
+					RequiredBytes: 5 * util.GiB,
+				},
+			},
+			resizeErr: cloud.ErrIncorrectVolumeState,
+			expError:  true,
+			expCode:   codes.FailedPrecondition,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -2186,7 +2261,7 @@ func TestControllerExpandVolume(t *testing.T) {
 			}
 
 			mockCloud := mocks.NewMockCloud(mockCtl)
-			mockCloud.EXPECT().ResizeDisk(gomock.Eq(ctx), gomock.Eq(tc.req.VolumeId), gomock.Any()).Return(retSizeGiB, nil).AnyTimes()
+			mockCloud.EXPECT().ResizeDisk(gomock.Eq(ctx), gomock.Eq(tc.req.VolumeId), gomock.Any()).Return(retSizeGiB, tc.resizeErr).AnyTimes()
 
 			awsDriver := controllerService{
 				cloud:         mockCloud,
@@ -2202,6 +2277,9 @@ func TestControllerExpandVolume(t *testing.T) {
 				if !tc.expError {
 					t.Fatalf("Unexpected error: %v", err)
 				}
+				if tc.expCode != codes.OK && srvErr.Code() != tc.expCode {
+					t.Fatalf("Expected error code %v, got %v", tc.expCode, srvErr.Code())
+				}
 			} else {
 				if tc.expError {
 					t.Fatalf("Expected error from ControllerExpandVolume, got nothing")
@@ -136,6 +136,9 @@ func (d *Driver) Run() error {
 func (d *Driver) Stop() {
 	klog.Infof("Stopping server")
 	d.srv.Stop()
+	if d.controllerService.cloud != nil {
+		d.controllerService.cloud.Close()
+	}
 }
 
 func WithEndpoint(endpoint string) func(*DriverOptions) {
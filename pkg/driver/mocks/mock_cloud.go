@@ -9,6 +9,7 @@ import (
 	cloud "github.com/c2devel/aws-ebs-csi-driver/pkg/cloud"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
+	time "time"
 )
 
 // MockCloud is a mock of Cloud interface
@@ -49,6 +50,64 @@ func (mr *MockCloudMockRecorder) AttachDisk(arg0, arg1, arg2 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachDisk", reflect.TypeOf((*MockCloud)(nil).AttachDisk), arg0, arg1, arg2)
 }
 
+// BatchReconcileVolumeTags mocks base method
+func (m *MockCloud) BatchReconcileVolumeTags(arg0 context.Context, arg1 map[string]map[string]string) (map[string]error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchReconcileVolumeTags", arg0, arg1)
+	ret0, _ := ret[0].(map[string]error)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchReconcileVolumeTags indicates an expected call of BatchReconcileVolumeTags
+func (mr *MockCloudMockRecorder) BatchReconcileVolumeTags(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchReconcileVolumeTags", reflect.TypeOf((*MockCloud)(nil).BatchReconcileVolumeTags), arg0, arg1)
+}
+
+// CheckVolumeConfigDrift mocks base method
+func (m *MockCloud) CheckVolumeConfigDrift(arg0 context.Context, arg1 string, arg2 *cloud.DiskOptions) (*cloud.ConfigDrift, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckVolumeConfigDrift", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*cloud.ConfigDrift)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckVolumeConfigDrift indicates an expected call of CheckVolumeConfigDrift
+func (mr *MockCloudMockRecorder) CheckVolumeConfigDrift(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckVolumeConfigDrift", reflect.TypeOf((*MockCloud)(nil).CheckVolumeConfigDrift), arg0, arg1, arg2)
+}
+
+// Close mocks base method
+func (m *MockCloud) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close
+func (mr *MockCloudMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockCloud)(nil).Close))
+}
+
+// CreateAndAttachDisk mocks base method
+func (m *MockCloud) CreateAndAttachDisk(arg0 context.Context, arg1, arg2 string, arg3 *cloud.DiskOptions) (*cloud.Disk, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAndAttachDisk", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*cloud.Disk)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateAndAttachDisk indicates an expected call of CreateAndAttachDisk
+func (mr *MockCloudMockRecorder) CreateAndAttachDisk(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAndAttachDisk", reflect.TypeOf((*MockCloud)(nil).CreateAndAttachDisk), arg0, arg1, arg2, arg3)
+}
+
 // CreateDisk mocks base method
 func (m *MockCloud) CreateDisk(arg0 context.Context, arg1 string, arg2 *cloud.DiskOptions) (*cloud.Disk, error) {
 	m.ctrl.T.Helper()
@@ -64,6 +123,21 @@ func (mr *MockCloudMockRecorder) CreateDisk(arg0, arg1, arg2 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDisk", reflect.TypeOf((*MockCloud)(nil).CreateDisk), arg0, arg1, arg2)
 }
 
+// CreateEncryptedSnapshot mocks base method
+func (m *MockCloud) CreateEncryptedSnapshot(arg0 context.Context, arg1, arg2 string, arg3 *cloud.SnapshotOptions) (*cloud.Snapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEncryptedSnapshot", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*cloud.Snapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEncryptedSnapshot indicates an expected call of CreateEncryptedSnapshot
+func (mr *MockCloudMockRecorder) CreateEncryptedSnapshot(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEncryptedSnapshot", reflect.TypeOf((*MockCloud)(nil).CreateEncryptedSnapshot), arg0, arg1, arg2, arg3)
+}
+
 // CreateSnapshot mocks base method
 func (m *MockCloud) CreateSnapshot(arg0 context.Context, arg1 string, arg2 *cloud.SnapshotOptions) (*cloud.Snapshot, error) {
 	m.ctrl.T.Helper()
@@ -109,6 +183,21 @@ func (mr *MockCloudMockRecorder) DeleteSnapshot(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSnapshot", reflect.TypeOf((*MockCloud)(nil).DeleteSnapshot), arg0, arg1)
 }
 
+// DeleteSnapshots mocks base method
+func (m *MockCloud) DeleteSnapshots(arg0 context.Context, arg1 []string, arg2 int) (map[string]error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSnapshots", arg0, arg1, arg2)
+	ret0, _ := ret[0].(map[string]error)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSnapshots indicates an expected call of DeleteSnapshots
+func (mr *MockCloudMockRecorder) DeleteSnapshots(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSnapshots", reflect.TypeOf((*MockCloud)(nil).DeleteSnapshots), arg0, arg1, arg2)
+}
+
 // DetachDisk mocks base method
 func (m *MockCloud) DetachDisk(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -123,6 +212,51 @@ func (mr *MockCloudMockRecorder) DetachDisk(arg0, arg1, arg2 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachDisk", reflect.TypeOf((*MockCloud)(nil).DetachDisk), arg0, arg1, arg2)
 }
 
+// DetectStuckVolumes mocks base method
+func (m *MockCloud) DetectStuckVolumes(arg0 context.Context, arg1 time.Duration) ([]*cloud.Disk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectStuckVolumes", arg0, arg1)
+	ret0, _ := ret[0].([]*cloud.Disk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetectStuckVolumes indicates an expected call of DetectStuckVolumes
+func (mr *MockCloudMockRecorder) DetectStuckVolumes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectStuckVolumes", reflect.TypeOf((*MockCloud)(nil).DetectStuckVolumes), arg0, arg1)
+}
+
+// FindVolumesAttachedToDeadNodes mocks base method
+func (m *MockCloud) FindVolumesAttachedToDeadNodes(arg0 context.Context, arg1 map[string]bool) ([]*cloud.Disk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindVolumesAttachedToDeadNodes", arg0, arg1)
+	ret0, _ := ret[0].([]*cloud.Disk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindVolumesAttachedToDeadNodes indicates an expected call of FindVolumesAttachedToDeadNodes
+func (mr *MockCloudMockRecorder) FindVolumesAttachedToDeadNodes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindVolumesAttachedToDeadNodes", reflect.TypeOf((*MockCloud)(nil).FindVolumesAttachedToDeadNodes), arg0, arg1)
+}
+
+// GetAvailabilityZonesForVolumeType mocks base method
+func (m *MockCloud) GetAvailabilityZonesForVolumeType(arg0 context.Context, arg1 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAvailabilityZonesForVolumeType", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAvailabilityZonesForVolumeType indicates an expected call of GetAvailabilityZonesForVolumeType
+func (mr *MockCloudMockRecorder) GetAvailabilityZonesForVolumeType(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailabilityZonesForVolumeType", reflect.TypeOf((*MockCloud)(nil).GetAvailabilityZonesForVolumeType), arg0, arg1)
+}
+
 // GetDiskByID mocks base method
 func (m *MockCloud) GetDiskByID(arg0 context.Context, arg1 string) (*cloud.Disk, error) {
 	m.ctrl.T.Helper()
@@ -153,6 +287,81 @@ func (mr *MockCloudMockRecorder) GetDiskByName(arg0, arg1, arg2 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDiskByName", reflect.TypeOf((*MockCloud)(nil).GetDiskByName), arg0, arg1, arg2)
 }
 
+// GetExpectedDevicePath mocks base method
+func (m *MockCloud) GetExpectedDevicePath(arg0 context.Context, arg1, arg2 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpectedDevicePath", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpectedDevicePath indicates an expected call of GetExpectedDevicePath
+func (mr *MockCloudMockRecorder) GetExpectedDevicePath(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpectedDevicePath", reflect.TypeOf((*MockCloud)(nil).GetExpectedDevicePath), arg0, arg1, arg2)
+}
+
+// GetInstanceType mocks base method
+func (m *MockCloud) GetInstanceType(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstanceType", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInstanceType indicates an expected call of GetInstanceType
+func (mr *MockCloudMockRecorder) GetInstanceType(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstanceType", reflect.TypeOf((*MockCloud)(nil).GetInstanceType), arg0, arg1)
+}
+
+// GetInstanceTypeInfo mocks base method
+func (m *MockCloud) GetInstanceTypeInfo(arg0 context.Context, arg1 string) (*cloud.InstanceTypeInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstanceTypeInfo", arg0, arg1)
+	ret0, _ := ret[0].(*cloud.InstanceTypeInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInstanceTypeInfo indicates an expected call of GetInstanceTypeInfo
+func (mr *MockCloudMockRecorder) GetInstanceTypeInfo(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstanceTypeInfo", reflect.TypeOf((*MockCloud)(nil).GetInstanceTypeInfo), arg0, arg1)
+}
+
+// GetNewestSnapshotByName mocks base method
+func (m *MockCloud) GetNewestSnapshotByName(arg0 context.Context, arg1 string) (*cloud.Snapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNewestSnapshotByName", arg0, arg1)
+	ret0, _ := ret[0].(*cloud.Snapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNewestSnapshotByName indicates an expected call of GetNewestSnapshotByName
+func (mr *MockCloudMockRecorder) GetNewestSnapshotByName(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNewestSnapshotByName", reflect.TypeOf((*MockCloud)(nil).GetNewestSnapshotByName), arg0, arg1)
+}
+
+// GetSnapshotActualSize mocks base method
+func (m *MockCloud) GetSnapshotActualSize(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSnapshotActualSize", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSnapshotActualSize indicates an expected call of GetSnapshotActualSize
+func (mr *MockCloudMockRecorder) GetSnapshotActualSize(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSnapshotActualSize", reflect.TypeOf((*MockCloud)(nil).GetSnapshotActualSize), arg0, arg1)
+}
+
 // GetSnapshotByID mocks base method
 func (m *MockCloud) GetSnapshotByID(arg0 context.Context, arg1 string) (*cloud.Snapshot, error) {
 	m.ctrl.T.Helper()
@@ -183,6 +392,36 @@ func (mr *MockCloudMockRecorder) GetSnapshotByName(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSnapshotByName", reflect.TypeOf((*MockCloud)(nil).GetSnapshotByName), arg0, arg1)
 }
 
+// GetVolumeAttachments mocks base method
+func (m *MockCloud) GetVolumeAttachments(arg0 context.Context, arg1 string) ([]cloud.VolumeAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVolumeAttachments", arg0, arg1)
+	ret0, _ := ret[0].([]cloud.VolumeAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVolumeAttachments indicates an expected call of GetVolumeAttachments
+func (mr *MockCloudMockRecorder) GetVolumeAttachments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVolumeAttachments", reflect.TypeOf((*MockCloud)(nil).GetVolumeAttachments), arg0, arg1)
+}
+
+// GetVolumeHealth mocks base method
+func (m *MockCloud) GetVolumeHealth(arg0 context.Context, arg1 string) (cloud.VolumeHealth, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVolumeHealth", arg0, arg1)
+	ret0, _ := ret[0].(cloud.VolumeHealth)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVolumeHealth indicates an expected call of GetVolumeHealth
+func (mr *MockCloudMockRecorder) GetVolumeHealth(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVolumeHealth", reflect.TypeOf((*MockCloud)(nil).GetVolumeHealth), arg0, arg1)
+}
+
 // IsExistInstance mocks base method
 func (m *MockCloud) IsExistInstance(arg0 context.Context, arg1 string) bool {
 	m.ctrl.T.Helper()
@@ -197,6 +436,50 @@ func (mr *MockCloudMockRecorder) IsExistInstance(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsExistInstance", reflect.TypeOf((*MockCloud)(nil).IsExistInstance), arg0, arg1)
 }
 
+// IsThrottled mocks base method
+func (m *MockCloud) IsThrottled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsThrottled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsThrottled indicates an expected call of IsThrottled
+func (mr *MockCloudMockRecorder) IsThrottled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsThrottled", reflect.TypeOf((*MockCloud)(nil).IsThrottled))
+}
+
+// ListDisksByTag mocks base method
+func (m *MockCloud) ListDisksByTag(arg0 context.Context, arg1, arg2 string) ([]*cloud.Disk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDisksByTag", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*cloud.Disk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDisksByTag indicates an expected call of ListDisksByTag
+func (mr *MockCloudMockRecorder) ListDisksByTag(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDisksByTag", reflect.TypeOf((*MockCloud)(nil).ListDisksByTag), arg0, arg1, arg2)
+}
+
+// ListOrphanedDisks mocks base method
+func (m *MockCloud) ListOrphanedDisks(arg0 context.Context, arg1, arg2 string) ([]*cloud.Disk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrphanedDisks", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*cloud.Disk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrphanedDisks indicates an expected call of ListOrphanedDisks
+func (mr *MockCloudMockRecorder) ListOrphanedDisks(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrphanedDisks", reflect.TypeOf((*MockCloud)(nil).ListOrphanedDisks), arg0, arg1, arg2)
+}
+
 // ListSnapshots mocks base method
 func (m *MockCloud) ListSnapshots(arg0 context.Context, arg1 string, arg2 int64, arg3 string) (*cloud.ListSnapshotsResponse, error) {
 	m.ctrl.T.Helper()
@@ -212,6 +495,94 @@ func (mr *MockCloudMockRecorder) ListSnapshots(arg0, arg1, arg2, arg3 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSnapshots", reflect.TypeOf((*MockCloud)(nil).ListSnapshots), arg0, arg1, arg2, arg3)
 }
 
+// ListSnapshotsOlderThan mocks base method
+func (m *MockCloud) ListSnapshotsOlderThan(arg0 context.Context, arg1 time.Time, arg2 map[string]string) ([]*cloud.Snapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSnapshotsOlderThan", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*cloud.Snapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSnapshotsOlderThan indicates an expected call of ListSnapshotsOlderThan
+func (mr *MockCloudMockRecorder) ListSnapshotsOlderThan(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSnapshotsOlderThan", reflect.TypeOf((*MockCloud)(nil).ListSnapshotsOlderThan), arg0, arg1, arg2)
+}
+
+// ListSnapshotsWithOptions mocks base method
+func (m *MockCloud) ListSnapshotsWithOptions(arg0 context.Context, arg1 *cloud.ListSnapshotsOptions) (*cloud.ListSnapshotsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSnapshotsWithOptions", arg0, arg1)
+	ret0, _ := ret[0].(*cloud.ListSnapshotsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSnapshotsWithOptions indicates an expected call of ListSnapshotsWithOptions
+func (mr *MockCloudMockRecorder) ListSnapshotsWithOptions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSnapshotsWithOptions", reflect.TypeOf((*MockCloud)(nil).ListSnapshotsWithOptions), arg0, arg1)
+}
+
+// ListVolumes mocks base method
+func (m *MockCloud) ListVolumes(arg0 context.Context, arg1 int64, arg2 string) (*cloud.ListVolumesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVolumes", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*cloud.ListVolumesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVolumes indicates an expected call of ListVolumes
+func (mr *MockCloudMockRecorder) ListVolumes(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVolumes", reflect.TypeOf((*MockCloud)(nil).ListVolumes), arg0, arg1, arg2)
+}
+
+// ModifyDisk mocks base method
+func (m *MockCloud) ModifyDisk(arg0 context.Context, arg1 string, arg2 *cloud.ModifyOptions) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModifyDisk", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ModifyDisk indicates an expected call of ModifyDisk
+func (mr *MockCloudMockRecorder) ModifyDisk(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModifyDisk", reflect.TypeOf((*MockCloud)(nil).ModifyDisk), arg0, arg1, arg2)
+}
+
+// ModifyDiskTags mocks base method
+func (m *MockCloud) ModifyDiskTags(arg0 context.Context, arg1 string, arg2 map[string]string, arg3 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModifyDiskTags", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ModifyDiskTags indicates an expected call of ModifyDiskTags
+func (mr *MockCloudMockRecorder) ModifyDiskTags(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModifyDiskTags", reflect.TypeOf((*MockCloud)(nil).ModifyDiskTags), arg0, arg1, arg2, arg3)
+}
+
+// ModifySnapshotTags mocks base method
+func (m *MockCloud) ModifySnapshotTags(arg0 context.Context, arg1 string, arg2 map[string]string, arg3 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModifySnapshotTags", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ModifySnapshotTags indicates an expected call of ModifySnapshotTags
+func (mr *MockCloudMockRecorder) ModifySnapshotTags(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModifySnapshotTags", reflect.TypeOf((*MockCloud)(nil).ModifySnapshotTags), arg0, arg1, arg2, arg3)
+}
+
 // ResizeDisk mocks base method
 func (m *MockCloud) ResizeDisk(arg0 context.Context, arg1 string, arg2 int64) (int64, error) {
 	m.ctrl.T.Helper()
@@ -227,6 +598,35 @@ func (mr *MockCloudMockRecorder) ResizeDisk(arg0, arg1, arg2 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResizeDisk", reflect.TypeOf((*MockCloud)(nil).ResizeDisk), arg0, arg1, arg2)
 }
 
+// ThrottleRate mocks base method
+func (m *MockCloud) ThrottleRate() float64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ThrottleRate")
+	ret0, _ := ret[0].(float64)
+	return ret0
+}
+
+// ThrottleRate indicates an expected call of ThrottleRate
+func (mr *MockCloudMockRecorder) ThrottleRate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ThrottleRate", reflect.TypeOf((*MockCloud)(nil).ThrottleRate))
+}
+
+// VolumeExists mocks base method
+func (m *MockCloud) VolumeExists(arg0 context.Context, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VolumeExists", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VolumeExists indicates an expected call of VolumeExists
+func (mr *MockCloudMockRecorder) VolumeExists(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VolumeExists", reflect.TypeOf((*MockCloud)(nil).VolumeExists), arg0, arg1)
+}
+
 // WaitForAttachmentState mocks base method
 func (m *MockCloud) WaitForAttachmentState(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -240,3 +640,34 @@ func (mr *MockCloudMockRecorder) WaitForAttachmentState(arg0, arg1, arg2 interfa
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForAttachmentState", reflect.TypeOf((*MockCloud)(nil).WaitForAttachmentState), arg0, arg1, arg2)
 }
+
+// WaitForSnapshotCompleted mocks base method
+func (m *MockCloud) WaitForSnapshotCompleted(arg0 context.Context, arg1 string) (*cloud.Snapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForSnapshotCompleted", arg0, arg1)
+	ret0, _ := ret[0].(*cloud.Snapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitForSnapshotCompleted indicates an expected call of WaitForSnapshotCompleted
+func (mr *MockCloudMockRecorder) WaitForSnapshotCompleted(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForSnapshotCompleted", reflect.TypeOf((*MockCloud)(nil).WaitForSnapshotCompleted), arg0, arg1)
+}
+
+// WillBeEncrypted mocks base method
+func (m *MockCloud) WillBeEncrypted(arg0 context.Context, arg1 *cloud.DiskOptions) (bool, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WillBeEncrypted", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// WillBeEncrypted indicates an expected call of WillBeEncrypted
+func (mr *MockCloudMockRecorder) WillBeEncrypted(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WillBeEncrypted", reflect.TypeOf((*MockCloud)(nil).WillBeEncrypted), arg0, arg1)
+}
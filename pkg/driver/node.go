@@ -476,7 +476,7 @@ func (d *nodeService) findDevicePath(devicePath, volumeID string) (string, error
 	// This is the magic name on which AWS presents NVME devices under /dev/disk/by-id/
 	// For example, vol-0fab1d5e3f72a5e23 creates a symlink at
 	// /dev/disk/by-id/nvme-Amazon_Elastic_Block_Store_vol0fab1d5e3f72a5e23
-	nvmeName := "nvme-Amazon_Elastic_Block_Store_" + strings.Replace(volumeID, "-", "", -1)
+	nvmeName := "nvme-Amazon_Elastic_Block_Store_" + cloud.NVMeSerial(volumeID)
 
 	return findNvmeVolume(nvmeName)
 }
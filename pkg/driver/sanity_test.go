@@ -119,6 +119,10 @@ func (c *fakeCloudProvider) CreateDisk(ctx context.Context, volumeName string, d
 	return d.Disk, nil
 }
 
+func (c *fakeCloudProvider) WillBeEncrypted(ctx context.Context, diskOptions *cloud.DiskOptions) (bool, string, error) {
+	return diskOptions.Encrypted || len(diskOptions.KmsKeyID) > 0, diskOptions.KmsKeyID, nil
+}
+
 func (c *fakeCloudProvider) DeleteDisk(ctx context.Context, volumeID string) (bool, error) {
 	for volName, f := range c.disks {
 		if f.Disk.VolumeID == volumeID {
@@ -136,6 +140,73 @@ func (c *fakeCloudProvider) AttachDisk(ctx context.Context, volumeID, nodeID str
 	return "/tmp", nil
 }
 
+func (c *fakeCloudProvider) CreateAndAttachDisk(ctx context.Context, volumeName, nodeID string, diskOptions *cloud.DiskOptions) (*cloud.Disk, string, error) {
+	disk, err := c.CreateDisk(ctx, volumeName, diskOptions)
+	if err != nil {
+		return nil, "", err
+	}
+	devicePath, err := c.AttachDisk(ctx, disk.VolumeID, nodeID)
+	if err != nil {
+		return nil, "", err
+	}
+	return disk, devicePath, nil
+}
+
+func (c *fakeCloudProvider) GetVolumeAttachments(ctx context.Context, volumeID string) ([]cloud.VolumeAttachment, error) {
+	attachments := []cloud.VolumeAttachment{}
+	if nodeID, ok := c.pub[volumeID]; ok {
+		attachments = append(attachments, cloud.VolumeAttachment{InstanceID: nodeID, Device: "/tmp", State: "attached"})
+	}
+	return attachments, nil
+}
+
+func (c *fakeCloudProvider) GetExpectedDevicePath(ctx context.Context, volumeID, nodeID string) (string, error) {
+	return "/tmp", nil
+}
+
+func (c *fakeCloudProvider) GetAvailabilityZonesForVolumeType(ctx context.Context, volumeType string) ([]string, error) {
+	return []string{"az"}, nil
+}
+
+func (c *fakeCloudProvider) Close() {}
+
+func (c *fakeCloudProvider) ModifyDiskTags(ctx context.Context, volumeID string, addOrUpdate map[string]string, remove []string) error {
+	for _, f := range c.disks {
+		if f.Disk.VolumeID != volumeID {
+			continue
+		}
+		if f.tags == nil {
+			f.tags = map[string]string{}
+		}
+		for k, v := range addOrUpdate {
+			f.tags[k] = v
+		}
+		for _, k := range remove {
+			delete(f.tags, k)
+		}
+		return nil
+	}
+	return cloud.ErrNotFound
+}
+
+func (c *fakeCloudProvider) BatchReconcileVolumeTags(ctx context.Context, volumeTags map[string]map[string]string) (map[string]error, error) {
+	errs := make(map[string]error, len(volumeTags))
+	for volumeID, tags := range volumeTags {
+		errs[volumeID] = cloud.ErrNotFound
+		for _, f := range c.disks {
+			if f.Disk.VolumeID != volumeID {
+				continue
+			}
+			f.tags = make(map[string]string, len(tags))
+			for k, v := range tags {
+				f.tags[k] = v
+			}
+			errs[volumeID] = nil
+		}
+	}
+	return errs, nil
+}
+
 func (c *fakeCloudProvider) DetachDisk(ctx context.Context, volumeID, nodeID string) error {
 	return nil
 }
@@ -173,10 +244,81 @@ func (c *fakeCloudProvider) GetDiskByID(ctx context.Context, volumeID string) (*
 	return nil, cloud.ErrNotFound
 }
 
+func (c *fakeCloudProvider) VolumeExists(ctx context.Context, volumeID string) (bool, error) {
+	for _, f := range c.disks {
+		if f.Disk.VolumeID == volumeID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (c *fakeCloudProvider) GetVolumeHealth(ctx context.Context, volumeID string) (cloud.VolumeHealth, error) {
+	for _, f := range c.disks {
+		if f.Disk.VolumeID == volumeID {
+			return cloud.VolumeHealth{VolumeID: volumeID, Status: "ok"}, nil
+		}
+	}
+	return cloud.VolumeHealth{}, cloud.ErrNotFound
+}
+
+func (c *fakeCloudProvider) DetectStuckVolumes(ctx context.Context, stuckAfter time.Duration) ([]*cloud.Disk, error) {
+	return nil, nil
+}
+
+func (c *fakeCloudProvider) CheckVolumeConfigDrift(ctx context.Context, volumeID string, desired *cloud.DiskOptions) (*cloud.ConfigDrift, error) {
+	return &cloud.ConfigDrift{}, nil
+}
+
+func (c *fakeCloudProvider) FindVolumesAttachedToDeadNodes(ctx context.Context, liveNodeIDs map[string]bool) ([]*cloud.Disk, error) {
+	return nil, nil
+}
+
+func (c *fakeCloudProvider) ListDisksByTag(ctx context.Context, tagKey, tagValue string) ([]*cloud.Disk, error) {
+	var disks []*cloud.Disk
+	for _, f := range c.disks {
+		if value, ok := f.tags[tagKey]; ok && (tagValue == "" || value == tagValue) {
+			disks = append(disks, f.Disk)
+		}
+	}
+	return disks, nil
+}
+
+func (c *fakeCloudProvider) ListOrphanedDisks(ctx context.Context, clusterTagKey, clusterTagValue string) ([]*cloud.Disk, error) {
+	return nil, nil
+}
+
+func (c *fakeCloudProvider) ListVolumes(ctx context.Context, maxResults int64, nextToken string) (*cloud.ListVolumesResponse, error) {
+	disks := make([]*cloud.Disk, 0, len(c.disks))
+	for _, f := range c.disks {
+		disks = append(disks, f.Disk)
+	}
+	return &cloud.ListVolumesResponse{Disks: disks}, nil
+}
+
+func (c *fakeCloudProvider) GetInstanceTypeInfo(ctx context.Context, instanceType string) (*cloud.InstanceTypeInfo, error) {
+	return &cloud.InstanceTypeInfo{InstanceType: instanceType}, nil
+}
+
+func (c *fakeCloudProvider) IsThrottled() bool {
+	return false
+}
+
+func (c *fakeCloudProvider) ThrottleRate() float64 {
+	return 0
+}
+
 func (c *fakeCloudProvider) IsExistInstance(ctx context.Context, nodeID string) bool {
 	return nodeID == "instanceID"
 }
 
+func (c *fakeCloudProvider) GetInstanceType(ctx context.Context, nodeID string) (string, error) {
+	if nodeID != "instanceID" {
+		return "", cloud.ErrNotFound
+	}
+	return "m5.large", nil
+}
+
 func (c *fakeCloudProvider) CreateSnapshot(ctx context.Context, volumeID string, snapshotOptions *cloud.SnapshotOptions) (snapshot *cloud.Snapshot, err error) {
 	r1 := rand.New(rand.NewSource(time.Now().UnixNano()))
 	snapshotID := fmt.Sprintf("snapshot-%d", r1.Uint64())
@@ -201,12 +343,41 @@ func (c *fakeCloudProvider) CreateSnapshot(ctx context.Context, volumeID string,
 
 }
 
+func (c *fakeCloudProvider) CreateEncryptedSnapshot(ctx context.Context, volumeID, kmsKeyID string, snapshotOptions *cloud.SnapshotOptions) (snapshot *cloud.Snapshot, err error) {
+	return c.CreateSnapshot(ctx, volumeID, snapshotOptions)
+}
+
 func (c *fakeCloudProvider) DeleteSnapshot(ctx context.Context, snapshotID string) (success bool, err error) {
 	delete(c.snapshots, snapshotID)
 	return true, nil
 
 }
 
+func (c *fakeCloudProvider) DeleteSnapshots(ctx context.Context, snapshotIDs []string, concurrency int) (map[string]error, error) {
+	errs := make(map[string]error, len(snapshotIDs))
+	for _, snapshotID := range snapshotIDs {
+		_, errs[snapshotID] = c.DeleteSnapshot(ctx, snapshotID)
+	}
+	return errs, nil
+}
+
+func (c *fakeCloudProvider) ModifySnapshotTags(ctx context.Context, snapshotID string, addOrUpdate map[string]string, remove []string) error {
+	s, ok := c.snapshots[snapshotID]
+	if !ok {
+		return cloud.ErrNotFound
+	}
+	if s.tags == nil {
+		s.tags = map[string]string{}
+	}
+	for k, v := range addOrUpdate {
+		s.tags[k] = v
+	}
+	for _, k := range remove {
+		delete(s.tags, k)
+	}
+	return nil
+}
+
 func (c *fakeCloudProvider) GetSnapshotByName(ctx context.Context, name string) (snapshot *cloud.Snapshot, err error) {
 	var snapshots []*fakeSnapshot
 	for _, s := range c.snapshots {
@@ -225,6 +396,10 @@ func (c *fakeCloudProvider) GetSnapshotByName(ctx context.Context, name string)
 	return snapshots[0].Snapshot, nil
 }
 
+func (c *fakeCloudProvider) GetNewestSnapshotByName(ctx context.Context, name string) (snapshot *cloud.Snapshot, err error) {
+	return c.GetSnapshotByName(ctx, name)
+}
+
 func (c *fakeCloudProvider) GetSnapshotByID(ctx context.Context, snapshotID string) (snapshot *cloud.Snapshot, err error) {
 	ret, exists := c.snapshots[snapshotID]
 	if !exists {
@@ -234,6 +409,19 @@ func (c *fakeCloudProvider) GetSnapshotByID(ctx context.Context, snapshotID stri
 	return ret.Snapshot, nil
 }
 
+func (c *fakeCloudProvider) WaitForSnapshotCompleted(ctx context.Context, snapshotID string) (snapshot *cloud.Snapshot, err error) {
+	return c.GetSnapshotByID(ctx, snapshotID)
+}
+
+func (c *fakeCloudProvider) GetSnapshotActualSize(ctx context.Context, snapshotID string) (int64, error) {
+	ret, exists := c.snapshots[snapshotID]
+	if !exists {
+		return 0, cloud.ErrNotFound
+	}
+
+	return ret.Size, nil
+}
+
 func (c *fakeCloudProvider) ListSnapshots(ctx context.Context, volumeID string, maxResults int64, nextToken string) (listSnapshotsResponse *cloud.ListSnapshotsResponse, err error) {
 	var snapshots []*cloud.Snapshot
 	var retToken string
@@ -259,6 +447,20 @@ func (c *fakeCloudProvider) ListSnapshots(ctx context.Context, volumeID string,
 
 }
 
+func (c *fakeCloudProvider) ListSnapshotsWithOptions(ctx context.Context, options *cloud.ListSnapshotsOptions) (*cloud.ListSnapshotsResponse, error) {
+	return c.ListSnapshots(ctx, options.VolumeID, options.MaxResults, options.NextToken)
+}
+
+func (c *fakeCloudProvider) ListSnapshotsOlderThan(ctx context.Context, cutoff time.Time, tagFilters map[string]string) ([]*cloud.Snapshot, error) {
+	var snapshots []*cloud.Snapshot
+	for _, fakeSnapshot := range c.snapshots {
+		if fakeSnapshot.Snapshot.CreationTime.Before(cutoff) {
+			snapshots = append(snapshots, fakeSnapshot.Snapshot)
+		}
+	}
+	return snapshots, nil
+}
+
 func (c *fakeCloudProvider) ResizeDisk(ctx context.Context, volumeID string, newSize int64) (int64, error) {
 	for volName, f := range c.disks {
 		if f.Disk.VolumeID == volumeID {
@@ -269,6 +471,10 @@ func (c *fakeCloudProvider) ResizeDisk(ctx context.Context, volumeID string, new
 	return 0, cloud.ErrNotFound
 }
 
+func (c *fakeCloudProvider) ModifyDisk(ctx context.Context, volumeID string, options *cloud.ModifyOptions) (int64, error) {
+	return c.ResizeDisk(ctx, volumeID, options.SizeBytes)
+}
+
 type fakeMounter struct {
 	exec.Interface
 }
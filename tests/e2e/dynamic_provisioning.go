@@ -402,6 +402,35 @@ var _ = Describe("[ebs-csi-e2e] [single-az] Dynamic Provisioning", func() {
 		}
 		test.Run(cs, ns)
 	})
+
+	It("should resize a volume on demand while it is in use and keep its data", func() {
+		allowVolumeExpansion := true
+		pod := testsuites.PodDetails{
+			Cmd: "echo 'hello world' > /mnt/test-1/data && sync && while true; do sleep 1; done",
+			Volumes: []testsuites.VolumeDetails{
+				{
+					VolumeType:           awscloud.VolumeTypeGP2,
+					FSType:               ebscsidriver.FSTypeExt4,
+					ClaimSize:            driver.MinimumSizeForVolumeType(awscloud.VolumeTypeGP2),
+					AllowVolumeExpansion: &allowVolumeExpansion,
+					VolumeMount: testsuites.VolumeMountDetails{
+						NameGenerate:      "test-volume-",
+						MountPathGenerate: "/mnt/test-",
+					},
+				},
+			},
+		}
+		test := testsuites.DynamicallyProvisionedResizeVolumeTest{
+			CSIDriver:        ebsDriver,
+			Pod:              pod,
+			ResizedClaimSize: "2Gi",
+			PodCheck: &testsuites.PodExecCheck{
+				Cmd:            []string{"cat", "/mnt/test-1/data"},
+				ExpectedString: "hello world\n",
+			},
+		}
+		test.Run(cs, ns)
+	})
 })
 
 var _ = Describe("[ebs-csi-e2e] [single-az] Snapshot", func() {
@@ -428,7 +457,7 @@ var _ = Describe("[ebs-csi-e2e] [single-az] Snapshot", func() {
 	It("should create a pod, write and read to it, take a volume snapshot, and create another pod from the snapshot", func() {
 		pod := testsuites.PodDetails{
 			// sync before taking a snapshot so that any cached data is written to the EBS volume
-			Cmd: "echo 'hello world' >> /mnt/test-1/data && grep 'hello world' /mnt/test-1/data && sync",
+			Cmd: "echo 'hello world' > /mnt/test-1/data && grep 'hello world' /mnt/test-1/data && sync",
 			Volumes: []testsuites.VolumeDetails{
 				{
 					VolumeType: awscloud.VolumeTypeGP2,
@@ -442,7 +471,8 @@ var _ = Describe("[ebs-csi-e2e] [single-az] Snapshot", func() {
 			},
 		}
 		restoredPod := testsuites.PodDetails{
-			Cmd: "grep 'hello world' /mnt/test-1/data",
+			// the restored file must hold exactly what was written before the snapshot
+			Cmd: "[ \"$(cat /mnt/test-1/data)\" = 'hello world' ]",
 			Volumes: []testsuites.VolumeDetails{
 				{
 					VolumeType: awscloud.VolumeTypeGP2,
@@ -536,6 +566,44 @@ var _ = Describe("[ebs-csi-e2e] [multi-az] Dynamic Provisioning", func() {
 		}
 		test.Run(cs, ns)
 	})
+
+	// Requires env AWS_AVAILABILITY_ZONES, a comma separated list of AZs
+	It("[env] should provision the volume in the zone of the node the pod is scheduled to", func() {
+		if os.Getenv(awsAvailabilityZonesEnv) == "" {
+			Skip(fmt.Sprintf("env %q not set", awsAvailabilityZonesEnv))
+		}
+		availabilityZones := strings.Split(os.Getenv(awsAvailabilityZonesEnv), ",")
+		availabilityZone := availabilityZones[rand.Intn(len(availabilityZones))]
+		region := availabilityZone[0 : len(availabilityZone)-1]
+		cloud, err := awscloud.NewCloud(region)
+		if err != nil {
+			Fail(fmt.Sprintf("could not get NewCloud: %v", err))
+		}
+
+		volumeBindingMode := storagev1.VolumeBindingWaitForFirstConsumer
+		pod := testsuites.PodDetails{
+			Cmd: "echo 'hello world' > /mnt/test-1/data && grep 'hello world' /mnt/test-1/data",
+			Volumes: []testsuites.VolumeDetails{
+				{
+					VolumeType:        awscloud.VolumeTypeGP2,
+					FSType:            ebscsidriver.FSTypeExt4,
+					ClaimSize:         driver.MinimumSizeForVolumeType(awscloud.VolumeTypeGP2),
+					VolumeBindingMode: &volumeBindingMode,
+					VolumeMount: testsuites.VolumeMountDetails{
+						NameGenerate:      "test-volume-",
+						MountPathGenerate: "/mnt/test-",
+					},
+				},
+			},
+		}
+		test := testsuites.DynamicallyProvisionedTopologyTest{
+			CSIDriver:        ebsDriver,
+			Pod:              pod,
+			AvailabilityZone: availabilityZone,
+			Cloud:            cloud,
+		}
+		test.Run(cs, ns)
+	})
 })
 
 func restClient(group string, version string) (restclientset.Interface, error) {
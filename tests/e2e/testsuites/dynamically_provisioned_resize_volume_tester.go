@@ -0,0 +1,71 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+   http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testsuites
+
+import (
+	"fmt"
+
+	"github.com/c2devel/aws-ebs-csi-driver/tests/e2e/driver"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	clientset "k8s.io/client-go/kubernetes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// DynamicallyProvisionedResizeVolumeTest will provision required StorageClass(es), PVC(s) and Pod(s)
+// Waiting for the PV provisioner to create a new PV
+// Testing if the Pod can write to the mounted volume
+// Resizing the PVC while the Pod is running, waiting for the PV and the file system to be resized
+// Testing if the data written before the resize is still on the volume
+// This test only supports a single volume
+type DynamicallyProvisionedResizeVolumeTest struct {
+	CSIDriver        driver.DynamicPVTestDriver
+	Pod              PodDetails
+	ResizedClaimSize string
+	PodCheck         *PodExecCheck
+}
+
+func (t *DynamicallyProvisionedResizeVolumeTest) Run(client clientset.Interface, namespace *v1.Namespace) {
+	volume := t.Pod.Volumes[0]
+	tpvc, cleanup := volume.SetupDynamicPersistentVolumeClaim(client, namespace, t.CSIDriver)
+	for i := range cleanup {
+		defer cleanup[i]()
+	}
+	mountPath := fmt.Sprintf("%s%d", volume.VolumeMount.MountPathGenerate, 1)
+	tpod := NewTestPod(client, namespace, t.Pod.Cmd)
+	tpod.SetupVolume(tpvc.persistentVolumeClaim, fmt.Sprintf("%s%d", volume.VolumeMount.NameGenerate, 1), mountPath, volume.VolumeMount.ReadOnly)
+
+	By("deploying the pod")
+	tpod.Create()
+	defer tpod.Cleanup()
+	By("checking that the pod is running")
+	tpod.WaitForRunning()
+
+	tpvc.Resize(t.ResizedClaimSize)
+	tpvc.WaitForResize(t.ResizedClaimSize)
+
+	By("checking that the file system in the pod is resized")
+	// The file system is a bit smaller than the volume, but must now be larger than the volume before the resize
+	claimSize := resource.MustParse(volume.ClaimSize)
+	Expect(tpod.FileSystemSize(mountPath)).To(BeNumerically(">", claimSize.Value()))
+
+	if t.PodCheck != nil {
+		By("checking that the data written before the resize is intact")
+		tpod.Exec(t.PodCheck.Cmd, t.PodCheck.ExpectedString)
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+   http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testsuites
+
+import (
+	"context"
+	"fmt"
+
+	awscloud "github.com/c2devel/aws-ebs-csi-driver/pkg/cloud"
+	ebscsidriver "github.com/c2devel/aws-ebs-csi-driver/pkg/driver"
+	"github.com/c2devel/aws-ebs-csi-driver/tests/e2e/driver"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/kubernetes/test/e2e/framework"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// DynamicallyProvisionedTopologyTest will provision required StorageClass(es), PVC(s) and a Pod
+// The Pod is scheduled with node affinity to the given availability zone
+// Waiting for the PV provisioner to create a new PV once the Pod is scheduled
+// Validate the PV nodeAffinity and the zone of the EBS volume match the zone of the Pod's node
+// This test only supports a single volume, its StorageClass must use the WaitForFirstConsumer binding mode
+type DynamicallyProvisionedTopologyTest struct {
+	CSIDriver        driver.DynamicPVTestDriver
+	Pod              PodDetails
+	AvailabilityZone string
+	Cloud            awscloud.Cloud
+}
+
+func (t *DynamicallyProvisionedTopologyTest) Run(client clientset.Interface, namespace *v1.Namespace) {
+	volume := t.Pod.Volumes[0]
+	tpvc, cleanup := volume.SetupDynamicPersistentVolumeClaim(client, namespace, t.CSIDriver)
+	for i := range cleanup {
+		defer cleanup[i]()
+	}
+	tpod := NewTestPod(client, namespace, t.Pod.Cmd)
+	tpod.SetupVolume(tpvc.persistentVolumeClaim, fmt.Sprintf("%s%d", volume.VolumeMount.NameGenerate, 1), fmt.Sprintf("%s%d", volume.VolumeMount.MountPathGenerate, 1), volume.VolumeMount.ReadOnly)
+	tpod.SetNodeAffinity(v1.LabelZoneFailureDomain, []string{t.AvailabilityZone})
+
+	By(fmt.Sprintf("deploying the pod in zone %q", t.AvailabilityZone))
+	tpod.Create()
+	defer tpod.Cleanup()
+	By("checking that the pods command exits with no error")
+	tpod.WaitForSuccess()
+
+	By("getting the zone of the pod's node")
+	pod, err := client.CoreV1().Pods(namespace.Name).Get(tpod.pod.Name, metav1.GetOptions{})
+	framework.ExpectNoError(err)
+	node, err := client.CoreV1().Nodes().Get(pod.Spec.NodeName, metav1.GetOptions{})
+	framework.ExpectNoError(err)
+	nodeZone := node.Labels[v1.LabelZoneFailureDomain]
+	Expect(nodeZone).To(Equal(t.AvailabilityZone))
+
+	tpvc.WaitForBound()
+	tpvc.ValidateProvisionedPersistentVolume()
+
+	By("checking the PV nodeAffinity matches the zone of the pod's node")
+	nodeAffinity := tpvc.persistentVolume.Spec.NodeAffinity
+	Expect(nodeAffinity).NotTo(BeNil())
+	Expect(nodeAffinity.Required).NotTo(BeNil())
+	Expect(nodeAffinity.Required.NodeSelectorTerms).To(HaveLen(1))
+	Expect(nodeAffinity.Required.NodeSelectorTerms[0].MatchExpressions).To(ConsistOf(v1.NodeSelectorRequirement{
+		Key:      ebscsidriver.TopologyKey,
+		Operator: v1.NodeSelectorOpIn,
+		Values:   []string{nodeZone},
+	}))
+
+	volumeID := tpvc.persistentVolume.Spec.CSI.VolumeHandle
+	By(fmt.Sprintf("checking EBS volume %q is in the zone of the pod's node", volumeID))
+	disk, err := t.Cloud.GetDiskByID(context.Background(), volumeID)
+	framework.ExpectNoError(err)
+	Expect(disk.AvailabilityZone).To(Equal(nodeZone))
+}
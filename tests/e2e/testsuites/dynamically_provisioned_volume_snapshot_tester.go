@@ -27,8 +27,9 @@ import (
 // DynamicallyProvisionedVolumeSnapshotTest will provision required StorageClass(es),VolumeSnapshotClass(es), PVC(s) and Pod(s)
 // Waiting for the PV provisioner to create a new PV
 // Testing if the Pod(s) can write and read to mounted volumes
-// Create a snapshot, validate the data is still on the disk, and then write and read to it again
-// And finally delete the snapshot
+// Create a snapshot, validate that it is backed by an EBS snapshot
+// Restore the snapshot to a new PVC and check that the data written before the snapshot is on it
+// And finally delete the snapshot and its VolumeSnapshotContent
 // This test only supports a single volume
 type DynamicallyProvisionedVolumeSnapshotTest struct {
 	CSIDriver   driver.PVTestDriver
@@ -58,6 +59,7 @@ func (t *DynamicallyProvisionedVolumeSnapshotTest) Run(client clientset.Interfac
 	snapshot := tvsc.CreateSnapshot(tpvc.persistentVolumeClaim)
 	defer tvsc.DeleteSnapshot(snapshot)
 	tvsc.ReadyToUse(snapshot)
+	tvsc.ValidateSnapshotContent(snapshot)
 
 	t.RestoredPod.Volumes[0].DataSource = &DataSource{Name: snapshot.Name}
 	trpod := NewTestPod(client, namespace, t.RestoredPod.Cmd)
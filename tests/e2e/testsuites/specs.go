@@ -41,6 +41,7 @@ type VolumeDetails struct {
 	ReclaimPolicy         *v1.PersistentVolumeReclaimPolicy
 	VolumeBindingMode     *storagev1.VolumeBindingMode
 	AllowedTopologyValues []string
+	AllowVolumeExpansion  *bool
 	VolumeMode            VolumeMode
 	VolumeMount           VolumeMountDetails
 	VolumeDevice          VolumeDeviceDetails
@@ -139,6 +140,7 @@ func (volume *VolumeDetails) SetupDynamicPersistentVolumeClaim(client clientset.
 	cleanupFuncs := make([]func(), 0)
 	By("setting up the StorageClass")
 	storageClass := csiDriver.GetDynamicProvisionStorageClass(driver.GetParameters(volume.VolumeType, volume.FSType, volume.Encrypted), volume.MountOptions, volume.ReclaimPolicy, volume.VolumeBindingMode, volume.AllowedTopologyValues, namespace.Name)
+	storageClass.AllowVolumeExpansion = volume.AllowVolumeExpansion
 	tsc := NewTestStorageClass(client, namespace, storageClass)
 	createdStorageClass := tsc.Create()
 	cleanupFuncs = append(cleanupFuncs, tsc.Cleanup)
@@ -18,6 +18,8 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/kubernetes-csi/external-snapshotter/v2/pkg/apis/volumesnapshot/v1beta1"
@@ -140,13 +142,40 @@ func (t *TestVolumeSnapshotClass) ReadyToUse(snapshot *v1beta1.VolumeSnapshot) {
 	framework.ExpectNoError(err)
 }
 
+// ValidateSnapshotContent checks that the snapshot is bound to a VolumeSnapshotContent
+// backed by an EBS snapshot, and returns it
+func (t *TestVolumeSnapshotClass) ValidateSnapshotContent(snapshot *v1beta1.VolumeSnapshot) *v1beta1.VolumeSnapshotContent {
+	By("validating the VolumeSnapshotContent of " + snapshot.Name)
+	c := snapshotclientset.New(t.client).SnapshotV1beta1()
+	vs, err := c.VolumeSnapshots(t.namespace.Name).Get(snapshot.Name, metav1.GetOptions{})
+	framework.ExpectNoError(err)
+	Expect(vs.Status).NotTo(BeNil())
+	Expect(vs.Status.BoundVolumeSnapshotContentName).NotTo(BeNil())
+
+	vsc, err := c.VolumeSnapshotContents().Get(*vs.Status.BoundVolumeSnapshotContentName, metav1.GetOptions{})
+	framework.ExpectNoError(err)
+	Expect(vsc.Status).NotTo(BeNil())
+	Expect(vsc.Status.SnapshotHandle).NotTo(BeNil())
+	Expect(*vsc.Status.SnapshotHandle).To(HavePrefix("snap-"))
+	return vsc
+}
+
 func (t *TestVolumeSnapshotClass) DeleteSnapshot(vs *v1beta1.VolumeSnapshot) {
 	By("deleting a VolumeSnapshot " + vs.Name)
-	err := snapshotclientset.New(t.client).SnapshotV1beta1().VolumeSnapshots(t.namespace.Name).Delete(vs.Name, &metav1.DeleteOptions{})
+	c := snapshotclientset.New(t.client).SnapshotV1beta1()
+	vs, err := c.VolumeSnapshots(t.namespace.Name).Get(vs.Name, metav1.GetOptions{})
+	framework.ExpectNoError(err)
+	err = c.VolumeSnapshots(t.namespace.Name).Delete(vs.Name, &metav1.DeleteOptions{})
 	framework.ExpectNoError(err)
 
 	err = t.waitForSnapshotDeleted(t.namespace.Name, vs.Name, 5*time.Second, 5*time.Minute)
 	framework.ExpectNoError(err)
+
+	// The VolumeSnapshotClass deletion policy is Delete, the content and the EBS snapshot must go too
+	if vs.Status != nil && vs.Status.BoundVolumeSnapshotContentName != nil {
+		err = t.waitForSnapshotContentDeleted(*vs.Status.BoundVolumeSnapshotContentName, 5*time.Second, 5*time.Minute)
+		framework.ExpectNoError(err)
+	}
 }
 
 func (t *TestVolumeSnapshotClass) Cleanup() {
@@ -171,6 +200,22 @@ func (t *TestVolumeSnapshotClass) waitForSnapshotDeleted(ns string, snapshotName
 	return fmt.Errorf("VolumeSnapshot %s is not removed from the system within %v", snapshotName, timeout)
 }
 
+func (t *TestVolumeSnapshotClass) waitForSnapshotContentDeleted(contentName string, poll, timeout time.Duration) error {
+	e2elog.Logf("Waiting up to %v for VolumeSnapshotContent %s to be removed", timeout, contentName)
+	c := snapshotclientset.New(t.client).SnapshotV1beta1()
+	for start := time.Now(); time.Since(start) < timeout; time.Sleep(poll) {
+		_, err := c.VolumeSnapshotContents().Get(contentName, metav1.GetOptions{})
+		if err != nil {
+			if apierrs.IsNotFound(err) {
+				e2elog.Logf("VolumeSnapshotContent %q doesn't exist in the system", contentName)
+				return nil
+			}
+			e2elog.Logf("Failed to get VolumeSnapshotContent %q, retrying in %v. Error: %v", contentName, poll, err)
+		}
+	}
+	return fmt.Errorf("VolumeSnapshotContent %s is not removed from the system within %v", contentName, timeout)
+}
+
 type TestPreProvisionedPersistentVolume struct {
 	client                    clientset.Interface
 	persistentVolume          *v1.PersistentVolume
@@ -302,6 +347,45 @@ func (t *TestPersistentVolumeClaim) WaitForBound() v1.PersistentVolumeClaim {
 	return *t.persistentVolumeClaim
 }
 
+// Resize requests the new size for the PVC, its StorageClass must allow volume expansion
+func (t *TestPersistentVolumeClaim) Resize(claimSize string) {
+	By(fmt.Sprintf("resizing the PVC to %s", claimSize))
+	pvc, err := t.client.CoreV1().PersistentVolumeClaims(t.namespace.Name).Get(t.persistentVolumeClaim.Name, metav1.GetOptions{})
+	framework.ExpectNoError(err)
+	pvc.Spec.Resources.Requests[v1.ResourceName(v1.ResourceStorage)] = resource.MustParse(claimSize)
+	t.persistentVolumeClaim, err = t.client.CoreV1().PersistentVolumeClaims(t.namespace.Name).Update(pvc)
+	framework.ExpectNoError(err)
+}
+
+// WaitForResize waits for the PV to be resized, then for the file system on it to be resized,
+// which kubelet reports in the PVC capacity
+func (t *TestPersistentVolumeClaim) WaitForResize(claimSize string) {
+	expectedCapacity := resource.MustParse(claimSize)
+
+	By(fmt.Sprintf("waiting for the PV to be resized to %s", claimSize))
+	err := wait.PollImmediate(5*time.Second, 5*time.Minute, func() (bool, error) {
+		pv, err := t.client.CoreV1().PersistentVolumes().Get(t.persistentVolumeClaim.Spec.VolumeName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		pvCapacity := pv.Spec.Capacity[v1.ResourceName(v1.ResourceStorage)]
+		return pvCapacity.Cmp(expectedCapacity) >= 0, nil
+	})
+	framework.ExpectNoError(err)
+
+	By(fmt.Sprintf("waiting for the file system to be resized to %s", claimSize))
+	err = wait.PollImmediate(5*time.Second, 5*time.Minute, func() (bool, error) {
+		pvc, err := t.client.CoreV1().PersistentVolumeClaims(t.namespace.Name).Get(t.persistentVolumeClaim.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		t.persistentVolumeClaim = pvc
+		claimCapacity := pvc.Status.Capacity[v1.ResourceName(v1.ResourceStorage)]
+		return claimCapacity.Cmp(expectedCapacity) >= 0, nil
+	})
+	framework.ExpectNoError(err)
+}
+
 func generatePVC(namespace, storageClassName, claimSize string, volumeMode v1.PersistentVolumeMode, dataSource *v1.TypedLocalObjectReference) *v1.PersistentVolumeClaim {
 	return &v1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
@@ -557,6 +641,20 @@ func (t *TestPod) WaitForRunning() {
 	framework.ExpectNoError(err)
 }
 
+func (t *TestPod) Exec(command []string, expectedString string) {
+	_, err := framework.LookForStringInPodExec(t.namespace.Name, t.pod.Name, command, expectedString, execTimeout)
+	framework.ExpectNoError(err)
+}
+
+// FileSystemSize returns the size in bytes of the file system mounted at mountPath in the pod
+func (t *TestPod) FileSystemSize(mountPath string) int64 {
+	out, err := framework.RunHostCmd(t.namespace.Name, t.pod.Name, fmt.Sprintf("df -kP %s | tail -n 1 | awk '{print $2}'", mountPath))
+	framework.ExpectNoError(err)
+	sizeKiB, err := strconv.ParseInt(strings.TrimSpace(out), 10, 64)
+	framework.ExpectNoError(err)
+	return sizeKiB * 1024
+}
+
 // Ideally this would be in "k8s.io/kubernetes/test/e2e/framework"
 // Similar to framework.WaitForPodSuccessInNamespaceSlow
 var podFailedCondition = func(pod *v1.Pod) (bool, error) {
@@ -617,6 +715,27 @@ func (t *TestPod) SetNodeSelector(nodeSelector map[string]string) {
 	t.pod.Spec.NodeSelector = nodeSelector
 }
 
+// SetNodeAffinity requires the pod to be scheduled on a node with the label key set to one of the values
+func (t *TestPod) SetNodeAffinity(key string, values []string) {
+	t.pod.Spec.Affinity = &v1.Affinity{
+		NodeAffinity: &v1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+				NodeSelectorTerms: []v1.NodeSelectorTerm{
+					{
+						MatchExpressions: []v1.NodeSelectorRequirement{
+							{
+								Key:      key,
+								Operator: v1.NodeSelectorOpIn,
+								Values:   values,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func (t *TestPod) Cleanup() {
 	cleanupPodOrFail(t.client, t.pod.Name, t.namespace.Name)
 }